@@ -0,0 +1,179 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrUnsupportedPDFA is returned for PDF/A formats Gotenberg does not produce.
+var ErrUnsupportedPDFA = errors.New("gotenberg: unsupported PDF/A format")
+
+// Archival configures the request for long-term archiving: the PDF is converted
+// to format (PDFA1b, PDFA2b or PDFA3b), flattened, so form fields and
+// annotations become static content, and stamped with metadata as by
+// MetadataEntries. metadata may be nil to set none.
+//
+// E-invoicing standards such as ZUGFeRD / Factur-X require PDFA3b with the
+// structured invoice embedded with Embed:
+//
+//	client.ConvertTemplate(ctx, templates.Invoice, data).
+//		Archival(gotenberg.PDFA3b, gotenberg.PDFMetadata{Title: "Invoice 42", Author: "ACME", Subject: "Invoice"}).
+//		Embed("factur-x.xml", xml).
+//		Send()
+func (r *Request) Archival(format string, metadata any) *Request {
+	return r.PDFA(format).
+		Flatten(true).
+		MetadataEntries(metadata)
+}
+
+// PDFA sets the PDF/A format (PDFA1b, PDFA2b or PDFA3b) of the generated PDF,
+// directly on the Chromium and LibreOffice routes, without a round trip
+// through ConvertPDF:
+//
+//	client.ConvertHTML(ctx, html).
+//		PDFA(gotenberg.PDFA3b).
+//		PDFUA(true).
+//		Send()
+//
+// Unlike Archival, form fields and annotations are kept.
+func (r *Request) PDFA(format string) *Request {
+	if err := validatePDFA(format); err != nil {
+		r.setErr(err)
+		return r
+	}
+	if r.attachments && format != PDFA3b {
+		r.setErr(fmt.Errorf("%w: attached files require %s, not %s", ErrUnsupportedPDFA, PDFA3b, format))
+		return r
+	}
+	r.archival = format
+	return r.Param(FieldPDFA, format)
+}
+
+// PDFUA sets whether the generated PDF is PDF/UA compliant, for universal
+// accessibility. See Accessible for the metadata PDF/UA requires.
+func (r *Request) PDFUA(enabled bool) *Request {
+	return r.Bool(FieldPDFUA, enabled)
+}
+
+// Embed attaches content as the file filename embedded in the generated PDF,
+// e.g. the XML invoice of a ZUGFeRD document. PDF/A-1b and PDF/A-2b do not
+// allow arbitrary embedded files; use PDFA3b with Archival.
+func (r *Request) Embed(filename string, content io.Reader) *Request {
+	return r.File(FieldEmbeds, filename, content)
+}
+
+// AttachFile attaches content as the file name of a PDF/A-3b document, e.g. the
+// XML payload of an e-invoice or the source data of a report. Requests not yet
+// archival become Archival(PDFA3b, nil); other PDF/A formats fail, as only PDF/A-3
+// allows arbitrary attachments.
+//
+// Existing PDFs get attachments by a round trip through ArchivePDF:
+//
+//	client.ArchivePDF(ctx, invoicePDF).
+//		AttachFile("factur-x.xml", xml).
+//		Send()
+func (r *Request) AttachFile(name string, content io.Reader) *Request {
+	switch r.archival {
+	case "":
+		r.Archival(PDFA3b, nil)
+	case PDFA3b:
+	default:
+		r.setErr(fmt.Errorf("%w: attached files require %s, not %s", ErrUnsupportedPDFA, PDFA3b, r.archival))
+		return r
+	}
+	r.attachments = true
+	return r.Embed(name, content)
+}
+
+// ArchivePDF creates a request converting an existing PDF to PDF/A-3b through
+// Gotenberg's pdfengines convert route, e.g. to add attachments with AttachFile.
+// Attachments on this route require a Gotenberg version supporting the embeds
+// field on the pdfengines routes.
+func (c *Client) ArchivePDF(ctx context.Context, pdf io.Reader) *Request {
+	return c.ConvertPDF(ctx, PDFConversion{PDFA: PDFA3b}, NamedReader{Name: "document.pdf", Reader: pdf})
+}
+
+// PDFConversion is the target format of ConvertPDF: a PDF/A format (PDFA1b,
+// PDFA2b or PDFA3b), PDF/UA, or both.
+type PDFConversion struct {
+	PDFA  string
+	PDFUA bool
+}
+
+// ConvertPDF creates a request converting existing PDFs to PDF/A and/or
+// PDF/UA through Gotenberg's pdfengines convert route, e.g. for archival
+// compliance. Several PDFs produce a zip, see Response.EachDocument.
+//
+//	client.ConvertPDF(ctx, gotenberg.PDFConversion{PDFA: gotenberg.PDFA2b, PDFUA: true},
+//		gotenberg.NamedReader{Name: "report.pdf", Reader: pdf}).
+//		Send()
+func (c *Client) ConvertPDF(ctx context.Context, conversion PDFConversion, pdfs ...NamedReader) *Request {
+	r := c.newRequest(ctx, ConvertPDF)
+	switch {
+	case len(pdfs) == 0:
+		r.setErr(ErrNoDocuments)
+	case conversion.PDFA == "" && !conversion.PDFUA:
+		r.setErr(fmt.Errorf("%w: no PDF/A format or PDF/UA requested", ErrUnsupportedPDFA))
+	}
+	for _, pdf := range pdfs {
+		r.File(FieldFiles, pdf.Name, pdf.Reader)
+	}
+	if conversion.PDFA != "" {
+		r.PDFA(conversion.PDFA)
+	}
+	if conversion.PDFUA {
+		r.PDFUA(true)
+	}
+	return r
+}
+
+// ArchiveOffice converts doc with LibreOffice and pipes the PDF through
+// Gotenberg's pdfengines convert route to conversion, for archival ingestion
+// of office documents. The intermediate PDF is streamed from the first
+// response into the second request, never buffered. configure, if not nil,
+// sets the options of the LibreOffice request, e.g. Password.
+//
+//	pdf, err := client.ArchiveOffice(ctx, gotenberg.PDFConversion{PDFA: gotenberg.PDFA2b},
+//		gotenberg.NamedReader{Name: "contract.docx", Reader: docx}, nil)
+//
+// Unlike PDFA on ConvertOffice, the PDF/A conversion is done by the PDF
+// engines rather than LibreOffice. Errors of either conversion are returned as
+// *GotenbergError; the caller closes the returned document.
+func (c *Client) ArchiveOffice(ctx context.Context, conversion PDFConversion, doc NamedReader, configure func(*Request)) (io.ReadCloser, error) {
+	office := c.ConvertOffice(ctx, doc)
+	if configure != nil {
+		configure(office)
+	}
+	resp, err := office.Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	// the second request reads the first response while uploading it
+	defer resp.Body.Close()
+
+	name := strings.TrimSuffix(doc.Name, path.Ext(doc.Name)) + ".pdf"
+	archived, err := c.ConvertPDF(ctx, conversion, NamedReader{Name: name, Reader: resp.Body}).Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := archived.Err(); err != nil {
+		return nil, err
+	}
+	return archived.Body, nil
+}
+
+// validatePDFA checks that Gotenberg produces the PDF/A format.
+func validatePDFA(format string) error {
+	switch format {
+	case PDFA1b, PDFA2b, PDFA3b:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedPDFA, format)
+}
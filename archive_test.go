@@ -0,0 +1,204 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestArchival(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Archival(PDFA3b, PDFMetadata{Title: "Invoice 42", Author: "ACME"}).
+		Embed("factur-x.xml", strings.NewReader("<Invoice/>")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if got := capture.value(FieldPDFA); got != PDFA3b {
+		t.Errorf("unexpected pdfa %q", got)
+	}
+	if got := capture.value(FieldFlatten); got != "true" {
+		t.Errorf("unexpected flatten %q", got)
+	}
+	if fh := capture.form.File[FieldEmbeds]; len(fh) != 1 || fh[0].Filename != "factur-x.xml" {
+		t.Errorf("embedded file not attached: %v", capture.form.File)
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal([]byte(capture.value(FieldMetadata)), &metadata); err != nil || metadata["Title"] != "Invoice 42" || metadata["Author"] != "ACME" {
+		t.Errorf("unexpected metadata %q: %v", capture.value(FieldMetadata), err)
+	}
+}
+
+func TestArchivalUnsupportedFormat(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Archival("PDF/A-4", nil).
+		Send()
+	if !errors.Is(err, ErrUnsupportedPDFA) {
+		t.Errorf("expected ErrUnsupportedPDFA, got %v", err)
+	}
+}
+
+func TestPDFAAndPDFUA(t *testing.T) {
+	for _, convert := range []func(c *Client) *Request{
+		func(c *Client) *Request {
+			return c.ConvertHTML(context.Background(), strings.NewReader("<html></html>"))
+		},
+		func(c *Client) *Request { return c.ConvertURL(context.Background(), "https://example.com") },
+	} {
+		c, capture := newCaptureClient(t)
+		if _, err := convert(c).PDFA(PDFA2b).PDFUA(true).Send(); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if capture.value(FieldPDFA) != PDFA2b || capture.value(FieldPDFUA) != "true" {
+			t.Errorf("%s: unexpected pdfa %q, pdfua %q", capture.path, capture.value(FieldPDFA), capture.value(FieldPDFUA))
+		}
+		if got := capture.value(FieldFlatten); got != "" {
+			t.Errorf("%s: unexpected flatten %q", capture.path, got)
+		}
+	}
+
+	c, _ := newCaptureClient(t)
+	err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).PDFA("PDF/A-4").Validate()
+	if !errors.Is(err, ErrUnsupportedPDFA) {
+		t.Errorf("expected ErrUnsupportedPDFA, got %v", err)
+	}
+	err = c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		AttachFile("data.xml", strings.NewReader("<Data/>")).
+		PDFA(PDFA2b).
+		Validate()
+	if !errors.Is(err, ErrUnsupportedPDFA) {
+		t.Errorf("expected ErrUnsupportedPDFA for attachments, got %v", err)
+	}
+}
+
+func TestAttachFile(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		AttachFile("invoice.xml", strings.NewReader("<Invoice/>")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldPDFA); got != PDFA3b {
+		t.Errorf("expected attachments to select %s, got %q", PDFA3b, got)
+	}
+	if fh := capture.form.File[FieldEmbeds]; len(fh) != 1 || fh[0].Filename != "invoice.xml" {
+		t.Errorf("attachment not embedded: %v", capture.form.File)
+	}
+
+	_, err = c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Archival(PDFA2b, nil).
+		AttachFile("invoice.xml", strings.NewReader("<Invoice/>")).
+		Send()
+	if !errors.Is(err, ErrUnsupportedPDFA) {
+		t.Errorf("expected ErrUnsupportedPDFA for PDF/A-2b, got %v", err)
+	}
+}
+
+func TestArchivePDF(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ArchivePDF(context.Background(), strings.NewReader("%PDF")).
+		AttachFile("invoice.xml", strings.NewReader("<Invoice/>")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != ConvertPDF || capture.value(FieldPDFA) != PDFA3b {
+		t.Errorf("unexpected request %s pdfa=%q", capture.path, capture.value(FieldPDFA))
+	}
+	if capture.files["document.pdf"] != "%PDF" || capture.files["invoice.xml"] != "<Invoice/>" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+}
+
+func TestConvertPDF(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertPDF(context.Background(), PDFConversion{PDFA: PDFA2b, PDFUA: true},
+		NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF report")},
+		NamedReader{Name: "annex.pdf", Reader: strings.NewReader("%PDF annex")}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != ConvertPDF || capture.value(FieldPDFA) != PDFA2b || capture.value(FieldPDFUA) != "true" {
+		t.Errorf("unexpected request %s %v", capture.path, capture.form.Value)
+	}
+	if capture.files["report.pdf"] != "%PDF report" || capture.files["annex.pdf"] != "%PDF annex" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+
+	_, err = c.ConvertPDF(context.Background(), PDFConversion{PDFUA: true},
+		NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF")}).
+		Send()
+	if err != nil || capture.value(FieldPDFA) != "" || capture.value(FieldPDFUA) != "true" {
+		t.Errorf("unexpected PDF/UA only request %v: %v", capture.form.Value, err)
+	}
+}
+
+func TestConvertPDFInvalid(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	pdf := NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF")}
+	tests := []struct {
+		conversion PDFConversion
+		pdfs       []NamedReader
+		want       error
+	}{
+		{PDFConversion{PDFA: PDFA2b}, nil, ErrNoDocuments},
+		{PDFConversion{}, []NamedReader{pdf}, ErrUnsupportedPDFA},
+		{PDFConversion{PDFA: "PDF/A-4"}, []NamedReader{pdf}, ErrUnsupportedPDFA},
+	}
+	for _, tt := range tests {
+		if _, err := c.ConvertPDF(context.Background(), tt.conversion, tt.pdfs...).Send(); !errors.Is(err, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.conversion, tt.want, err)
+		}
+	}
+}
+
+func TestArchiveOffice(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		fh := r.MultipartForm.File[FieldFiles][0]
+		f, _ := fh.Open()
+		content, _ := io.ReadAll(f)
+		switch {
+		case r.URL.Path == ConvertOffice && r.FormValue(FieldPassword) != "secret":
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"status": 400, "message": "document is encrypted"}`)
+		case r.URL.Path == ConvertOffice:
+			io.WriteString(w, "pdf("+string(content)+")")
+		case r.URL.Path == ConvertPDF:
+			io.WriteString(w, r.FormValue(FieldPDFA)+"("+fh.Filename+":"+string(content)+")")
+		}
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := func() NamedReader { return NamedReader{Name: "contract.docx", Reader: strings.NewReader("docx")} }
+	pdf, err := c.ArchiveOffice(context.Background(), PDFConversion{PDFA: PDFA2b}, doc(), func(r *Request) { r.Password("secret") })
+	if err != nil {
+		t.Fatalf("ArchiveOffice failed: %v", err)
+	}
+	defer pdf.Close()
+	if got, _ := io.ReadAll(pdf); string(got) != "PDF/A-2b(contract.pdf:pdf(docx))" {
+		t.Errorf("unexpected document %q", got)
+	}
+
+	var gerr *GotenbergError
+	if _, err := c.ArchiveOffice(context.Background(), PDFConversion{PDFA: PDFA2b}, doc(), nil); !errors.As(err, &gerr) || gerr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected the LibreOffice error, got %v", err)
+	}
+}
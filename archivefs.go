@@ -0,0 +1,85 @@
+package gotenberg
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ArchiveFS attaches every file of fsys as an asset of the request, e.g. the
+// images, stylesheets and fonts of a template embedded with go:embed:
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	sub, _ := fs.Sub(assets, "assets")
+//	client.ConvertHTML(ctx, index).ArchiveFS(sub).Send()
+//
+// Gotenberg keeps all files in one directory: files are attached under their
+// base name, which the pages must reference, and two files of the same name
+// fail the request with ErrInvalidFilename. Hidden files and directories,
+// e.g. .DS_Store, are skipped. The files are closed once the request is sent.
+func (r *Request) ArchiveFS(fsys fs.FS) *Request {
+	attached := make(map[string]string)
+	for _, f := range r.files {
+		if f.key == FieldFiles {
+			attached[f.filename] = f.filename
+		}
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && skippedAsset(d.Name()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		base := path.Base(name)
+		if other, ok := attached[base]; ok {
+			return fmt.Errorf("%w: %s and %s are both attached as %s", ErrInvalidFilename, other, name, base)
+		}
+		attached[base] = name
+		f, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		r.closers = append(r.closers, f)
+		r.File(FieldFiles, base, f)
+		return nil
+	})
+	if err != nil {
+		r.setErr(err)
+	}
+	return r
+}
+
+// ArchiveZip attaches the files of the zip archive zr as assets of the
+// request, as ArchiveFS. The archive is read into memory.
+func (r *Request) ArchiveZip(zr io.Reader) *Request {
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		r.setErr(fmt.Errorf("gotenberg: read asset archive: %w", err))
+		return r
+	}
+	return r.ArchiveFS(archive)
+}
+
+// skippedAsset reports whether the file or directory name is left out of
+// ArchiveFS: hidden files and the resource forks macOS adds to archives.
+func skippedAsset(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "__MACOSX"
+}
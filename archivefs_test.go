@@ -0,0 +1,73 @@
+package gotenberg
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestArchiveFS(t *testing.T) {
+	assets := fstest.MapFS{
+		"style.css":       {Data: []byte("body{}")},
+		"img/logo.png":    {Data: []byte("png")},
+		"img/.DS_Store":   {Data: []byte("junk")},
+		".git/config":     {Data: []byte("junk")},
+		"fonts/inter.ttf": {Data: []byte("ttf")},
+	}
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader(`<html><img src="logo.png"></html>`)).
+		ArchiveFS(assets).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	want := map[string]string{FileIndexHTML: `<html><img src="logo.png"></html>`, "style.css": "body{}", "logo.png": "png", "inter.ttf": "ttf"}
+	if len(capture.files) != len(want) {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+	for name, content := range want {
+		if capture.files[name] != content {
+			t.Errorf("%s = %q, want %q", name, capture.files[name], content)
+		}
+	}
+}
+
+func TestArchiveFSDuplicateNames(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	for _, assets := range []fstest.MapFS{
+		{"a/logo.png": {Data: []byte("a")}, "b/logo.png": {Data: []byte("b")}},
+		{"index.html": {Data: []byte("<html></html>")}},
+	} {
+		_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).ArchiveFS(assets).Send()
+		if !errors.Is(err, ErrInvalidFilename) {
+			t.Errorf("expected ErrInvalidFilename, got %v", err)
+		}
+	}
+}
+
+func TestArchiveZip(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	for name, content := range map[string]string{"css/print.css": "@page{}", "__MACOSX/css/._print.css": "junk"} {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).ArchiveZip(&archive).Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.files["print.css"] != "@page{}" || len(capture.files) != 2 {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+
+	if _, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).ArchiveZip(strings.NewReader("not a zip")).Send(); err == nil {
+		t.Error("expected an error for an invalid archive")
+	}
+}
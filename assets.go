@@ -0,0 +1,123 @@
+package gotenberg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrMissingAsset is returned by Send when the asset check finds references
+// to files that were not attached to the request.
+var ErrMissingAsset = errors.New("gotenberg: referenced asset not attached")
+
+// MissingAssetsError lists the local references without a matching attachment.
+type MissingAssetsError struct {
+	Names []string
+}
+
+func (e *MissingAssetsError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrMissingAsset, strings.Join(e.Names, ", "))
+}
+
+// Unwrap allows errors.Is(err, ErrMissingAsset).
+func (e *MissingAssetsError) Unwrap() error {
+	return ErrMissingAsset
+}
+
+var assetReference = regexp.MustCompile(`(?i)(?:\b(?:src|href)\s*=\s*["']([^"']+)["']|url\(\s*["']?([^"')]+)["']?\s*\))`)
+
+// CheckAssets enables a pre-send check of the local assets referenced by the attached
+// HTML and CSS files (src and href attributes, CSS url()). Send fails with a
+// *MissingAssetsError when a referenced file was not attached. Attachments nobody
+// references are passed to onUnused, if not nil.
+// The checked files are buffered in memory to be parsed.
+func (r *Request) CheckAssets(onUnused func(names []string)) *Request {
+	r.checkAssets = true
+	r.onUnusedAssets = onUnused
+	return r
+}
+
+// verifyAssets runs the asset check on the request files.
+func (r *Request) verifyAssets() error {
+	attached := make(map[string]bool)
+	referenced := make(map[string]bool)
+
+	for i, f := range r.files {
+		if f.key != FieldFiles {
+			continue
+		}
+		attached[f.filename] = true
+
+		ext := strings.ToLower(path.Ext(f.filename))
+		if ext != ".html" && ext != ".css" {
+			continue
+		}
+
+		data, err := io.ReadAll(r.fileContent(f))
+		if err != nil {
+			return err
+		}
+		// The cover page and the HTML suffix are now part of the buffered content
+		r.files[i].content = bytes.NewReader(data)
+		if f.filename == FileIndexHTML {
+			r.htmlSuffix = nil
+			r.coverHTML = ""
+		}
+
+		for _, name := range assetReferences(data) {
+			referenced[name] = true
+		}
+	}
+
+	var missing, unused []string
+	for name := range referenced {
+		if !attached[name] {
+			missing = append(missing, name)
+		}
+	}
+	for name := range attached {
+		switch name {
+		case FileIndexHTML, FileHeaderHTML, FileFooterHTML:
+			continue
+		}
+		if !referenced[name] {
+			unused = append(unused, name)
+		}
+	}
+
+	if len(unused) > 0 && r.onUnusedAssets != nil {
+		sort.Strings(unused)
+		r.onUnusedAssets(unused)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return &MissingAssetsError{Names: missing}
+	}
+	return nil
+}
+
+// assetReferences returns the local files referenced by an HTML or CSS document.
+// Absolute URLs, protocol-relative URLs, fragments and data URIs are ignored.
+func assetReferences(doc []byte) []string {
+	var names []string
+	for _, m := range assetReference.FindAllSubmatch(doc, -1) {
+		ref := string(m[1])
+		if ref == "" {
+			ref = string(m[2])
+		}
+		ref = strings.TrimSpace(ref)
+		if ref == "" || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "//") || strings.Contains(ref, ":") {
+			continue
+		}
+		if i := strings.IndexAny(ref, "?#"); i >= 0 {
+			ref = ref[:i]
+		}
+		names = append(names, path.Clean(ref))
+	}
+	return names
+}
@@ -0,0 +1,57 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssetReferences(t *testing.T) {
+	doc := `<img src="logo.png"><link href='./styles.css'><a href="#top"></a>
+<img src="https://example.com/x.png"><img src="data:image/png;base64,AA==">
+<div style="background: url(bg.jpg?v=1)"></div><script src="//cdn/x.js"></script>`
+	got := assetReferences([]byte(doc))
+	want := []string{"logo.png", "styles.css", "bg.jpg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCheckAssetsMissing(t *testing.T) {
+	c := newTestClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader(`<img src="logo.png"><img src="sig.png">`)).
+		File(FieldFiles, "logo.png", strings.NewReader("png")).
+		CheckAssets(nil).
+		Send()
+
+	var missing *MissingAssetsError
+	if !errors.As(err, &missing) || !errors.Is(err, ErrMissingAsset) {
+		t.Fatalf("expected MissingAssetsError, got %v", err)
+	}
+	if !reflect.DeepEqual(missing.Names, []string{"sig.png"}) {
+		t.Errorf("unexpected missing assets %v", missing.Names)
+	}
+}
+
+func TestCheckAssetsUnused(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	var unused []string
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader(`<link href="styles.css">`)).
+		File(FieldFiles, "styles.css", strings.NewReader(`body { background: url("bg.png") }`)).
+		File(FieldFiles, "bg.png", strings.NewReader("png")).
+		File(FieldFiles, "extra.png", strings.NewReader("png")).
+		WithFont("Inter.ttf", strings.NewReader("ttf")).
+		CheckAssets(func(names []string) { unused = names }).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !reflect.DeepEqual(unused, []string{"extra.png"}) {
+		t.Errorf("unexpected unused assets %v", unused)
+	}
+	if !strings.Contains(capture.files[FileIndexHTML], "@font-face") {
+		t.Errorf("font declaration lost: %q", capture.files[FileIndexHTML])
+	}
+}
@@ -0,0 +1,54 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditRecord describes a single conversion sent to Gotenberg.
+type AuditRecord struct {
+	Time       time.Time     `json:"time"`
+	Tenant     string        `json:"tenant,omitempty"`
+	Route      string        `json:"route"`
+	InputHash  string        `json:"input_hash"`
+	Trace      string        `json:"trace,omitempty"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	OutputSize int64         `json:"output_size"`
+}
+
+// AuditSink receives a record for every conversion performed by a Client.
+// Record is called synchronously from Request.Send and must be safe for concurrent use.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord)
+}
+
+// NopAuditSink discards all audit records. It is the default sink.
+type NopAuditSink struct{}
+
+// Record implements AuditSink.
+func (NopAuditSink) Record(context.Context, AuditRecord) {}
+
+// StorageAuditSink stores every audit record as a JSON object in Storage,
+// under Prefix/YYYY/MM/DD/<trace>.json.
+type StorageAuditSink struct {
+	Storage Storage
+	Prefix  string
+
+	// OnError is called when a record cannot be stored. Optional.
+	OnError func(record AuditRecord, err error)
+}
+
+// Record implements AuditSink.
+func (s *StorageAuditSink) Record(ctx context.Context, record AuditRecord) {
+	data, err := json.Marshal(record)
+	if err == nil {
+		_, err = s.Storage.UploadFile(ctx, datedObjectName(s.Prefix, record.Time, record.Trace), bytes.NewReader(data), int64(len(data)), "application/json")
+	}
+	if err != nil && s.OnError != nil {
+		s.OnError(record, err)
+	}
+}
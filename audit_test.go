@@ -0,0 +1,72 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+}
+
+func (s *recordingSink) Record(ctx context.Context, record AuditRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+}
+
+func TestAuditSinkRecordsConversion(t *testing.T) {
+	sink := &recordingSink{}
+	c, err := NewClient(&http.Client{Transport: &mockRoundTripper{}}, "http://localhost", WithAuditSink(sink))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	html := "<html></html>"
+	_, err = c.ConvertHTML(context.Background(), bytes.NewBufferString(html)).Tenant("acme").Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if len(sink.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(sink.records))
+	}
+	rec := sink.records[0]
+	sum := sha256.Sum256([]byte(html))
+	if rec.InputHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected input hash %s", rec.InputHash)
+	}
+	if rec.Route != ConvertHTML || rec.Tenant != "acme" || rec.Trace != "trace-id" || rec.StatusCode != 200 {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
+
+func TestStorageAuditSink(t *testing.T) {
+	storage := newMemoryStorage()
+	sink := &StorageAuditSink{Storage: storage, Prefix: "audit"}
+	sink.Record(context.Background(), AuditRecord{
+		Time:  time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		Route: ConvertURL,
+		Trace: "abc",
+	})
+
+	data, ok := storage.files["audit/2024/03/05/abc.json"]
+	if !ok {
+		t.Fatalf("record not stored, have %v", storage.files)
+	}
+	var rec AuditRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatalf("invalid record: %v", err)
+	}
+	if rec.Route != ConvertURL {
+		t.Errorf("unexpected route %s", rec.Route)
+	}
+}
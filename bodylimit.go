@@ -0,0 +1,32 @@
+package gotenberg
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBodySize is the request body limit of the MinioAPI upload and
+// conversion routes unless changed with WithBodyLimit.
+const DefaultMaxBodySize = 100 << 20
+
+// LimitBody wraps next so that request bodies larger than maxBytes are answered
+// with 413. Requests announcing a larger Content-Length are rejected before next
+// is called, other bodies fail with *http.MaxBytesError once the limit is read.
+// A limit of zero or less disables the check.
+func LimitBody(maxBytes int64, next http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			writeErrorResponse(w, r, http.StatusRequestEntityTooLarge, bodyTooLargeMessage(maxBytes))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bodyTooLargeMessage(limit int64) string {
+	return fmt.Sprintf("Request body exceeds %d bytes", limit)
+}
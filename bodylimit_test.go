@@ -0,0 +1,54 @@
+package gotenberg
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLimitBody(t *testing.T) {
+	mux := http.NewServeMux()
+	NewMinioAPI(nil, WithBodyLimit("/api/upload", 16)).RegisterRoutes(mux)
+
+	body, contentType := multipartForm(t, "<html>too large for the limit</html>")
+	req := httptest.NewRequest(http.MethodPost, "/api/upload", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for announced length, got %d", rec.Code)
+	}
+
+	// without Content-Length the limit is hit while parsing the form
+	body, contentType = multipartForm(t, "<html>too large for the limit</html>")
+	req = httptest.NewRequest(http.MethodPost, "/api/upload", io.MultiReader(body))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", contentType)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge || !strings.Contains(rec.Body.String(), string(CodePayloadTooLarge)) {
+		t.Errorf("expected 413 for streamed body, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLimitBodyProxy(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := LimitBody(16, NewProxy(c))
+
+	body, contentType := multipartForm(t, "<html>too large for the limit</html>")
+	req := httptest.NewRequest(http.MethodPost, ConvertHTML, io.MultiReader(body))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,143 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowReader returns one byte per Read, forever, counting the reads.
+type slowReader struct {
+	reads atomic.Int64
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	s.reads.Add(1)
+	time.Sleep(time.Millisecond)
+	p[0] = 'x'
+	return 1, nil
+}
+
+// blockingReadCloser blocks reads until it is closed.
+type blockingReadCloser struct {
+	closed chan struct{}
+}
+
+func (b *blockingReadCloser) Read(p []byte) (int, error) {
+	<-b.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (b *blockingReadCloser) Close() error {
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+	return nil
+}
+
+func newDiscardServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte("%PDF"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCancelStopsUpload(t *testing.T) {
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL, WithConcurrencyLimit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	source := &slowReader{}
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.ConvertHTML(ctx, source).Send()
+		done <- err
+	}()
+	for source.reads.Load() < 10 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Send did not return after cancellation")
+	}
+
+	// The upload stops reading its source
+	time.Sleep(20 * time.Millisecond)
+	reads := source.reads.Load()
+	time.Sleep(20 * time.Millisecond)
+	if more := source.reads.Load() - reads; more > 0 {
+		t.Errorf("source read %d more times after cancellation", more)
+	}
+
+	// The slot of the concurrency limit is free again
+	quick, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	resp, err := c.ConvertHTML(quick, strings.NewReader("<html></html>")).Send()
+	if err != nil {
+		t.Fatalf("conversion after cancellation failed: %v", err)
+	}
+	resp.Body.Close()
+	if stats := c.Stats(); stats.ActiveRequests != 0 || stats.UploadBytes != 0 || stats.QueuedRequests != 0 {
+		t.Errorf("unexpected stats after cancellation %+v", stats)
+	}
+}
+
+// blockingStorage serves every object with a blockingReadCloser.
+type blockingStorage struct {
+	memoryStorage
+	opened chan *blockingReadCloser
+}
+
+func (s *blockingStorage) DownloadFile(ctx context.Context, name string) (io.ReadCloser, error) {
+	rc := &blockingReadCloser{closed: make(chan struct{})}
+	s.opened <- rc
+	return rc, nil
+}
+
+func TestCancelClosesOpenedFiles(t *testing.T) {
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &blockingStorage{memoryStorage: *newMemoryStorage(), opened: make(chan *blockingReadCloser, 1)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- MergeStoredPDFs(ctx, c, storage, []string{"a.pdf"}, "merged.pdf")
+	}()
+	source := <-storage.opened
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("MergeStoredPDFs did not return after cancellation")
+	}
+	select {
+	case <-source.closed:
+	default:
+		t.Error("stored object not closed after cancellation")
+	}
+}
@@ -0,0 +1,148 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// HeaderHTML attaches html as the header printed at the top of every page,
+// in the top margin, which must be large enough to hold it:
+//
+//	client.ConvertHTML(ctx, index).
+//		HeaderHTML(strings.NewReader(`<p style="font-size: 8px"><span class="title"></span></p>`)).
+//		Margins(1, 0.5, 0.5, 0.5).
+//		Send()
+//
+// Chromium renders the header apart from the page: styles must be inline and
+// images data URIs. Elements of the classes date, title, url, pageNumber and
+// totalPages are filled with the printing values. Chromium convert routes only.
+func (r *Request) HeaderHTML(html io.Reader) *Request {
+	return r.File(FieldFiles, FileHeaderHTML, html)
+}
+
+// FooterHTML attaches html as the footer printed at the bottom of every page,
+// in the bottom margin, as HeaderHTML. Chromium convert routes only.
+func (r *Request) FooterHTML(html io.Reader) *Request {
+	return r.File(FieldFiles, FileFooterHTML, html)
+}
+
+// ExtraHTTPHeaders sets headers Chromium sends with the requests of the page,
+// e.g. an Authorization or tenant header for a page converted with ConvertURL:
+//
+//	client.ConvertURL(ctx, "https://app.example.com/reports/7").
+//		ExtraHTTPHeaders(map[string]string{"Authorization": "Bearer " + token}).
+//		Send()
+//
+// Headers of several calls are merged, a header set again replaces its value.
+// Chromium routes only.
+func (r *Request) ExtraHTTPHeaders(headers map[string]string) *Request {
+	for name, value := range headers {
+		if !validHeaderName(name) || strings.ContainsAny(value, "\r\n\x00") {
+			r.setErr(fmt.Errorf("%w: extra HTTP header %q", ErrInvalidField, name))
+			return r
+		}
+	}
+	if r.extraHeaders == nil {
+		r.extraHeaders = make(map[string]string, len(headers))
+	}
+	for name, value := range headers {
+		r.extraHeaders[name] = value
+	}
+	return r
+}
+
+// UserAgent overrides the User-Agent header of Chromium, e.g. to pin it for
+// sites serving different markup to unknown agents. Chromium routes only.
+func (r *Request) UserAgent(userAgent string) *Request {
+	if userAgent == "" || strings.ContainsAny(userAgent, "\r\n\x00") {
+		r.setErr(fmt.Errorf("%w: user agent %q", ErrInvalidField, userAgent))
+		return r
+	}
+	return r.Param(FieldUserAgent, userAgent)
+}
+
+// FailOnConsoleExceptions makes the conversion fail when the JavaScript of
+// the page throws, instead of producing a broken document. Gotenberg answers
+// 409 with the exceptions, see Response.Err. Chromium routes only.
+func (r *Request) FailOnConsoleExceptions(fail bool) *Request {
+	return r.Bool(FieldFailOnConsoleExceptions, fail)
+}
+
+// FailOnResourceLoadingFailed makes the conversion fail when a resource of
+// the page, e.g. an image or a stylesheet, cannot be loaded. Chromium routes
+// only.
+func (r *Request) FailOnResourceLoadingFailed(fail bool) *Request {
+	return r.Bool(FieldFailOnResourceLoadingFailed, fail)
+}
+
+// SkipNetworkIdleEvent sets whether Chromium converts the page without waiting
+// for the network to be idle. Gotenberg 8 skips the wait by default, which is
+// faster but may miss late content; pages with long-polling connections never
+// become idle and need it skipped. Chromium routes only.
+func (r *Request) SkipNetworkIdleEvent(skip bool) *Request {
+	return r.Bool(FieldSkipNetworkIdleEvent, skip)
+}
+
+// WaitDelay makes Chromium wait delay after loading the page before
+// converting it, e.g. for animations and charts drawn by timers. Prefer
+// waitForExpression when the page can signal it is ready. Chromium routes
+// only.
+func (r *Request) WaitDelay(delay time.Duration) *Request {
+	if delay < 0 {
+		r.setErr(fmt.Errorf("%w: negative wait delay %v", ErrInvalidField, delay))
+		return r
+	}
+	return r.Param(FieldWaitDelay, delay.String())
+}
+
+// FastRender is the preset for static pages such as templates rendered with
+// their assets: Chromium converts without waiting for the network to be idle,
+// and failing scripts or assets fail the conversion instead of producing a
+// broken document. Set the fields of presets with their own setters rather
+// than after a preset, Gotenberg uses the first value of a field. Chromium
+// routes only.
+func (r *Request) FastRender() *Request {
+	return r.SkipNetworkIdleEvent(true).
+		FailOnConsoleExceptions(true).
+		FailOnResourceLoadingFailed(true)
+}
+
+// ThoroughRender is the preset for JavaScript-heavy pages such as dashboards:
+// Chromium waits for the network to be idle and one more second for late
+// rendering, and failing scripts fail the conversion, while assets failing to
+// load, e.g. third-party trackers, do not. Chromium routes only.
+func (r *Request) ThoroughRender() *Request {
+	return r.SkipNetworkIdleEvent(false).
+		WaitDelay(time.Second).
+		FailOnConsoleExceptions(true).
+		FailOnResourceLoadingFailed(false)
+}
+
+// validHeaderName reports whether name is an HTTP header field name.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if c <= ' ' || c >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeExtraHTTPHeaders sets the extraHttpHeaders form field from the collected headers.
+func (r *Request) writeExtraHTTPHeaders() error {
+	if len(r.extraHeaders) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(r.extraHeaders)
+	if err != nil {
+		return fmt.Errorf("gotenberg: encode extra HTTP headers: %w", err)
+	}
+	r.Param(FieldExtraHTTPHeaders, string(data))
+	return nil
+}
@@ -0,0 +1,142 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtraHTTPHeaders(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertURL(context.Background(), "https://app.example.com/reports/7").
+		ExtraHTTPHeaders(map[string]string{"Authorization": "Bearer token", "X-Tenant": "acme"}).
+		ExtraHTTPHeaders(map[string]string{"X-Tenant": "globex"}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	want := `{"Authorization":"Bearer token","X-Tenant":"globex"}`
+	if got := capture.value(FieldExtraHTTPHeaders); got != want {
+		t.Errorf("unexpected extra HTTP headers %s", got)
+	}
+}
+
+func TestExtraHTTPHeadersValidation(t *testing.T) {
+	c := newTestClient(t)
+	for _, headers := range []map[string]string{
+		{"": "value"},
+		{"X Tenant": "acme"},
+		{"X-Tenant:": "acme"},
+		{"X-Tenant": "acme\r\nX-Injected: 1"},
+	} {
+		err := c.ConvertURL(context.Background(), "https://example.com").ExtraHTTPHeaders(headers).Validate()
+		if !errors.Is(err, ErrInvalidField) {
+			t.Errorf("%v: expected ErrInvalidField, got %v", headers, err)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	const ua = "Mozilla/5.0 (X11; Linux x86_64) Reports/1.0"
+	if _, err := c.ConvertURL(context.Background(), "https://example.com").UserAgent(ua).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldUserAgent); got != ua {
+		t.Errorf("unexpected user agent %q", got)
+	}
+
+	for _, invalid := range []string{"", "Reports\r\nX-Injected: 1"} {
+		err := c.ConvertURL(context.Background(), "https://example.com").UserAgent(invalid).Validate()
+		if !errors.Is(err, ErrInvalidField) {
+			t.Errorf("%q: expected ErrInvalidField, got %v", invalid, err)
+		}
+	}
+}
+
+func TestFailOptions(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertURL(context.Background(), "https://example.com").
+		FailOnConsoleExceptions(true).
+		FailOnResourceLoadingFailed(true).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.value(FieldFailOnConsoleExceptions) != "true" || capture.value(FieldFailOnResourceLoadingFailed) != "true" {
+		t.Errorf("unexpected fields %q %q", capture.value(FieldFailOnConsoleExceptions), capture.value(FieldFailOnResourceLoadingFailed))
+	}
+}
+
+func TestSkipNetworkIdleEvent(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	if _, err := c.ConvertURL(context.Background(), "https://example.com").SkipNetworkIdleEvent(false).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldSkipNetworkIdleEvent); got != "false" {
+		t.Errorf("unexpected skipNetworkIdleEvent %q", got)
+	}
+}
+
+func TestHeaderFooterHTML(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		HeaderHTML(strings.NewReader(`<p class="title"></p>`)).
+		FooterHTML(strings.NewReader(`<p class="pageNumber"></p>`)).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.files[FileHeaderHTML] != `<p class="title"></p>` || capture.files[FileFooterHTML] != `<p class="pageNumber"></p>` {
+		t.Errorf("unexpected header and footer %v", capture.files)
+	}
+}
+
+func TestRenderPresets(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset func(*Request) *Request
+		want   map[string]string
+	}{
+		{"fast", (*Request).FastRender, map[string]string{
+			FieldSkipNetworkIdleEvent:        "true",
+			FieldWaitDelay:                   "",
+			FieldFailOnConsoleExceptions:     "true",
+			FieldFailOnResourceLoadingFailed: "true",
+		}},
+		{"thorough", (*Request).ThoroughRender, map[string]string{
+			FieldSkipNetworkIdleEvent:        "false",
+			FieldWaitDelay:                   "1s",
+			FieldFailOnConsoleExceptions:     "true",
+			FieldFailOnResourceLoadingFailed: "false",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, capture := newCaptureClient(t)
+			if _, err := tt.preset(c.ConvertURL(context.Background(), "https://example.com")).Send(); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			for field, want := range tt.want {
+				if got := capture.value(field); got != want {
+					t.Errorf("%s = %q, want %q", field, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitDelay(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	if _, err := c.ConvertURL(context.Background(), "https://example.com").WaitDelay(1500 * time.Millisecond).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldWaitDelay); got != "1.5s" {
+		t.Errorf("waitDelay = %q", got)
+	}
+	if _, err := c.ConvertURL(context.Background(), "https://example.com").WaitDelay(-time.Second).Send(); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("expected ErrInvalidField, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package gotenberg
+
+import (
+	"io"
+	"strings"
+)
+
+// composeHead starts the document of ComposeHTML. Every section but the
+// first starts on a new page.
+const composeHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>.gotenberg-section + .gotenberg-section { break-before: page; }</style>
+</head>
+<body>
+`
+
+// ComposeHTML concatenates HTML sections into a single index.html, each
+// section starting on a new page, so multi-section reports are produced in
+// one conversion instead of converting every section and merging the PDFs:
+//
+//	client.ConvertHTML(ctx, gotenberg.ComposeHTML(cover, summary, details))
+//
+// Sections are streamed in order, wrapped in <section class="gotenberg-section">
+// elements. They can be fragments or complete documents, whose head styles
+// then apply to the whole document.
+func ComposeHTML(sections ...io.Reader) io.Reader {
+	readers := []io.Reader{strings.NewReader(composeHead)}
+	for _, section := range sections {
+		readers = append(readers,
+			strings.NewReader(`<section class="gotenberg-section">`+"\n"),
+			section,
+			strings.NewReader("\n</section>\n"),
+		)
+	}
+	readers = append(readers, strings.NewReader("</body>\n</html>\n"))
+	return io.MultiReader(readers...)
+}
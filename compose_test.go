@@ -0,0 +1,24 @@
+package gotenberg
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestComposeHTML(t *testing.T) {
+	data, err := io.ReadAll(ComposeHTML(strings.NewReader("<h1>Cover</h1>"), strings.NewReader("<p>Body</p>")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+
+	cover := strings.Index(html, "<h1>Cover</h1>")
+	body := strings.Index(html, "<p>Body</p>")
+	if cover < 0 || body < cover {
+		t.Errorf("sections missing or out of order: %s", html)
+	}
+	if strings.Count(html, `<section class="gotenberg-section">`) != 2 || !strings.Contains(html, "break-before: page") {
+		t.Errorf("sections not separated by page breaks: %s", html)
+	}
+}
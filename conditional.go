@@ -0,0 +1,57 @@
+package gotenberg
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// setValidators sets the ETag and Last-Modified headers of a stored object.
+// S3 ETags are sent unquoted, HTTP requires quotes.
+func setValidators(h http.Header, etag string, modified time.Time) {
+	if etag != "" {
+		h.Set("ETag", `"`+strings.Trim(etag, `"`)+`"`)
+	}
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// notModified reports whether the conditional headers of r match the object
+// with etag, last modified at modified. If-Modified-Since is only evaluated
+// without If-None-Match, as RFC 9110 requires.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return etagMatches(match, etag)
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	// HTTP dates have a precision of a second
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// etagMatches reports whether the If-None-Match header match lists etag,
+// comparing weakly.
+func etagMatches(match, etag string) bool {
+	etag = strings.Trim(etag, `"`)
+	for _, candidate := range strings.Split(match, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || (etag != "" && strings.Trim(candidate, `"`) == etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNotModified answers a conditional request whose object did not change.
+func writeNotModified(w http.ResponseWriter) {
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+}
@@ -0,0 +1,116 @@
+package gotenberg
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// newFakeS3 serves the objects of files from the bucket "docs", for the
+// read-only calls of MinioClient.
+func newFakeS3(t *testing.T, files map[string]string, modified time.Time) *MinioClient {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[strings.TrimPrefix(r.URL.Path, "/docs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag-`+strconv.Itoa(len(content))+`"`)
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		if r.Method == http.MethodGet {
+			io.WriteString(w, content)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL)
+	client, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("key", "secret", ""), Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &MinioClient{client: client, bucketName: "docs"}
+}
+
+func TestDownloadConditionalGet(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mux := http.NewServeMux()
+	NewMinioAPI(newFakeS3(t, map[string]string{"report.pdf": "%PDF-1.7"}, modified)).RegisterRoutes(mux)
+
+	download := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/download?objectName=report.pdf", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := download("", "")
+	if rec.Code != http.StatusOK || rec.Body.String() != "%PDF-1.7" || rec.Header().Get("ETag") != `"etag-8"` ||
+		rec.Header().Get("Last-Modified") != "Wed, 01 May 2024 12:00:00 GMT" {
+		t.Fatalf("unexpected download %d %q %v", rec.Code, rec.Body.String(), rec.Header())
+	}
+
+	for _, tc := range []struct {
+		header, value string
+		code          int
+	}{
+		{"If-None-Match", `"etag-8"`, http.StatusNotModified},
+		{"If-None-Match", `"stale"`, http.StatusOK},
+		{"If-Modified-Since", modified.Format(http.TimeFormat), http.StatusNotModified},
+		{"If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat), http.StatusOK},
+	} {
+		rec := download(tc.header, tc.value)
+		if rec.Code != tc.code {
+			t.Errorf("%s: %s: expected %d, got %d", tc.header, tc.value, tc.code, rec.Code)
+		}
+		if tc.code == http.StatusNotModified && (rec.Body.Len() != 0 || rec.Header().Get("ETag") != `"etag-8"`) {
+			t.Errorf("unexpected 304 response %q %v", rec.Body.String(), rec.Header())
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+	for _, tc := range []struct {
+		header, value string
+		want          bool
+	}{
+		{"If-None-Match", `"abc"`, true},
+		{"If-None-Match", `W/"abc"`, true},
+		{"If-None-Match", `"xyz", "abc"`, true},
+		{"If-None-Match", `*`, true},
+		{"If-None-Match", `"xyz"`, false},
+		{"If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT", true},
+		{"If-Modified-Since", "Wed, 01 May 2024 11:59:59 GMT", false},
+		{"If-Modified-Since", "yesterday", false},
+		{"", "", false},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/download", nil)
+		if tc.header != "" {
+			r.Header.Set(tc.header, tc.value)
+		}
+		if got := notModified(r, "abc", modified); got != tc.want {
+			t.Errorf("%s %s: expected %v, got %v", tc.header, tc.value, tc.want, got)
+		}
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since
+	r := httptest.NewRequest(http.MethodGet, "/api/download", nil)
+	r.Header.Set("If-None-Match", `"xyz"`)
+	r.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	if notModified(r, "abc", modified) {
+		t.Error("If-Modified-Since evaluated despite If-None-Match")
+	}
+}
@@ -1,26 +1,113 @@
 package gotenberg
 
 const (
-	ConvertHTML = "/forms/chromium/convert/html"
-	ConvertURL  = "/forms/chromium/convert/url"
-)
-
-const (
-	FieldSinglePage              = "singlePage"
-	FieldPaperWidth              = "paperWidth"
-	FieldPaperHeight             = "paperHeight"
-	FieldMarginTop               = "marginTop"
-	FieldMarginBottom            = "marginBottom"
-	FieldMarginLeft              = "marginLeft"
-	FieldMarginRight             = "marginRight"
-	FieldPreferCSSPageSize       = "preferCssPageSize"
-	FieldGenerateDocumentOutline = "generateDocumentOutline"
-	FieldGenerateTaggedPDF       = "generateTaggedPdf"
-	FieldPrintBackground         = "printBackground"
-	FieldOmitBackground          = "omitBackground"
-	FieldLandscape               = "landscape"
-	FieldScale                   = "scale"
-	FieldNativePageRanges        = "nativePageRanges"
+	ConvertHTML        = "/forms/chromium/convert/html"
+	ConvertURL         = "/forms/chromium/convert/url"
+	ConvertMarkdown    = "/forms/chromium/convert/markdown"
+	ScreenshotHTML     = "/forms/chromium/screenshot/html"
+	ScreenshotURL      = "/forms/chromium/screenshot/url"
+	ScreenshotMarkdown = "/forms/chromium/screenshot/markdown"
+	MergePDF           = "/forms/pdfengines/merge"
+	ConvertPDF         = "/forms/pdfengines/convert"
+	SplitPDF           = "/forms/pdfengines/split"
+	ReadMetadata       = "/forms/pdfengines/metadata/read"
+	WriteMetadata      = "/forms/pdfengines/metadata/write"
+	FlattenPDF         = "/forms/pdfengines/flatten"
+	ConvertOffice      = "/forms/libreoffice/convert"
+	HealthCheck        = "/health"
+	Version            = "/version"
+)
+
+const (
+	FieldSinglePage                  = "singlePage"
+	FieldPaperWidth                  = "paperWidth"
+	FieldPaperHeight                 = "paperHeight"
+	FieldMarginTop                   = "marginTop"
+	FieldMarginBottom                = "marginBottom"
+	FieldMarginLeft                  = "marginLeft"
+	FieldMarginRight                 = "marginRight"
+	FieldPreferCSSPageSize           = "preferCssPageSize"
+	FieldGenerateDocumentOutline     = "generateDocumentOutline"
+	FieldGenerateTaggedPDF           = "generateTaggedPdf"
+	FieldPrintBackground             = "printBackground"
+	FieldOmitBackground              = "omitBackground"
+	FieldLandscape                   = "landscape"
+	FieldScale                       = "scale"
+	FieldNativePageRanges            = "nativePageRanges"
+	FieldWaitForExpression           = "waitForExpression"
+	FieldWaitDelay                   = "waitDelay"
+	FieldDownloadFrom                = "downloadFrom"
+	FieldEmulatedMediaType           = "emulatedMediaType"
+	FieldPDFUA                       = "pdfua"
+	FieldMetadata                    = "metadata"
+	FieldPDFA                        = "pdfa"
+	FieldFlatten                     = "flatten"
+	FieldEmbeds                      = "embeds"
+	FieldCookies                     = "cookies"
+	FieldExtraHTTPHeaders            = "extraHttpHeaders"
+	FieldUserAgent                   = "userAgent"
+	FieldFailOnConsoleExceptions     = "failOnConsoleExceptions"
+	FieldFailOnResourceLoadingFailed = "failOnResourceLoadingFailed"
+	FieldSkipNetworkIdleEvent        = "skipNetworkIdleEvent"
+)
+
+// Split form fields, see Split.
+const (
+	FieldSplitMode  = "splitMode"
+	FieldSplitSpan  = "splitSpan"
+	FieldSplitUnify = "splitUnify"
+)
+
+// LibreOffice form fields, see ConvertOffice.
+const (
+	FieldSinglePageSheets = "singlePageSheets"
+	FieldSkipEmptyPages   = "skipEmptyPages"
+	FieldUpdateIndexes    = "updateIndexes"
+	FieldMerge            = "merge"
+	FieldPassword         = "password"
+
+	FieldLosslessImageCompression = "losslessImageCompression"
+	FieldImageQuality             = "quality"
+	FieldReduceImageResolution    = "reduceImageResolution"
+	FieldMaxImageResolution       = "maxImageResolution"
+
+	FieldNativePDFFormats                = "nativePdfFormats"
+	FieldExportFormFields                = "exportFormFields"
+	FieldAllowDuplicateFieldNames        = "allowDuplicateFieldNames"
+	FieldExportBookmarks                 = "exportBookmarks"
+	FieldExportBookmarksToPDFDestination = "exportBookmarksToPdfDestination"
+	FieldExportPlaceholders              = "exportPlaceholders"
+	FieldExportNotes                     = "exportNotes"
+	FieldExportNotesPages                = "exportNotesPages"
+	FieldExportHiddenSlides              = "exportHiddenSlides"
+)
+
+// PDF/A formats Gotenberg produces, see Archival and ConvertPDF.
+const (
+	PDFA1b = "PDF/A-1b"
+	PDFA2b = "PDF/A-2b"
+	PDFA3b = "PDF/A-3b"
+)
+
+const (
+	MediaTypePrint  = "print"
+	MediaTypeScreen = "screen"
+)
+
+const (
+	FieldScreenshotWidth            = "width"
+	FieldScreenshotHeight           = "height"
+	FieldScreenshotClip             = "clip"
+	FieldScreenshotFormat           = "format"
+	FieldScreenshotQuality          = "quality"
+	FieldScreenshotOptimizeForSpeed = "optimizeForSpeed"
+)
+
+// Image formats of the screenshot routes.
+const (
+	ImageFormatPNG  = "png"
+	ImageFormatJPEG = "jpeg"
+	ImageFormatWebP = "webp"
 )
 
 const (
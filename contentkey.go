@@ -0,0 +1,77 @@
+package gotenberg
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// ContentKey returns the content-addressable key of a document with the hex
+// encoded SHA-256 checksum and the extension ext, below prefix:
+//
+//	ContentKey("docs", checksum, ".pdf") // docs/9f/9f86d08...15b0f00a08.pdf
+//
+// Identical documents share a key, so they are stored once, and the key is
+// the same in every storage and cache holding the document. The first two
+// digits of the checksum spread the keys over directories.
+func ContentKey(prefix, checksum, ext string) string {
+	if len(checksum) < 2 {
+		return path.Join(prefix, checksum+ext)
+	}
+	return path.Join(prefix, checksum[:2], checksum+ext)
+}
+
+// ConvertAndStoreByContent sends the request and stores the converted
+// document in storage under its ContentKey below prefix, returned as the
+// StorageKey of the Result:
+//
+//	result, err := client.ConvertHTML(ctx, html).
+//		ConvertAndStoreByContent(storage, "docs")
+//
+// The document is spooled to compute its key before the upload, which is
+// skipped when the key exists already with the size of the document.
+// Otherwise it behaves like ConvertAndStore.
+func (r *Request) ConvertAndStoreByContent(storage Storage, prefix string) (Result, error) {
+	if r.webhookURL != "" {
+		return Result{}, ErrWebhookMode
+	}
+	start := time.Now()
+	resp, err := r.Send()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := resp.Err(); err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	result := resp.result()
+	counter := &documentCounter{pdf: isPDF(result.ContentType)}
+	body, err := r.client.spoolBody(io.TeeReader(resp.Body, counter), DefaultWebhookMemoryThreshold)
+	if err != nil {
+		return Result{}, fmt.Errorf("gotenberg: read document: %w", err)
+	}
+	defer body.Close()
+	result.Size = counter.size
+	result.PageCount = counter.pages
+	result.Checksum = body.checksum
+
+	key := ContentKey(prefix, result.Checksum, documentExt(result.Filename, result.ContentType))
+	ctx := ContextWithTenant(r.ctx, r.tenant)
+	if info, err := storage.GetFileInfo(ctx, key); err != nil || info.Size != result.Size {
+		if _, err := storage.UploadFile(ctx, key, body, result.Size, result.ContentType); err != nil {
+			return Result{}, fmt.Errorf("gotenberg: store %s: %w", key, err)
+		}
+		if r.verifyStored {
+			if err := verifyStored(ctx, storage, key, result); err != nil {
+				storage.DeleteFile(ctx, key)
+				return Result{}, err
+			}
+		}
+	}
+	result.Timings = resp.Timings()
+	result.Duration = time.Since(start)
+	result.StorageKey = key
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestContentKey(t *testing.T) {
+	if got := ContentKey("docs", "9f86d081", ".pdf"); got != "docs/9f/9f86d081.pdf" {
+		t.Errorf("unexpected key %s", got)
+	}
+	if got := ContentKey("", "9f86d081", ""); got != "9f/9f86d081" {
+		t.Errorf("unexpected key without prefix %s", got)
+	}
+}
+
+func TestConvertAndStoreByContent(t *testing.T) {
+	var conversions atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conversions.Add(1)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte(twoPagePDF))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+	sum := sha256.Sum256([]byte(twoPagePDF))
+	checksum := hex.EncodeToString(sum[:])
+	want := "docs/" + checksum[:2] + "/" + checksum + ".pdf"
+
+	result, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		VerifyStored().
+		ConvertAndStoreByContent(storage, "docs")
+	if err != nil {
+		t.Fatalf("ConvertAndStoreByContent failed: %v", err)
+	}
+	if result.StorageKey != want || result.Checksum != checksum || result.Size != int64(len(twoPagePDF)) || result.PageCount != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if string(storage.files[want]) != twoPagePDF {
+		t.Errorf("unexpected stored object %q", storage.files[want])
+	}
+
+	// An identical document is not uploaded again
+	stored := storage.modified[want]
+	time.Sleep(time.Millisecond)
+	again, err := c.ConvertHTML(context.Background(), strings.NewReader("<html><body></body></html>")).
+		ConvertAndStoreByContent(storage, "docs")
+	if err != nil {
+		t.Fatalf("second ConvertAndStoreByContent failed: %v", err)
+	}
+	if again.StorageKey != want || !storage.modified[want].Equal(stored) || len(storage.files) != 1 || conversions.Load() != 2 {
+		t.Errorf("identical document stored again: %+v", again)
+	}
+}
+
+func TestKeyTemplateChecksum(t *testing.T) {
+	body, err := spoolBody(strings.NewReader("pdf"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	sum := sha256.Sum256([]byte("pdf"))
+	if body.checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected checksum %s", body.checksum)
+	}
+	result := &WebhookResult{ContentType: "application/pdf", Checksum: body.checksum}
+	if got := KeyTemplate("docs/{sha256}{ext}").Expand(Job{}, result); got != "docs/"+body.checksum+".pdf" {
+		t.Errorf("unexpected key %s", got)
+	}
+
+	large, err := spoolBody(strings.NewReader(strings.Repeat("x", 100)), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer large.Close()
+	sum = sha256.Sum256([]byte(strings.Repeat("x", 100)))
+	if large.checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected checksum of spooled file %s", large.checksum)
+	}
+}
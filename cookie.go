@@ -0,0 +1,90 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SameSite attributes of cookies, see CookieSameSite.
+const (
+	SameSiteStrict = "Strict"
+	SameSiteLax    = "Lax"
+	SameSiteNone   = "None"
+)
+
+// cookie is a cookie of the cookies form field.
+type cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Domain   string `json:"domain"`
+	Path     string `json:"path,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	SameSite string `json:"sameSite,omitempty"`
+}
+
+// CookieOption sets an attribute of a cookie, see Request.Cookie.
+type CookieOption func(*cookie)
+
+// CookiePath limits the cookie to the URLs below path.
+func CookiePath(path string) CookieOption {
+	return func(c *cookie) { c.Path = path }
+}
+
+// CookieSecure sends the cookie over HTTPS only.
+func CookieSecure() CookieOption {
+	return func(c *cookie) { c.Secure = true }
+}
+
+// CookieHTTPOnly hides the cookie from the scripts of the page.
+func CookieHTTPOnly() CookieOption {
+	return func(c *cookie) { c.HTTPOnly = true }
+}
+
+// CookieSameSite sets the SameSite attribute, SameSiteStrict, SameSiteLax or
+// SameSiteNone. SameSiteNone requires CookieSecure.
+func CookieSameSite(mode string) CookieOption {
+	return func(c *cookie) { c.SameSite = mode }
+}
+
+// Cookie sets a cookie Chromium sends to domain while loading the page, e.g.
+// the session of an authenticated page converted with ConvertURL:
+//
+//	client.ConvertURL(ctx, "https://app.example.com/invoices/42").
+//		Cookie("session", token, "app.example.com", gotenberg.CookieSecure(), gotenberg.CookieHTTPOnly()).
+//		Send()
+//
+// Cookies are sent as a single JSON array when the request is sent. Chromium
+// routes only.
+func (r *Request) Cookie(name, value, domain string, opts ...CookieOption) *Request {
+	c := cookie{Name: name, Value: value, Domain: domain}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	switch {
+	case name == "" || domain == "":
+		r.setErr(fmt.Errorf("%w: cookie %q requires a name and a domain", ErrInvalidField, name))
+		return r
+	case c.SameSite != "" && c.SameSite != SameSiteStrict && c.SameSite != SameSiteLax && c.SameSite != SameSiteNone:
+		r.setErr(fmt.Errorf("%w: cookie %s has unsupported SameSite %q", ErrInvalidField, name, c.SameSite))
+		return r
+	case c.SameSite == SameSiteNone && !c.Secure:
+		r.setErr(fmt.Errorf("%w: cookie %s with SameSite None must be secure", ErrInvalidField, name))
+		return r
+	}
+	r.cookies = append(r.cookies, c)
+	return r
+}
+
+// writeCookies sets the cookies form field from the collected cookies.
+func (r *Request) writeCookies() error {
+	if len(r.cookies) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(r.cookies)
+	if err != nil {
+		return fmt.Errorf("gotenberg: encode cookies: %w", err)
+	}
+	r.Param(FieldCookies, string(data))
+	return nil
+}
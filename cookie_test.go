@@ -0,0 +1,49 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCookie(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertURL(context.Background(), "https://app.example.com/invoices/42").
+		Cookie("session", "s3cr3t", "app.example.com", CookieSecure(), CookieHTTPOnly(), CookieSameSite(SameSiteStrict)).
+		Cookie("locale", "de", "app.example.com", CookiePath("/invoices")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	want := `[{"name":"session","value":"s3cr3t","domain":"app.example.com","secure":true,"httpOnly":true,"sameSite":"Strict"},` +
+		`{"name":"locale","value":"de","domain":"app.example.com","path":"/invoices"}]`
+	if got := capture.value(FieldCookies); got != want {
+		t.Errorf("unexpected cookies %s", got)
+	}
+}
+
+func TestCookieValidation(t *testing.T) {
+	c := newTestClient(t)
+	tests := []struct {
+		name, domain string
+		opts         []CookieOption
+	}{
+		{"", "example.com", nil},
+		{"session", "", nil},
+		{"session", "example.com", []CookieOption{CookieSameSite("Sometimes")}},
+		{"session", "example.com", []CookieOption{CookieSameSite(SameSiteNone)}},
+	}
+	for _, tt := range tests {
+		err := c.ConvertURL(context.Background(), "https://example.com").Cookie(tt.name, "v", tt.domain, tt.opts...).Validate()
+		if !errors.Is(err, ErrInvalidField) {
+			t.Errorf("cookie %q on %q: expected ErrInvalidField, got %v", tt.name, tt.domain, err)
+		}
+	}
+
+	err := c.ConvertURL(context.Background(), "https://example.com").
+		Cookie("session", "v", "example.com", CookieSameSite(SameSiteNone), CookieSecure()).
+		Validate()
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package gotenberg
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig configures cross-origin access to the HTTP handlers of this
+// package, so browser front-ends can call them directly.
+type CORSConfig struct {
+	// AllowedOrigins are the allowed origins, e.g. "https://app.example.com".
+	// "*" allows any origin and "https://*.example.com" any subdomain.
+	AllowedOrigins []string
+
+	// AllowedMethods are GET and POST if empty.
+	AllowedMethods []string
+
+	// AllowedHeaders are Content-Type and HeaderAPIKey if empty.
+	AllowedHeaders []string
+
+	// ExposedHeaders are readable by the browser, the Gotenberg trace,
+	// Content-Disposition and HeaderProxyCache if empty.
+	ExposedHeaders []string
+
+	// AllowCredentials lets the listed origins send cookies and credentials.
+	// Origins only allowed by "*" never get credentialed access.
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache preflight responses.
+	MaxAge time.Duration
+}
+
+// Middleware wraps next with CORS headers and answers preflight requests.
+// Requests from other origins are served without CORS headers, so the
+// browser refuses them.
+func (c CORSConfig) Middleware(next http.Handler) http.Handler {
+	methods := strings.Join(orDefault(c.AllowedMethods, http.MethodGet, http.MethodPost), ", ")
+	headers := strings.Join(orDefault(c.AllowedHeaders, "Content-Type", HeaderAPIKey), ", ")
+	exposed := strings.Join(orDefault(c.ExposedHeaders, HeaderGotenbergTrace, "Content-Disposition", HeaderProxyCache), ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !c.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		if c.listed(origin) {
+			h.Set("Access-Control-Allow-Origin", origin)
+			if c.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+		} else {
+			// allowed by "*" only: browsers refuse credentials with a wildcard
+			h.Set("Access-Control-Allow-Origin", "*")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", methods)
+			h.Set("Access-Control-Allow-Headers", headers)
+			if c.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.Set("Access-Control-Expose-Headers", exposed)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c CORSConfig) allowed(origin string) bool {
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return c.listed(origin)
+}
+
+// listed reports whether origin matches an entry other than "*".
+func (c CORSConfig) listed(origin string) bool {
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			continue
+		}
+		if strings.EqualFold(o, origin) {
+			return true
+		}
+		// https://*.example.com matches https://app.example.com
+		if scheme, domain, ok := strings.Cut(o, "*."); ok && strings.HasPrefix(origin, scheme) &&
+			strings.HasSuffix(strings.ToLower(origin), "."+strings.ToLower(domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDefault(values []string, defaults ...string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
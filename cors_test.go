@@ -0,0 +1,62 @@
+package gotenberg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	NewMinioAPI(nil, WithCORS(CORSConfig{AllowedOrigins: []string{"https://*.example.com"}})).RegisterRoutes(mux)
+
+	// preflight is answered before the handler rejects OPTIONS
+	req := httptest.NewRequest(http.MethodOptions, "/api/upload", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent ||
+		rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		rec.Header().Get("Access-Control-Allow-Methods") != "GET, POST" {
+		t.Errorf("unexpected preflight response %d %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/download", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unexpected CORS headers for foreign origin: %v", rec.Header())
+	}
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	handler := CORSConfig{AllowedOrigins: []string{"*"}}.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/forms/chromium/convert/html", nil)
+	req.Header.Set("Origin", "https://any.org")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Access-Control-Expose-Headers") == "" {
+		t.Errorf("unexpected headers %v", rec.Header())
+	}
+}
+
+func TestCORSAnyOriginWithoutCredentials(t *testing.T) {
+	handler := CORSConfig{AllowedOrigins: []string{"*", "https://app.example.com"}, AllowCredentials: true}.
+		Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for origin, credentialed := range map[string]bool{"https://evil.example.org": false, "https://app.example.com": true} {
+		req := httptest.NewRequest(http.MethodGet, "/api/download", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		allowOrigin, credentials := rec.Header().Get("Access-Control-Allow-Origin"), rec.Header().Get("Access-Control-Allow-Credentials")
+		switch {
+		case credentialed && (allowOrigin != origin || credentials != "true"):
+			t.Errorf("%s: expected credentialed access, got %v", origin, rec.Header())
+		case !credentialed && (allowOrigin != "*" || credentials != ""):
+			t.Errorf("%s: expected anonymous access, got %v", origin, rec.Header())
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CoverPage describes a generated cover page: a logo, the title and subtitle,
+// the date and a table of metadata such as author, customer or version.
+type CoverPage struct {
+	Title    string
+	Subtitle string
+
+	// Logo is an image, e.g. PNG or SVG, embedded into the page.
+	Logo []byte
+
+	// Date is shown below the title unless zero, formatted with DateLayout
+	// ("January 2, 2006" if empty).
+	Date       time.Time
+	DateLayout string
+
+	// Fields are shown as a table, in order.
+	Fields []CoverField
+
+	// CSS is appended to the default stylesheet of the page.
+	CSS string
+}
+
+// CoverField is a row of the metadata table of a CoverPage.
+type CoverField struct {
+	Label string
+	Value string
+}
+
+var coverTemplate = template.Must(template.New("cover").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+.gotenberg-cover { display: flex; flex-direction: column; justify-content: center; min-height: 90vh; font-family: sans-serif; text-align: center; break-after: page; }
+.gotenberg-cover img { max-width: 40%; max-height: 120px; margin: 0 auto 2em; }
+.gotenberg-cover h1 { font-size: 2.4em; margin: 0 0 .3em; }
+.gotenberg-cover .subtitle { font-size: 1.3em; color: #555; margin: 0 0 1em; }
+.gotenberg-cover .date { color: #777; margin: 0 0 2em; }
+.gotenberg-cover table { margin: 0 auto; border-collapse: collapse; text-align: left; }
+.gotenberg-cover th, .gotenberg-cover td { padding: .3em 1em; border-bottom: 1px solid #ddd; }
+{{.CSS}}
+</style>
+</head>
+<body>
+<div class="gotenberg-cover">
+{{if .Logo}}<img src="{{.Logo}}" alt="">{{end}}
+<h1>{{.Title}}</h1>
+{{if .Subtitle}}<p class="subtitle">{{.Subtitle}}</p>{{end}}
+{{if .Date}}<p class="date">{{.Date}}</p>{{end}}
+{{if .Fields}}<table>
+{{range .Fields}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>{{end}}
+</div>
+</body>
+</html>
+`))
+
+// HTML returns the cover page as a complete HTML document.
+func (c CoverPage) HTML() (string, error) {
+	data := struct {
+		Title, Subtitle, Date string
+		Logo                  template.URL
+		Fields                []CoverField
+		CSS                   template.CSS
+	}{
+		Title:    c.Title,
+		Subtitle: c.Subtitle,
+		Fields:   c.Fields,
+		CSS:      template.CSS(c.CSS),
+	}
+	if len(c.Logo) > 0 {
+		contentType := http.DetectContentType(c.Logo)
+		if strings.HasPrefix(contentType, "text/") && strings.Contains(string(c.Logo), "<svg") {
+			contentType = "image/svg+xml"
+		}
+		data.Logo = template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(c.Logo))
+	}
+	if !c.Date.IsZero() {
+		layout := c.DateLayout
+		if layout == "" {
+			layout = "January 2, 2006"
+		}
+		data.Date = c.Date.Format(layout)
+	}
+
+	var b strings.Builder
+	if err := coverTemplate.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WithCoverPage prepends cover to index.html, so the cover is the first page of
+// the document. The document is composed with ComposeHTML and starts on the next
+// page. Only HTML based routes read the cover.
+func (r *Request) WithCoverPage(cover CoverPage) *Request {
+	markup, err := cover.HTML()
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+	r.coverHTML = markup
+	r.upload.total += int64(len(markup) + len(composeHead))
+	return r
+}
+
+// MergeCoverPage converts cover to PDF and merges it before pdf, e.g. for
+// documents not converted from HTML. The cover uses Gotenberg's default page
+// size; configure it with the cover's CSS (@page { size: A4; }) to match pdf.
+func MergeCoverPage(ctx context.Context, client *Client, cover CoverPage, pdf io.Reader) (io.ReadCloser, error) {
+	markup, err := cover.HTML()
+	if err != nil {
+		return nil, err
+	}
+	coverResp, err := client.ConvertHTML(ctx, strings.NewReader(markup)).
+		Bool(FieldPreferCSSPageSize, true).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := coverResp.Err(); err != nil {
+		return nil, err
+	}
+
+	req := client.MergePDFs(ctx,
+		NamedReader{Name: "cover.pdf", Reader: coverResp.Body},
+		NamedReader{Name: "document.pdf", Reader: pdf})
+	req.closers = append(req.closers, coverResp.Body)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,74 @@
+package gotenberg
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCoverPageHTML(t *testing.T) {
+	cover := CoverPage{
+		Title:  "Annual <Report>",
+		Logo:   []byte("\x89PNG\r\n\x1a\n"),
+		Date:   time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC),
+		Fields: []CoverField{{Label: "Author", Value: "Finance"}},
+	}
+	markup, err := cover.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Annual &lt;Report&gt;", `src="data:image/png;base64,`, "March 7, 2026", "<th>Author</th><td>Finance</td>"} {
+		if !strings.Contains(markup, want) {
+			t.Errorf("cover page misses %q:\n%s", want, markup)
+		}
+	}
+}
+
+func TestWithCoverPage(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<p>body</p>")).
+		WithCoverPage(CoverPage{Title: "Cover"}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	index := capture.files[FileIndexHTML]
+	cover, body := strings.Index(index, "<h1>Cover</h1>"), strings.Index(index, "<p>body</p>")
+	if !strings.HasPrefix(index, composeHead) || cover < 0 || body < cover {
+		t.Errorf("unexpected index.html:\n%s", index)
+	}
+}
+
+func TestCoverPageWithAssetCheck(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<p>body</p>")).
+		WithCoverPage(CoverPage{Title: "Cover"}).
+		CheckAssets(nil).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if index := capture.files[FileIndexHTML]; strings.Count(index, "<h1>Cover</h1>") != 1 {
+		t.Errorf("expected one cover page:\n%s", index)
+	}
+}
+
+func TestMergeCoverPage(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := MergeCoverPage(context.Background(), c, CoverPage{Title: "Cover"}, strings.NewReader("%PDF"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer merged.Close()
+	data, _ := io.ReadAll(merged)
+	if string(data) != "pdf:"+MergePDF || calls != 2 {
+		t.Errorf("unexpected merge %q after %d calls", data, calls)
+	}
+}
@@ -0,0 +1,51 @@
+package gotenberg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilePrintCSS is the name of the stylesheet attached by WithPrintCSS.
+const FilePrintCSS = "print.css"
+
+// FilePageBreakCSS is the name of the stylesheet attached by WithPageBreakCSS.
+const FilePageBreakCSS = "page-breaks.css"
+
+// PageBreakCSS is the utility stylesheet attached by WithPageBreakCSS.
+const PageBreakCSS = `.page-break { break-after: page; }
+.page-break-before { break-before: page; }
+.avoid-break { break-inside: avoid; }
+thead { display: table-header-group; }
+tfoot { display: table-footer-group; }
+tr { break-inside: avoid; }
+`
+
+// WithPrintCSS attaches css as a print-media stylesheet and links it at the end of
+// index.html, so its rules override the document's own styles without editing the template.
+// Calling it several times attaches several stylesheets, applied in call order.
+func (r *Request) WithPrintCSS(css string) *Request {
+	name := FilePrintCSS
+	if r.printCSS > 0 {
+		name = fmt.Sprintf("print-%d.css", r.printCSS+1)
+	}
+	r.printCSS++
+
+	r.File(FieldFiles, name, strings.NewReader(css))
+	r.appendHTML(fmt.Sprintf(`<link rel="stylesheet" media="print" href=%q>`, name))
+	return r
+}
+
+// WithPageBreakCSS attaches PageBreakCSS and links it at the end of index.html:
+// elements with the page-break class end their page, page-break-before elements
+// start a new page, avoid-break elements are not split across pages, table rows
+// are kept whole and table headers and footers repeat on every page.
+func (r *Request) WithPageBreakCSS() *Request {
+	if r.pageBreakCSS {
+		return r
+	}
+	r.pageBreakCSS = true
+
+	r.File(FieldFiles, FilePageBreakCSS, strings.NewReader(PageBreakCSS))
+	r.appendHTML(fmt.Sprintf(`<link rel="stylesheet" href=%q>`, FilePageBreakCSS))
+	return r
+}
@@ -0,0 +1,47 @@
+package gotenberg
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWithPrintCSS(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithPrintCSS("h1 { color: black; }").
+		WithPrintCSS("nav { display: none; }").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.files["print.css"] != "h1 { color: black; }" || capture.files["print-2.css"] != "nav { display: none; }" {
+		t.Errorf("stylesheets not attached: %v", capture.files)
+	}
+	want := "<html></html>" +
+		`<link rel="stylesheet" media="print" href="print.css">` + "\n" +
+		`<link rel="stylesheet" media="print" href="print-2.css">`
+	if got := capture.files[FileIndexHTML]; got != want {
+		t.Errorf("unexpected index.html %q", got)
+	}
+}
+
+func TestWithPageBreakCSS(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithPageBreakCSS().
+		WithPageBreakCSS().
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.files[FilePageBreakCSS] != PageBreakCSS {
+		t.Errorf("stylesheet not attached: %v", capture.files)
+	}
+	want := "<html></html>" + `<link rel="stylesheet" href="page-breaks.css">`
+	if got := capture.files[FileIndexHTML]; got != want {
+		t.Errorf("unexpected index.html %q", got)
+	}
+}
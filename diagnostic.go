@@ -0,0 +1,178 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Diagnostic is the support bundle of a failed conversion: what was sent,
+// what Gotenberg answered and how long it took, correlated with the logs of
+// Gotenberg by Trace.
+type Diagnostic struct {
+	Time   time.Time `json:"time"`
+	Tenant string    `json:"tenant,omitempty"`
+	Route  string    `json:"route"`
+	// Trace is the Gotenberg-Trace of the conversion, which Gotenberg logs.
+	Trace      string `json:"trace,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	// Error is the transport error of conversions without a response.
+	Error string `json:"error,omitempty"`
+
+	// Fields are the form fields of the request. Values that may hold
+	// credentials, e.g. cookies and extra HTTP headers, are redacted and long
+	// values truncated.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Files are the names of the attached files.
+	Files []string `json:"files,omitempty"`
+	// InputBytes is the number of file bytes uploaded.
+	InputBytes int64 `json:"input_bytes"`
+
+	// Response is the beginning of the response body, the error message of
+	// Gotenberg.
+	Response string `json:"response,omitempty"`
+
+	// QueueWait is the time spent waiting for a slot of the concurrency limit.
+	QueueWait time.Duration `json:"queue_wait"`
+	// Duration is the time from sending the request to the response.
+	Duration time.Duration `json:"duration"`
+}
+
+// DiagnosticSink receives the Diagnostic of every failed conversion of a
+// Client, see WithDiagnostics. Diagnose is called synchronously from
+// Request.Send and must be safe for concurrent use.
+type DiagnosticSink interface {
+	Diagnose(ctx context.Context, diagnostic Diagnostic)
+}
+
+// DiagnosticFunc is a DiagnosticSink of a function, e.g. logging the bundle:
+//
+//	gotenberg.WithDiagnostics(gotenberg.DiagnosticFunc(func(ctx context.Context, d gotenberg.Diagnostic) {
+//		slog.ErrorContext(ctx, "conversion failed", "diagnostic", d)
+//	}))
+type DiagnosticFunc func(ctx context.Context, diagnostic Diagnostic)
+
+// Diagnose implements DiagnosticSink.
+func (f DiagnosticFunc) Diagnose(ctx context.Context, diagnostic Diagnostic) {
+	f(ctx, diagnostic)
+}
+
+// StorageDiagnosticSink stores every Diagnostic as a JSON object in Storage,
+// under Prefix/YYYY/MM/DD/<trace>.json.
+type StorageDiagnosticSink struct {
+	Storage Storage
+	Prefix  string
+
+	// OnError is called when a diagnostic cannot be stored. Optional.
+	OnError func(diagnostic Diagnostic, err error)
+}
+
+// Diagnose implements DiagnosticSink.
+func (s *StorageDiagnosticSink) Diagnose(ctx context.Context, diagnostic Diagnostic) {
+	data, err := json.Marshal(diagnostic)
+	if err == nil {
+		_, err = s.Storage.UploadFile(ctx, datedObjectName(s.Prefix, diagnostic.Time, diagnostic.Trace), bytes.NewReader(data), int64(len(data)), "application/json")
+	}
+	if err != nil && s.OnError != nil {
+		s.OnError(diagnostic, err)
+	}
+}
+
+// WithDiagnostics hands a Diagnostic of every failed conversion to sink:
+// conversions answered with an error status by Gotenberg and conversions
+// failing to reach it.
+func WithDiagnostics(sink DiagnosticSink) ClientOption {
+	return func(c *Client) {
+		c.diagnostics = sink
+	}
+}
+
+const (
+	// maxDiagnosticValue bounds the field values of a Diagnostic.
+	maxDiagnosticValue = 1 << 10
+	// maxDiagnosticResponse bounds the response excerpt of a Diagnostic.
+	maxDiagnosticResponse = 4 << 10
+)
+
+// redactedFields are the fields whose values are left out of diagnostics.
+var redactedFields = map[string]bool{
+	FieldCookies:          true,
+	FieldExtraHTTPHeaders: true,
+	FieldDownloadFrom:     true,
+	FieldPassword:         true,
+}
+
+// noteField records a form field for the diagnostics of the request.
+func (r *Request) noteField(key, value string) {
+	if r.client.diagnostics == nil {
+		return
+	}
+	if r.fields == nil {
+		r.fields = make(map[string]string)
+	}
+	switch {
+	case redactedFields[key]:
+		value = "[redacted]"
+	case len(value) > maxDiagnosticValue:
+		cut := maxDiagnosticValue
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		value = value[:cut] + "…"
+	}
+	r.fields[key] = value
+}
+
+// diagnose hands the Diagnostic of a failed conversion to the client's sink.
+// The response excerpt is read from resp.Body, which still returns the whole
+// body afterwards.
+func (r *Request) diagnose(queued, start time.Time, resp *http.Response, err error) {
+	if r.client.diagnostics == nil || (err == nil && resp.StatusCode < http.StatusBadRequest) {
+		return
+	}
+	d := Diagnostic{
+		Time:       start,
+		Tenant:     r.tenant,
+		Route:      r.route,
+		Trace:      r.trace,
+		Fields:     r.fields,
+		InputBytes: r.inputs.size(),
+		QueueWait:  start.Sub(queued),
+		Duration:   time.Since(start),
+	}
+	for _, f := range r.files {
+		d.Files = append(d.Files, f.filename)
+	}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	if resp != nil {
+		if trace := resp.Header.Get(HeaderGotenbergTrace); trace != "" {
+			d.Trace = trace
+		}
+		d.StatusCode = resp.StatusCode
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxDiagnosticResponse))
+		d.Response = string(excerpt)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(excerpt), resp.Body), resp.Body}
+	}
+	r.client.diagnostics.Diagnose(r.ctx, d)
+}
+
+// datedObjectName returns the object name of a record of time t under
+// prefix/YYYY/MM/DD/<trace>.json, named after t without trace.
+func datedObjectName(prefix string, t time.Time, trace string) string {
+	name := trace
+	if name == "" {
+		name = strconv.FormatInt(t.UnixNano(), 10)
+	}
+	return path.Join(prefix, t.UTC().Format("2006/01/02"), name+".json")
+}
@@ -0,0 +1,90 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiagnostics(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set(HeaderGotenbergTrace, r.Header.Get(HeaderGotenbergTrace))
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"status": 400, "message": "Invalid form data: form field 'paperWidth' is invalid"}`)
+	}))
+	defer srv.Close()
+
+	var diagnostics []Diagnostic
+	sink := DiagnosticFunc(func(ctx context.Context, d Diagnostic) { diagnostics = append(diagnostics, d) })
+	c, err := NewClient(srv.Client(), srv.URL, WithDiagnostics(sink))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Trace("trace-1").
+		Param(FieldPaperWidth, "wide").
+		Cookie("session", "secret", "example.com").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := resp.Err(); err == nil || !strings.Contains(err.Error(), "paperWidth") {
+		t.Errorf("response error %v, want the Gotenberg message after the diagnostic", err)
+	}
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("got %d diagnostics, want 1", len(diagnostics))
+	}
+	d := diagnostics[0]
+	if d.Trace != "trace-1" || d.Route != ConvertHTML || d.StatusCode != http.StatusBadRequest {
+		t.Errorf("unexpected diagnostic %+v", d)
+	}
+	if d.Fields[FieldPaperWidth] != "wide" || d.Fields[FieldCookies] != "[redacted]" {
+		t.Errorf("unexpected fields %v", d.Fields)
+	}
+	if len(d.Files) != 1 || d.Files[0] != FileIndexHTML || !strings.Contains(d.Response, "Invalid form data") {
+		t.Errorf("unexpected files %v and response %q", d.Files, d.Response)
+	}
+}
+
+func TestDiagnosticsSkipSuccess(t *testing.T) {
+	srv := newDiscardServer(t)
+	called := false
+	c, err := NewClient(srv.Client(), srv.URL, WithDiagnostics(DiagnosticFunc(func(context.Context, Diagnostic) { called = true })))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if called {
+		t.Error("diagnostic of a successful conversion")
+	}
+}
+
+func TestStorageDiagnosticSink(t *testing.T) {
+	storage := newMemoryStorage()
+	sink := &StorageDiagnosticSink{Storage: storage, Prefix: "diagnostics"}
+	sink.Diagnose(context.Background(), Diagnostic{
+		Time:       time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC),
+		Route:      ConvertURL,
+		Trace:      "abc",
+		StatusCode: http.StatusBadGateway,
+	})
+
+	data, ok := storage.files["diagnostics/2024/03/05/abc.json"]
+	if !ok {
+		t.Fatalf("diagnostic not stored, have %v", storage.files)
+	}
+	var d Diagnostic
+	if err := json.Unmarshal(data, &d); err != nil || d.StatusCode != http.StatusBadGateway {
+		t.Errorf("unexpected diagnostic %+v, %v", d, err)
+	}
+}
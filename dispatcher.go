@@ -0,0 +1,78 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Dispatcher passes every webhook result to several consumers, e.g. to store
+// the document and publish an event. Use Consume as the ResultConsumer of
+// Outbox.ResultHandler.
+//
+// Consumers run in the order they were added, each reading the document from
+// the start. A failing or panicking consumer does not prevent the others from
+// running.
+type Dispatcher struct {
+	consumers []dispatchConsumer
+
+	// OnError is called for every failing consumer. Optional.
+	OnError func(name string, job Job, err error)
+}
+
+type dispatchConsumer struct {
+	name     string
+	consume  ResultConsumer
+	optional bool
+}
+
+// Add adds a required consumer. When it fails, Consume fails and the job
+// stays pending, so the result is dispatched again when it is redelivered.
+func (d *Dispatcher) Add(name string, consume ResultConsumer) *Dispatcher {
+	d.consumers = append(d.consumers, dispatchConsumer{name: name, consume: consume})
+	return d
+}
+
+// AddOptional adds a consumer whose failures are only reported to OnError.
+func (d *Dispatcher) AddOptional(name string, consume ResultConsumer) *Dispatcher {
+	d.consumers = append(d.consumers, dispatchConsumer{name: name, consume: consume, optional: true})
+	return d
+}
+
+// Consume implements ResultConsumer. It returns the errors of the failed
+// required consumers.
+func (d *Dispatcher) Consume(ctx context.Context, job Job, result *WebhookResult) error {
+	var errs []error
+	for _, c := range d.consumers {
+		err := d.run(ctx, c, job, result)
+		if err == nil {
+			continue
+		}
+		if d.OnError != nil {
+			d.OnError(c.name, job, err)
+		}
+		if !c.optional {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run calls a single consumer with a rewound document.
+func (d *Dispatcher) run(ctx context.Context, c dispatchConsumer, job Job, result *WebhookResult) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("gotenberg: consumer %s panicked: %v", c.name, p)
+		}
+	}()
+
+	if _, err := result.Body.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("gotenberg: consumer %s: %w", c.name, err)
+	}
+	r := *result
+	if err := c.consume(ctx, job, &r); err != nil {
+		return fmt.Errorf("gotenberg: consumer %s: %w", c.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	var read []string
+	reader := func(name string) ResultConsumer {
+		return func(ctx context.Context, job Job, result *WebhookResult) error {
+			data, _ := io.ReadAll(result.Body)
+			read = append(read, name+":"+string(data))
+			return nil
+		}
+	}
+
+	var reported []string
+	d := &Dispatcher{
+		OnError: func(name string, job Job, err error) { reported = append(reported, name) },
+	}
+	d.Add("store", reader("store")).
+		AddOptional("events", func(ctx context.Context, job Job, result *WebhookResult) error {
+			panic("broker down")
+		}).
+		AddOptional("audit", func(ctx context.Context, job Job, result *WebhookResult) error {
+			return errors.New("audit failed")
+		}).
+		Add("index", reader("index"))
+
+	result := &WebhookResult{Body: bytes.NewReader([]byte("pdf"))}
+	if err := d.Consume(context.Background(), Job{ID: "job"}, result); err != nil {
+		t.Errorf("optional failures must not fail Consume: %v", err)
+	}
+	if strings.Join(read, ",") != "store:pdf,index:pdf" {
+		t.Errorf("unexpected reads %v", read)
+	}
+	if strings.Join(reported, ",") != "events,audit" {
+		t.Errorf("unexpected reported errors %v", reported)
+	}
+
+	d.Add("failing", func(ctx context.Context, job Job, result *WebhookResult) error {
+		return errors.New("storage down")
+	})
+	if err := d.Consume(context.Background(), Job{ID: "job"}, result); err == nil || !strings.Contains(err.Error(), "storage down") {
+		t.Errorf("expected required consumer error, got %v", err)
+	}
+}
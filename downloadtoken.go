@@ -0,0 +1,97 @@
+package gotenberg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDownloadTokenTTL is the lifetime of download tokens when none is configured.
+const DefaultDownloadTokenTTL = 15 * time.Minute
+
+// MinDownloadTokenKeySize is the minimum length of DownloadTokens.Key: with
+// shorter keys, e.g. an empty key, anyone could forge tokens.
+const MinDownloadTokenKeySize = 32
+
+var (
+	// ErrInvalidDownloadToken is returned for download tokens that are
+	// malformed or were not signed with the key.
+	ErrInvalidDownloadToken = errors.New("gotenberg: invalid download token")
+
+	// ErrDownloadTokenExpired is returned for download tokens past their expiry.
+	ErrDownloadTokenExpired = errors.New("gotenberg: download token expired")
+)
+
+// DownloadTokens signs short-lived tokens naming a stored object, so the
+// download endpoint of MinioAPI serves only objects handed out by the
+// application instead of any object whose name can be guessed, see
+// WithDownloadTokens. A token is an HMAC-SHA256 over the object name and the
+// expiry time.
+type DownloadTokens struct {
+	// Key is the secret tokens are signed with, at least
+	// MinDownloadTokenKeySize random bytes. Tokens never verify with shorter
+	// keys.
+	Key []byte
+
+	// TTL is the lifetime of the tokens, DefaultDownloadTokenTTL if zero.
+	TTL time.Duration
+
+	// now returns the current time, time.Now if nil
+	now func() time.Time
+}
+
+func (t *DownloadTokens) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// Sign returns a token for objectName expiring after TTL.
+func (t *DownloadTokens) Sign(objectName string) string {
+	ttl := t.TTL
+	if ttl <= 0 {
+		ttl = DefaultDownloadTokenTTL
+	}
+	payload := binary.BigEndian.AppendUint64(nil, uint64(t.clock().Add(ttl).Unix()))
+	payload = append(payload, objectName...)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(t.mac(payload))
+}
+
+// Verify returns the object name of token. It returns an error wrapping
+// ErrInvalidDownloadToken for forged or malformed tokens and
+// ErrDownloadTokenExpired for expired ones. All tokens are invalid when Key
+// is shorter than MinDownloadTokenKeySize.
+func (t *DownloadTokens) Verify(token string) (string, error) {
+	if len(t.Key) < MinDownloadTokenKeySize {
+		return "", fmt.Errorf("%w: signing key shorter than %d bytes", ErrInvalidDownloadToken, MinDownloadTokenKeySize)
+	}
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidDownloadToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) < 8 {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidDownloadToken)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, t.mac(payload)) {
+		return "", fmt.Errorf("%w: bad signature", ErrInvalidDownloadToken)
+	}
+	expiry := time.Unix(int64(binary.BigEndian.Uint64(payload)), 0)
+	if !t.clock().Before(expiry) {
+		return "", fmt.Errorf("%w at %s", ErrDownloadTokenExpired, expiry.UTC().Format(time.RFC3339))
+	}
+	return string(payload[8:]), nil
+}
+
+func (t *DownloadTokens) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, t.Key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,92 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadTokens(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tokens := &DownloadTokens{Key: []byte("0123456789abcdef0123456789abcdef"), TTL: time.Minute, now: func() time.Time { return now }}
+
+	token := tokens.Sign("invoices/42.pdf")
+	if strings.Contains(token, "invoices") {
+		t.Errorf("token %s exposes the object name", token)
+	}
+	if name, err := tokens.Verify(token); err != nil || name != "invoices/42.pdf" {
+		t.Fatalf("Verify returned %q, %v", name, err)
+	}
+
+	payload, mac, _ := strings.Cut(token, ".")
+	other := tokens.Sign("invoices/43.pdf")
+	otherPayload, _, _ := strings.Cut(other, ".")
+	foreign := &DownloadTokens{Key: []byte("another key of thirty-two bytes!")}
+	for name, forged := range map[string]string{
+		"swapped payload": otherPayload + "." + mac,
+		"foreign key":     foreign.Sign("invoices/42.pdf"),
+		"no signature":    payload,
+		"garbage":         "!!.??",
+		"empty":           "",
+	} {
+		if _, err := tokens.Verify(forged); !errors.Is(err, ErrInvalidDownloadToken) {
+			t.Errorf("%s: expected ErrInvalidDownloadToken, got %v", name, err)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := tokens.Verify(token); !errors.Is(err, ErrDownloadTokenExpired) {
+		t.Errorf("expected ErrDownloadTokenExpired, got %v", err)
+	}
+}
+
+func TestDownloadTokensRequireKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("short")} {
+		weak := &DownloadTokens{Key: key}
+		if _, err := weak.Verify(weak.Sign("invoices/42.pdf")); !errors.Is(err, ErrInvalidDownloadToken) {
+			t.Errorf("%d byte key: expected ErrInvalidDownloadToken, got %v", len(key), err)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d byte key: WithDownloadTokens did not panic", len(key))
+				}
+			}()
+			WithDownloadTokens(weak)
+		}()
+	}
+}
+
+func TestDownloadRequiresToken(t *testing.T) {
+	tokens := &DownloadTokens{Key: []byte("0123456789abcdef0123456789abcdef")}
+	expired := &DownloadTokens{Key: tokens.Key, now: func() time.Time { return time.Now().Add(-time.Hour) }}
+	mux := http.NewServeMux()
+	NewMinioAPI(nil, WithDownloadTokens(tokens)).RegisterRoutes(mux)
+
+	for _, tc := range []struct {
+		query     string
+		code      int
+		errorCode ErrorCode
+	}{
+		{"objectName=invoices/42.pdf", http.StatusBadRequest, CodeBadRequest},
+		{"token=" + url.QueryEscape((&DownloadTokens{Key: []byte("forged")}).Sign("invoices/42.pdf")), http.StatusForbidden, CodeForbidden},
+		{"token=" + url.QueryEscape(expired.Sign("invoices/42.pdf")), http.StatusForbidden, CodeForbidden},
+	} {
+		for _, route := range []string{"/api/download", "/api/preview"} {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, route+"?"+tc.query, nil))
+			if rec.Code != tc.code {
+				t.Errorf("%s?%s: expected %d, got %d", route, tc.query, tc.code, rec.Code)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Code != tc.errorCode {
+				t.Errorf("%s?%s: expected code %s, got %+v (%v)", route, tc.query, tc.errorCode, resp, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package gotenberg
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrDecryption is returned when reading an object of EncryptedStorage that
+// is not encrypted, was encrypted with another key or was modified or
+// truncated.
+var ErrDecryption = errors.New("gotenberg: cannot decrypt stored object")
+
+// KeyProvider provides the AES keys of EncryptedStorage, identified by an ID
+// stored with every object, so keys can be rotated while older objects stay
+// readable.
+type KeyProvider interface {
+	// EncryptionKey returns the key new objects are encrypted with, and its ID.
+	EncryptionKey(ctx context.Context) (id string, key []byte, err error)
+	// DecryptionKey returns the key with id.
+	DecryptionKey(ctx context.Context, id string) ([]byte, error)
+}
+
+// StaticKeys is a KeyProvider with a fixed set of keys, by ID. Keys must have
+// 16, 24 or 32 bytes, selecting AES-128, AES-192 or AES-256.
+type StaticKeys struct {
+	// Current is the ID of the key new objects are encrypted with.
+	Current string
+	Keys    map[string][]byte
+}
+
+// EncryptionKey implements KeyProvider.
+func (s StaticKeys) EncryptionKey(ctx context.Context) (string, []byte, error) {
+	key, err := s.DecryptionKey(ctx, s.Current)
+	return s.Current, key, err
+}
+
+// DecryptionKey implements KeyProvider.
+func (s StaticKeys) DecryptionKey(ctx context.Context, id string) ([]byte, error) {
+	key, ok := s.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("gotenberg: unknown encryption key %q", id)
+	}
+	return key, nil
+}
+
+// EncryptedStorage is a Storage encrypting objects with AES-GCM before they
+// are uploaded to Storage and decrypting them on download, for deployments
+// that cannot rely on server-side encryption:
+//
+//	storage := &gotenberg.EncryptedStorage{
+//		Storage: minioClient,
+//		Keys:    gotenberg.StaticKeys{Current: "2024-01", Keys: map[string][]byte{"2024-01": key}},
+//	}
+//
+// Objects are encrypted in chunks as they are streamed, each chunk
+// authenticated, so reordered, modified or truncated objects fail to download
+// with ErrDecryption. GetFileInfo reports the size of the document rather
+// than of the stored object; ListFiles, of Lister storages, the stored sizes.
+type EncryptedStorage struct {
+	Storage Storage
+	Keys    KeyProvider
+}
+
+var (
+	_ Storage = (*EncryptedStorage)(nil)
+	_ Lister  = (*EncryptedStorage)(nil)
+)
+
+const (
+	// encryptedMagic starts every encrypted object, followed by the nonce
+	// prefix, the length of the key ID and the key ID.
+	encryptedMagic = "GTE1"
+
+	encryptedChunkSize = 64 << 10
+	noncePrefixSize    = 7
+)
+
+// UploadFile implements Storage, encrypting the document.
+func (s *EncryptedStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	id, key, err := s.Keys.EncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > math.MaxUint8 {
+		return nil, fmt.Errorf("gotenberg: encryption key ID %q is too long", id)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(encryptedMagic)+noncePrefixSize+1+len(id))
+	header = append(header, encryptedMagic...)
+	prefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header = append(header, prefix...)
+	header = append(header, byte(len(id)))
+	header = append(header, id...)
+
+	encrypted := &encryptReader{
+		src:    bufio.NewReaderSize(reader, encryptedChunkSize),
+		aead:   aead,
+		prefix: prefix,
+		plain:  make([]byte, encryptedChunkSize),
+		out:    header,
+	}
+	if size >= 0 {
+		chunks := max((size+encryptedChunkSize-1)/encryptedChunkSize, 1)
+		size = int64(len(header)) + size + chunks*int64(aead.Overhead())
+	}
+	return s.Storage.UploadFile(ctx, objectName, encrypted, size, contentType)
+}
+
+// DownloadFile implements Storage, decrypting the document as it is read.
+func (s *EncryptedStorage) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	content, err := s.Storage.DownloadFile(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+	src := bufio.NewReaderSize(content, encryptedChunkSize+16)
+	prefix, id, err := readEncryptedHeader(src)
+	if err != nil {
+		content.Close()
+		return nil, fmt.Errorf("%w: %s: %v", ErrDecryption, objectName, err)
+	}
+	key, err := s.Keys.DecryptionKey(ctx, id)
+	if err != nil {
+		content.Close()
+		return nil, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		content.Close()
+		return nil, err
+	}
+	return &decryptReader{
+		src:    src,
+		closer: content,
+		name:   objectName,
+		aead:   aead,
+		prefix: prefix,
+		sealed: make([]byte, encryptedChunkSize+aead.Overhead()),
+	}, nil
+}
+
+// GetFileInfo implements Storage. The size is the size of the document, which
+// takes reading the header of the object.
+func (s *EncryptedStorage) GetFileInfo(ctx context.Context, objectName string) (minio.ObjectInfo, error) {
+	info, err := s.Storage.GetFileInfo(ctx, objectName)
+	if err != nil {
+		return info, err
+	}
+	content, err := s.Storage.DownloadFile(ctx, objectName)
+	if err != nil {
+		return info, err
+	}
+	defer content.Close()
+	_, id, err := readEncryptedHeader(bufio.NewReader(content))
+	if err != nil {
+		return info, fmt.Errorf("%w: %s: %v", ErrDecryption, objectName, err)
+	}
+	headerSize := int64(len(encryptedMagic) + noncePrefixSize + 1 + len(id))
+	// Every chunk carries the 16 bytes GCM tag
+	sealed := int64(encryptedChunkSize + 16)
+	body := info.Size - headerSize
+	chunks := (body + sealed - 1) / sealed
+	info.Size = body - chunks*16
+	return info, nil
+}
+
+// DeleteFile implements Storage.
+func (s *EncryptedStorage) DeleteFile(ctx context.Context, objectName string) error {
+	return s.Storage.DeleteFile(ctx, objectName)
+}
+
+// ListFiles implements Lister for storages implementing it. The sizes are
+// the sizes of the stored objects.
+func (s *EncryptedStorage) ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo {
+	return listFiles(ctx, s.Storage, prefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("gotenberg: encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// readEncryptedHeader reads the header of an encrypted object.
+func readEncryptedHeader(r *bufio.Reader) (prefix []byte, id string, err error) {
+	fixed := make([]byte, len(encryptedMagic)+noncePrefixSize+1)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, "", errors.New("missing header")
+	}
+	if string(fixed[:len(encryptedMagic)]) != encryptedMagic {
+		return nil, "", errors.New("not encrypted")
+	}
+	idBytes := make([]byte, fixed[len(fixed)-1])
+	if _, err := io.ReadFull(r, idBytes); err != nil {
+		return nil, "", errors.New("missing key ID")
+	}
+	return fixed[len(encryptedMagic) : len(encryptedMagic)+noncePrefixSize], string(idBytes), nil
+}
+
+// chunkNonce returns the nonce of chunk counter: the random prefix of the
+// object, the counter and whether the chunk is the last one, so chunks can
+// neither be reordered nor dropped from the end.
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, noncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptReader encrypts src chunk by chunk.
+type encryptReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	plain   []byte
+	// out holds the encrypted bytes not read yet
+	out  []byte
+	done bool
+}
+
+func (e *encryptReader) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.seal(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+// seal encrypts the next chunk.
+func (e *encryptReader) seal() error {
+	n, err := io.ReadFull(e.src, e.plain)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	if !last {
+		if _, err := e.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if e.counter == math.MaxUint32 {
+		return errors.New("gotenberg: document too large to encrypt")
+	}
+	e.out = e.aead.Seal(e.out[:0], chunkNonce(e.prefix, e.counter, last), e.plain[:n], nil)
+	e.counter++
+	e.done = last
+	return nil
+}
+
+// decryptReader decrypts the chunks of src.
+type decryptReader struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	name    string
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	sealed  []byte
+	// out holds the decrypted bytes not read yet
+	out  []byte
+	done bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// open decrypts the next chunk.
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.src, d.sealed)
+	last := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !last {
+		return err
+	}
+	if !last {
+		if _, err := d.src.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	plain, err := d.aead.Open(d.out[:0], chunkNonce(d.prefix, d.counter, last), d.sealed[:n], nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s: chunk %d is corrupt or missing", ErrDecryption, d.name, d.counter)
+	}
+	d.out = plain
+	d.counter++
+	d.done = last
+	return nil
+}
+
+func (d *decryptReader) Close() error {
+	return d.closer.Close()
+}
@@ -0,0 +1,155 @@
+package gotenberg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func newEncryptedStorage(t *testing.T) (*EncryptedStorage, *memoryStorage) {
+	t.Helper()
+	key := make([]byte, 32)
+	rand.Read(key)
+	backend := newMemoryStorage()
+	return &EncryptedStorage{Storage: backend, Keys: StaticKeys{Current: "k1", Keys: map[string][]byte{"k1": key}}}, backend
+}
+
+// sealDocument returns the object EncryptedStorage should store for document,
+// sealed chunk by chunk with the key and the nonce prefix in the header of
+// stored.
+func sealDocument(t *testing.T, storage *EncryptedStorage, stored, document []byte) []byte {
+	t.Helper()
+	if !bytes.HasPrefix(stored, []byte(encryptedMagic)) {
+		t.Fatalf("stored object starts with %q", stored[:min(len(stored), len(encryptedMagic))])
+	}
+	prefix, id, err := readEncryptedHeader(bufio.NewReader(bytes.NewReader(stored)))
+	if err != nil {
+		t.Fatalf("invalid header: %v", err)
+	}
+	key, err := storage.Keys.DecryptionKey(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	object := append([]byte(nil), stored[:len(encryptedMagic)+noncePrefixSize+1+len(id)]...)
+	chunks := max((len(document)+encryptedChunkSize-1)/encryptedChunkSize, 1)
+	for i := 0; i < chunks; i++ {
+		chunk := document[i*encryptedChunkSize : min((i+1)*encryptedChunkSize, len(document))]
+		object = aead.Seal(object, chunkNonce(prefix, uint32(i), i == chunks-1), chunk, nil)
+	}
+	return object
+}
+
+func TestEncryptedStorageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	storage, backend := newEncryptedStorage(t)
+	for _, size := range []int{0, 1, encryptedChunkSize, encryptedChunkSize + 1, 3*encryptedChunkSize + 100} {
+		document := make([]byte, size)
+		rand.Read(document)
+		if _, err := storage.UploadFile(ctx, "doc.pdf", bytes.NewReader(document), int64(size), "application/pdf"); err != nil {
+			t.Fatalf("%d: upload failed: %v", size, err)
+		}
+		if want := sealDocument(t, storage, backend.files["doc.pdf"], document); !bytes.Equal(backend.files["doc.pdf"], want) {
+			t.Errorf("%d: stored object is not the sealed document", size)
+		}
+
+		info, err := storage.GetFileInfo(ctx, "doc.pdf")
+		if err != nil {
+			t.Fatalf("%d: GetFileInfo failed: %v", size, err)
+		}
+		if info.Size != int64(size) {
+			t.Errorf("%d: GetFileInfo reported %d bytes", size, info.Size)
+		}
+
+		content, err := storage.DownloadFile(ctx, "doc.pdf")
+		if err != nil {
+			t.Fatalf("%d: download failed: %v", size, err)
+		}
+		got, err := io.ReadAll(content)
+		content.Close()
+		if err != nil {
+			t.Fatalf("%d: read failed: %v", size, err)
+		}
+		if !bytes.Equal(got, document) {
+			t.Errorf("%d: decrypted %d bytes differ from the document", size, len(got))
+		}
+	}
+}
+
+func TestEncryptedStorageDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	storage, backend := newEncryptedStorage(t)
+	document := make([]byte, 2*encryptedChunkSize+10)
+	if _, err := storage.UploadFile(ctx, "doc.pdf", bytes.NewReader(document), -1, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	stored := backend.files["doc.pdf"]
+	headerSize := len(encryptedMagic) + noncePrefixSize + 1 + len("k1")
+
+	for name, tampered := range map[string][]byte{
+		"flipped":   append(append([]byte{}, stored[:len(stored)-1]...), stored[len(stored)-1]^1),
+		"truncated": stored[:headerSize+2*(encryptedChunkSize+16)],
+		"plain":     []byte("%PDF-1.7"),
+	} {
+		backend.files["doc.pdf"] = tampered
+		content, err := storage.DownloadFile(ctx, "doc.pdf")
+		if err == nil {
+			_, err = io.ReadAll(content)
+			content.Close()
+		}
+		if !errors.Is(err, ErrDecryption) {
+			t.Errorf("%s: expected ErrDecryption, got %v", name, err)
+		}
+	}
+}
+
+func TestEncryptedStorageKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	storage, _ := newEncryptedStorage(t)
+	if _, err := storage.UploadFile(ctx, "old.pdf", bytes.NewReader([]byte("%PDF old")), -1, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := storage.Keys.(StaticKeys)
+	keys.Keys["k2"] = make([]byte, 16)
+	keys.Current = "k2"
+	storage.Keys = keys
+	content, err := storage.DownloadFile(ctx, "old.pdf")
+	if err != nil {
+		t.Fatalf("download after rotation failed: %v", err)
+	}
+	defer content.Close()
+	if got, _ := io.ReadAll(content); string(got) != "%PDF old" {
+		t.Errorf("unexpected content %q", got)
+	}
+
+	delete(keys.Keys, "k1")
+	if _, err := storage.DownloadFile(ctx, "old.pdf"); err == nil {
+		t.Error("expected an error for a removed key")
+	}
+}
+
+func TestConvertAndStoreEncrypted(t *testing.T) {
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage, backend := newEncryptedStorage(t)
+	result, err := c.ConvertHTML(context.Background(), bytes.NewReader([]byte("<html></html>"))).
+		VerifyStored().
+		ConvertAndStore(storage, "doc.pdf")
+	if err != nil {
+		t.Fatalf("ConvertAndStore failed: %v", err)
+	}
+	if result.Size != 4 || bytes.Contains(backend.files["doc.pdf"], []byte("%PDF")) {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
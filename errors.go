@@ -0,0 +1,59 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize bounds the Gotenberg error messages read from responses and callbacks.
+const maxErrorBodySize = 64 << 10
+
+// GotenbergError is a conversion failure reported by Gotenberg, either in the
+// response of a synchronous conversion (see Response.Err) or posted to the
+// webhook error URL (see DecodeWebhookError).
+type GotenbergError struct {
+	StatusCode int    `json:"status"`
+	Message    string `json:"message"`
+	Trace      string `json:"-"`
+}
+
+func (e *GotenbergError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("gotenberg: conversion failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("gotenberg: conversion failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Err returns a *GotenbergError when Gotenberg rejected the conversion,
+// reading and closing the response body. It returns nil for successful responses.
+func (r *Response) Err() error {
+	if r.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	defer r.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(r.Body, maxErrorBodySize))
+	return parseGotenbergError(r.StatusCode, body, r.GotenbergTrace)
+}
+
+// DecodeWebhookError decodes the JSON error ({"status": ..., "message": ...})
+// Gotenberg posts to the webhook error URL.
+func DecodeWebhookError(r *http.Request) (*GotenbergError, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxErrorBodySize))
+	if err != nil {
+		return nil, err
+	}
+	return parseGotenbergError(0, body, r.Header.Get(HeaderGotenbergTrace)), nil
+}
+
+// parseGotenbergError decodes a JSON error body, falling back to the body text
+// as message. status is used unless the body carries its own.
+func parseGotenbergError(status int, body []byte, trace string) *GotenbergError {
+	e := &GotenbergError{StatusCode: status, Trace: trace}
+	if err := json.Unmarshal(body, e); err != nil || e.Message == "" {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	return e
+}
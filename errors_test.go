@@ -0,0 +1,44 @@
+package gotenberg
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseErr(t *testing.T) {
+	resp := &Response{
+		Response: &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader("Invalid form data\n")),
+		},
+		GotenbergTrace: "trace",
+	}
+	var gerr *GotenbergError
+	if err := resp.Err(); !errors.As(err, &gerr) {
+		t.Fatalf("expected *GotenbergError, got %v", err)
+	}
+	if gerr.StatusCode != 400 || gerr.Message != "Invalid form data" || gerr.Trace != "trace" {
+		t.Errorf("unexpected error %+v", gerr)
+	}
+
+	ok := &Response{Response: &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}}
+	if err := ok.Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestDecodeWebhookError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/error", strings.NewReader(`{"status":503,"message":"Service Unavailable"}`))
+	req.Header.Set(HeaderGotenbergTrace, "trace")
+	gerr, err := DecodeWebhookError(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gerr.StatusCode != 503 || gerr.Message != "Service Unavailable" || gerr.Trace != "trace" {
+		t.Errorf("unexpected error %+v", gerr)
+	}
+}
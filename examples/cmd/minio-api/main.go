@@ -14,11 +14,11 @@ import (
 func main() {
 	// MinIO configuration
 	config := gotenberg.MinioConfig{
-		Endpoint:        "localhost:9000",           // MinIO server endpoint
-		AccessKeyID:     "minioadmin",               // MinIO access key
-		SecretAccessKey: "minioadmin",               // MinIO secret key
-		BucketName:      "documents",                // Bucket name
-		UseSSL:          false,                      // Use HTTPS (false for local development)
+		Endpoint:        "localhost:9000", // MinIO server endpoint
+		AccessKeyID:     "minioadmin",     // MinIO access key
+		SecretAccessKey: "minioadmin",     // MinIO secret key
+		BucketName:      "documents",      // Bucket name
+		UseSSL:          false,            // Use HTTPS (false for local development)
 	}
 
 	// Create MinIO client
@@ -28,12 +28,22 @@ func main() {
 		log.Fatalf("Failed to create MinIO client: %v", err)
 	}
 
+	// Create Gotenberg client for the conversion and preview endpoints
+	gotenbergURL := os.Getenv("GOTENBERG_URL")
+	if gotenbergURL == "" {
+		gotenbergURL = "http://localhost:3000"
+	}
+	gotenbergClient, err := gotenberg.NewClient(&http.Client{}, gotenbergURL)
+	if err != nil {
+		log.Fatalf("Failed to create Gotenberg client: %v", err)
+	}
+
 	// Create API handler
-	api := gotenberg.NewMinioAPI(minioClient)
+	api := gotenberg.NewMinioAPI(minioClient, gotenberg.WithGotenberg(gotenbergClient))
 
 	// Create HTTP server
 	mux := http.NewServeMux()
-	
+
 	// Register MinIO routes
 	api.RegisterRoutes(mux)
 
@@ -55,8 +65,10 @@ func main() {
 	fmt.Println("Available endpoints:")
 	fmt.Println("  POST /api/upload     - Upload file to MinIO")
 	fmt.Println("  GET  /api/download   - Download file from MinIO")
+	fmt.Println("  POST /api/convert    - Convert HTML to PDF and store it in MinIO")
+	fmt.Println("  GET  /api/preview    - PNG preview of a converted document")
 	fmt.Println("  GET  /health         - Health check")
-	
+
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
@@ -7,15 +7,14 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/nativebpm/gotenberg-client"
 	"github.com/nativebpm/gotenberg-client/examples/model"
 	"github.com/nativebpm/gotenberg-client/examples/pkg/image"
-	"github.com/nativebpm/gotenberg-client/examples/pkg/templates/invoice"
+	"github.com/nativebpm/gotenberg-client/templates"
+	"github.com/nativebpm/gotenberg-client/webhook"
 )
 
 // cleanupPDFFiles removes all PDF files from the current directory
@@ -45,7 +44,7 @@ func cleanupPDFFiles() {
 func main() {
 	cleanupPDFFiles()
 
-	srv := StartServer(":28080")
+	srv := &webhook.Server{Addr: ":28080", Handler: newMux()}
 
 	gotenbergURL := `http://localhost:3000`
 
@@ -62,12 +61,8 @@ func main() {
 
 	go func() { // Example #1:
 		data := model.InvoiceData
-		html := bytes.NewBuffer(nil)
-		if err := invoice.Template.Execute(html, data); err != nil {
-			log.Fatal(err)
-		}
 
-		resp, err := client.ConvertHTML(context.Background(), html).
+		resp, err := client.ConvertTemplate(context.Background(), templates.Invoice, data).
 			File(gotenberg.FieldFiles, "logo.png", bytes.NewReader(logo)).
 			Bool(gotenberg.FieldPrintBackground, true).
 			WebhookURL("http://host.docker.internal:28080/success", http.MethodPost).
@@ -88,12 +83,8 @@ func main() {
 	go func() { // Example #2:
 		time.Sleep(1 * time.Second) // Задержка для избежания одновременных запросов
 		data := model.InvoiceData
-		html := bytes.NewBuffer(nil)
-		if err := invoice.Template.Execute(html, data); err != nil {
-			log.Fatal(err)
-		}
 
-		resp, err := client.ConvertHTML(context.Background(), html).
+		resp, err := client.ConvertTemplate(context.Background(), templates.Invoice, data).
 			File(gotenberg.FieldFiles, "logo.png", bytes.NewReader(logo)).
 			Bool(gotenberg.FieldPrintBackground, true).
 			WebhookURL("http://host.docker.internal:28080/success", http.MethodPost).
@@ -113,15 +104,9 @@ func main() {
 			"gotenberg-trace", resp.GotenbergTrace)
 	}()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-	slog.Info("waiting for webhook callbacks; press Ctrl+C to exit")
-	<-sigCh
-
-	slog.Info("shutting down webhook server")
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		slog.Info("error shutting down server", "err", err)
+	slog.Info("waiting for webhook callbacks; press Ctrl+C to exit", "addr", srv.Addr)
+	if err := srv.Run(); err != nil {
+		log.Fatalf("webhook server error: %v", err)
 	}
+	slog.Info("webhook server stopped")
 }
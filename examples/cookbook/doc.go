@@ -0,0 +1,8 @@
+// Package cookbook holds compile-checked, runnable examples of the
+// gotenberg-client features, one file per feature. Run them with
+//
+//	go test ./examples/cookbook
+//
+// The examples run against an in-process stand-in for Gotenberg; replace
+// gotenbergURL with the URL of a real Gotenberg to convert actual documents.
+package cookbook
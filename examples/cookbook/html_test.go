@@ -0,0 +1,39 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Convert an HTML document with its stylesheet to an A4 PDF.
+func Example_convertHTML() {
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	html := strings.NewReader(`<html><head><link rel="stylesheet" href="style.css"></head><body><h1>Hello</h1></body></html>`)
+	resp, err := client.ConvertHTML(context.Background(), html).
+		File(gotenberg.FieldFiles, "style.css", strings.NewReader("h1 { color: navy; }")).
+		PaperSizeA4().
+		Margins(1, 1, 1, 1).
+		OutputFilename("hello").
+		Send()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := resp.Err(); err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	pdf, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(pdf))
+	// Output: %PDF-1.7 /forms/chromium/convert/html
+}
@@ -0,0 +1,106 @@
+package cookbook_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// gotenbergURL is the Gotenberg the examples convert with.
+var gotenbergURL string
+
+func TestMain(m *testing.M) {
+	srv := httptest.NewServer(http.HandlerFunc(fakeGotenberg))
+	gotenbergURL = srv.URL
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// fakeGotenberg answers every route with a small PDF naming the route.
+// In webhook mode the PDF is posted to the webhook URL instead.
+func fakeGotenberg(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	trace := r.Header.Get(gotenberg.HeaderGotenbergTrace)
+	if trace == "" {
+		trace = "example-trace"
+	}
+	pdf := []byte("%PDF-1.7 " + r.URL.Path)
+
+	if webhook := r.Header.Get(gotenberg.HeaderWebhookURL); webhook != "" {
+		go func() {
+			req, _ := http.NewRequest(http.MethodPost, webhook, bytes.NewReader(pdf))
+			req.Header.Set("Content-Type", "application/pdf")
+			req.Header.Set(gotenberg.HeaderGotenbergTrace, trace)
+			if resp, err := http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set(gotenberg.HeaderGotenbergTrace, trace)
+	w.Write(pdf)
+}
+
+// memoryStorage is a gotenberg.Storage keeping objects in memory, showing how
+// storage backends other than MinIO plug into the storage-backed helpers.
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: make(map[string][]byte)}
+}
+
+func (s *memoryStorage) UploadFile(ctx context.Context, name string, r io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[name] = data
+	return &minio.UploadInfo{Key: name, Size: int64(len(data))}, nil
+}
+
+func (s *memoryStorage) DownloadFile(ctx context.Context, name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[name]
+	if !ok {
+		return nil, minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memoryStorage) GetFileInfo(ctx context.Context, name string) (minio.ObjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[name]
+	if !ok {
+		return minio.ObjectInfo{}, minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound}
+	}
+	return minio.ObjectInfo{Key: name, Size: int64(len(data))}, nil
+}
+
+func (s *memoryStorage) DeleteFile(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, name)
+	return nil
+}
@@ -0,0 +1,33 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Merge stored PDFs, in order, into a new stored document.
+func Example_mergeStoredPDFs() {
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	storage.UploadFile(ctx, "statements/cover.pdf", strings.NewReader("%PDF cover"), -1, "application/pdf")
+	storage.UploadFile(ctx, "statements/2026-09.pdf", strings.NewReader("%PDF september"), -1, "application/pdf")
+
+	err = gotenberg.MergeStoredPDFs(ctx, client, storage,
+		[]string{"statements/cover.pdf", "statements/2026-09.pdf"}, "statements/merged.pdf")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	info, _ := storage.GetFileInfo(ctx, "statements/merged.pdf")
+	fmt.Println(info.Key)
+	// Output: statements/merged.pdf
+}
@@ -0,0 +1,34 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Convert an office document to PDF with LibreOffice.
+func Example_convertOffice() {
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	docx := strings.NewReader("PK...") // e.g. an opened report.docx
+
+	resp, err := client.ConvertOffice(context.Background(),
+		gotenberg.NamedReader{Name: "report.docx", Reader: docx}).
+		Landscape(true).
+		Send()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	pdf, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(pdf))
+	// Output: %PDF-1.7 /forms/libreoffice/convert
+}
@@ -0,0 +1,43 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Convert a document kept in storage and store the PDF next to it. MinIO is
+// used the same way with gotenberg.NewMinioClient.
+func Example_storage() {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	storage.UploadFile(ctx, "reports/q3/index.html", strings.NewReader("<h1>Q3</h1>"), -1, "text/html")
+
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL, gotenberg.WithStorage(storage))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := client.FromSpec(ctx, gotenberg.ConversionSpec{
+		Route: gotenberg.ConvertHTML,
+		Files: []gotenberg.FileRef{{Filename: gotenberg.FileIndexHTML, Object: "reports/q3/index.html"}},
+	}).Send()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := resp.Err(); err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	info, err := storage.UploadFile(ctx, "reports/q3/report.pdf", resp.Body, -1, "application/pdf")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(info.Key)
+	// Output: reports/q3/report.pdf
+}
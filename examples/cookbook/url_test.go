@@ -0,0 +1,35 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Convert a web page to a landscape PDF with its backgrounds.
+func Example_convertURL() {
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := client.ConvertURL(context.Background(), "https://example.com").
+		Bool(gotenberg.FieldLandscape, true).
+		Bool(gotenberg.FieldPrintBackground, true).
+		Send()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := resp.Err(); err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	pdf, _ := io.ReadAll(resp.Body)
+	fmt.Println(string(pdf))
+	// Output: %PDF-1.7 /forms/chromium/convert/url
+}
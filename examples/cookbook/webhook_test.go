@@ -0,0 +1,56 @@
+package cookbook_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// Submit a conversion in webhook mode through an Outbox and receive the PDF
+// on the webhook URL.
+func Example_webhook() {
+	client, err := gotenberg.NewClient(http.DefaultClient, gotenbergURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	received := make(chan string, 1)
+	consume := func(ctx context.Context, job gotenberg.Job, result *gotenberg.WebhookResult) error {
+		pdf, err := io.ReadAll(result.Body)
+		received <- string(pdf)
+		return err
+	}
+
+	mux := http.NewServeMux()
+	callbacks := httptest.NewServer(mux)
+	defer callbacks.Close()
+
+	outbox := gotenberg.NewOutbox(client, gotenberg.NewMemoryJobStore(), gotenberg.WebhookSpec{
+		URL:      callbacks.URL + "/success",
+		ErrorURL: callbacks.URL + "/error",
+	})
+	mux.Handle("/success", outbox.ResultHandler(consume))
+	mux.Handle("/error", outbox.ErrorHandler(nil))
+	_, err = outbox.Submit(ctx, gotenberg.ConversionSpec{
+		Route: gotenberg.ConvertHTML,
+		Files: []gotenberg.FileRef{{Filename: gotenberg.FileIndexHTML, Data: []byte("<h1>Invoice</h1>")}},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	select {
+	case pdf := <-received:
+		fmt.Println(pdf)
+	case <-time.After(5 * time.Second):
+		fmt.Println("no result")
+	}
+	// Output: %PDF-1.7 /forms/chromium/convert/html
+}
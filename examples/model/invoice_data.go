@@ -1,27 +1,30 @@
 package model
 
-var InvoiceData = Invoice{
-	InvoiceNumber: "123",
-	CreatedAt:     "January 1, 2023",
-	DueAt:         "February 1, 2023",
-	From: Party{
-		Name:         "Sparksuite, Inc.",
-		Address:      "12345 Sunny Road",
-		CityStateZip: "Sunnyville, TX 12345",
+import (
+	"time"
+
+	"github.com/nativebpm/gotenberg-client/templates"
+)
+
+var InvoiceData = templates.InvoiceData{
+	Number:   "123",
+	IssuedAt: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+	DueAt:    time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC),
+	Currency: "USD",
+	From: templates.Party{
+		Name:    "Sparksuite, Inc.",
+		Address: []string{"12345 Sunny Road", "Sunnyville, TX 12345"},
 	},
-	To: Party{
+	To: templates.Party{
 		Name:    "Acme Corp.",
-		Contact: "John Doe",
+		Address: []string{"John Doe"},
 		Email:   "john@example.com",
 	},
-	Payment: Payment{
-		Method:      "Check",
-		CheckNumber: "1000",
-	},
-	Items: []Item{
-		{Description: "Website design", Price: "$300.00", Last: false},
-		{Description: "Hosting (3 months)", Price: "$75.00", Last: false},
-		{Description: "Domain name (1 year)", Price: "$10.00", Last: true},
+	Items: []templates.LineItem{
+		{Description: "Website design", Quantity: 1, UnitPrice: 300},
+		{Description: "Hosting (3 months)", Quantity: 3, UnitPrice: 25},
+		{Description: "Domain name (1 year)", Quantity: 1, UnitPrice: 10},
 	},
-	Total: "$385.00",
+	PaymentTerms: "Payment by check, check #1000.",
+	Logo:         "logo.png",
 }
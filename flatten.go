@@ -0,0 +1,28 @@
+package gotenberg
+
+import "context"
+
+// FlattenPDF creates a request flattening existing PDFs with Gotenberg's
+// pdfengines flatten route: form fields and annotations become static
+// content, e.g. to freeze a filled-in form. Several PDFs produce a zip, see
+// Response.EachDocument.
+//
+//	client.FlattenPDF(ctx, gotenberg.NamedReader{Name: "form.pdf", Reader: pdf}).
+//		Send()
+func (c *Client) FlattenPDF(ctx context.Context, pdfs ...NamedReader) *Request {
+	r := c.newRequest(ctx, FlattenPDF)
+	if len(pdfs) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	for _, pdf := range pdfs {
+		r.File(FieldFiles, pdf.Name, pdf.Reader)
+	}
+	return r
+}
+
+// Flatten flattens the generated PDF, so form fields and annotations become
+// static content. Gotenberg supports it on the Chromium and LibreOffice
+// conversion routes and on the merge and split routes.
+func (r *Request) Flatten(flatten bool) *Request {
+	return r.Bool(FieldFlatten, flatten)
+}
@@ -0,0 +1,47 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFlattenPDF(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.FlattenPDF(context.Background(),
+		NamedReader{Name: "form.pdf", Reader: strings.NewReader("%PDF-form")},
+		NamedReader{Name: "order.pdf", Reader: strings.NewReader("%PDF-order")}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != FlattenPDF {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	if capture.files["form.pdf"] != "%PDF-form" || capture.files["order.pdf"] != "%PDF-order" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+
+	if _, err := c.FlattenPDF(context.Background()).Send(); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	if _, err := c.ConvertHTML(context.Background(), bytes.NewBufferString("<form></form>")).Flatten(true).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.value(FieldFlatten) != "true" {
+		t.Errorf("expected flatten=true, got %q", capture.value(FieldFlatten))
+	}
+
+	if _, err := c.ConvertOffice(context.Background(), NamedReader{Name: "form.odt", Reader: strings.NewReader("odt")}).Flatten(false).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.value(FieldFlatten) != "false" {
+		t.Errorf("expected flatten=false, got %q", capture.value(FieldFlatten))
+	}
+}
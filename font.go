@@ -0,0 +1,63 @@
+package gotenberg
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// fontFormats maps font file extensions to their CSS format() hint.
+var fontFormats = map[string]string{
+	".ttf":   "truetype",
+	".otf":   "opentype",
+	".woff":  "woff",
+	".woff2": "woff2",
+}
+
+// FontFace returns the @font-face rule declaring family from the attached font file filename.
+func FontFace(family, filename string) string {
+	src := fmt.Sprintf("url(%q)", filename)
+	if format, ok := fontFormats[strings.ToLower(path.Ext(filename))]; ok {
+		src += fmt.Sprintf(" format(%q)", format)
+	}
+	return fmt.Sprintf("@font-face { font-family: %q; src: %s; }", family, src)
+}
+
+// WithFont attaches the font file name to the request and declares it in index.html
+// with a matching @font-face rule. The font family is the file name without its
+// extension, e.g. "Inter-Bold.woff2" declares the family "Inter-Bold".
+// Only HTML based routes read the declaration.
+func (r *Request) WithFont(name string, reader io.Reader) *Request {
+	name = path.Base(filepath.ToSlash(name))
+	r.File(FieldFiles, name, reader)
+	r.appendHTML("<style>" + FontFace(strings.TrimSuffix(name, path.Ext(name)), name) + "</style>")
+	return r
+}
+
+// WithFontFromPath attaches the font file at the given path, see WithFont.
+// The file is closed once the request is sent.
+func (r *Request) WithFontFromPath(name string) *Request {
+	f, err := os.Open(name)
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+	r.closers = append(r.closers, f)
+	return r.WithFont(filepath.Base(name), f)
+}
+
+// WithFontFS attaches the font file name from fsys, see WithFont.
+// The file is closed once the request is sent.
+func (r *Request) WithFontFS(fsys fs.FS, name string) *Request {
+	f, err := fsys.Open(name)
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+	r.closers = append(r.closers, f)
+	return r.WithFont(path.Base(name), f)
+}
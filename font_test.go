@@ -0,0 +1,60 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFontFace(t *testing.T) {
+	got := FontFace("Inter", "Inter.woff2")
+	want := `@font-face { font-family: "Inter"; src: url("Inter.woff2") format("woff2"); }`
+	if got != want {
+		t.Errorf("unexpected rule %s", got)
+	}
+}
+
+func TestWithFont(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithFont("fonts/Inter-Bold.ttf", strings.NewReader("font-data")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.files["Inter-Bold.ttf"] != "font-data" {
+		t.Errorf("font not attached: %v", capture.files)
+	}
+	want := "<html></html>" + `<style>@font-face { font-family: "Inter-Bold"; src: url("Inter-Bold.ttf") format("truetype"); }</style>`
+	if got := capture.files[FileIndexHTML]; got != want {
+		t.Errorf("unexpected index.html %q", got)
+	}
+}
+
+func TestWithFontFS(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	fsys := fstest.MapFS{"fonts/Noto.otf": {Data: []byte("otf")}}
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithFontFS(fsys, "fonts/Noto.otf").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.files["Noto.otf"] != "otf" {
+		t.Errorf("font not attached: %v", capture.files)
+	}
+}
+
+func TestWithFontFromPathMissing(t *testing.T) {
+	c := newTestClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithFontFromPath("/does/not/exist.ttf").
+		Send()
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+}
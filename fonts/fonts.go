@@ -0,0 +1,44 @@
+// Package fonts bundles subsetted fallback fonts for the presets of
+// gotenberg, so that documents render without boxes instead of characters on
+// Gotenberg images lacking the fonts of the preset font stacks. It is a
+// separate package: binaries not importing it do not embed the fonts.
+//
+//	client.ConvertHTML(ctx, html).
+//		WithPreset(fonts.Fallback(gotenberg.PresetKorean)).
+//		Send()
+//
+// The fonts are licensed under the SIL Open Font License, see the license
+// files next to them.
+package fonts
+
+import (
+	"embed"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// FS holds the bundled font files, for Preset.WithFonts and Request.WithFontFS.
+//
+//go:embed *.woff2
+var FS embed.FS
+
+// NanumBarunGothic covers the Hangul syllables and compatibility jamo (390 KiB).
+const NanumBarunGothic = "NanumBarunGothic.woff2"
+
+// fallbacks are the bundled fonts covering the script of a preset, by name.
+// No Arabic, Hebrew or Han font is bundled yet: the presets of these scripts
+// rely on the fonts of the Gotenberg image.
+var fallbacks = map[string][]string{
+	gotenberg.PresetKorean.Name: {NanumBarunGothic},
+}
+
+// Fallback returns a copy of p attaching the bundled fonts covering its
+// script, p itself if none is bundled. Being subsets, they only take
+// precedence over the preset font stack for the characters they cover.
+func Fallback(p gotenberg.Preset) gotenberg.Preset {
+	names, ok := fallbacks[p.Name]
+	if !ok {
+		return p
+	}
+	return p.WithFonts(FS, names...)
+}
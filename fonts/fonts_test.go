@@ -0,0 +1,34 @@
+package fonts
+
+import (
+	"bytes"
+	"io/fs"
+	"strings"
+	"testing"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+func TestBundledFonts(t *testing.T) {
+	for _, names := range fallbacks {
+		for _, name := range names {
+			data, err := fs.ReadFile(FS, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasPrefix(data, []byte("wOF2")) {
+				t.Errorf("%s is not a WOFF2 font", name)
+			}
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	css := Fallback(gotenberg.PresetKorean).CSSRules()
+	if !strings.Contains(css, `font-family: "NanumBarunGothic", "Noto Sans CJK KR"`) {
+		t.Errorf("bundled font not in the font stack: %s", css)
+	}
+	if Fallback(gotenberg.PresetArabic).CSSRules() != gotenberg.PresetArabic.CSSRules() {
+		t.Error("expected presets without bundled fonts to be unchanged")
+	}
+}
@@ -0,0 +1,124 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func FuzzParam(f *testing.F) {
+	for _, seed := range []string{"", "8.27", "Ünïcødé ✓", "line\r\nbreak", "\"quoted\"", "--boundary", "\x00"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		c, capture := newCaptureClient(t)
+		_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+			Param(FieldWaitForExpression, value).
+			Send()
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		if got := capture.value(FieldWaitForExpression); got != value {
+			t.Errorf("sent %q, Gotenberg receives %q", value, got)
+		}
+	})
+}
+
+func FuzzFloat(f *testing.F) {
+	for _, seed := range []float64{0, 8.27, -1, 1e-7, 1e21, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(1), math.NaN()} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, value float64) {
+		c, capture := newCaptureClient(t)
+		_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+			Float(FieldScale, value).
+			Send()
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			if !errors.Is(err, ErrInvalidField) {
+				t.Errorf("expected ErrInvalidField for %v, got %v", value, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		got, err := strconv.ParseFloat(capture.value(FieldScale), 64)
+		if err != nil || got != value {
+			t.Errorf("sent %v, Gotenberg receives %q", value, capture.value(FieldScale))
+		}
+	})
+}
+
+func FuzzFilename(f *testing.F) {
+	for _, seed := range []string{"index.html", "", ".", "..", "../index.html", `C:\index.html`, "résumé.docx", "日本語.html", "a\"b.html", "a\r\nX-Injected: 1", "a\x00b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		c, capture := newCaptureClient(t)
+		_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+			File(FieldFiles, name, strings.NewReader("content")).
+			Send()
+		if errors.Is(err, ErrInvalidFilename) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		// Accepted names reach Gotenberg unchanged
+		if capture.files[name] != "content" {
+			t.Errorf("file %q not received as is: %v", name, capture.files)
+		}
+	})
+}
+
+func FuzzKeyTemplate(f *testing.F) {
+	f.Add("acme", "invoice.pdf", "trace")
+	f.Add("..", "../../etc/passwd", "/")
+	f.Add("a/b", `..\..\x`, ".")
+	f.Fuzz(func(t *testing.T, tenant, filename, trace string) {
+		job := Job{ID: "job", CreatedAt: time.Unix(0, 0), Spec: ConversionSpec{Tenant: tenant}}
+		result := &WebhookResult{Filename: filename, Trace: trace, ContentType: "application/pdf"}
+		key := KeyTemplate("results/{tenant}/{filename}/{trace}").Expand(job, result)
+
+		if !strings.HasPrefix(key, "results/") {
+			t.Fatalf("key %q escapes its prefix", key)
+		}
+		segments := strings.Split(key, "/")
+		if len(segments) > 4 {
+			t.Errorf("placeholder values add path segments: %q", key)
+		}
+		for _, s := range segments {
+			if s == "" || s == "." || s == ".." {
+				t.Errorf("key %q has segment %q", key, s)
+			}
+		}
+	})
+}
+
+func FuzzBaseURL(f *testing.F) {
+	for _, seed := range []string{"http://localhost:3000", "http://localhost:3000/", "https://gotenberg.internal/api//", "http://host?x=1", "http://host#frag", "localhost:3000", "", "http://[::1]:3000/gotenberg"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, baseURL string) {
+		c, err := NewClient(nil, baseURL)
+		if err != nil {
+			return
+		}
+		base, _ := url.Parse(baseURL)
+		u, err := url.Parse(c.baseURL + HealthCheck)
+		if err != nil {
+			t.Fatalf("route joined to %q does not parse: %v", baseURL, err)
+		}
+		if u.Host != base.Host || u.RawQuery != "" || u.Fragment != "" {
+			t.Errorf("route joined to %q targets %s", baseURL, u)
+		}
+		if !strings.HasSuffix(u.Path, HealthCheck) || strings.HasSuffix(u.Path, "/"+HealthCheck) {
+			t.Errorf("route joined to %q has path %q", baseURL, u.Path)
+		}
+	})
+}
@@ -4,9 +4,23 @@ package gotenberg
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
 	"io"
+	"math"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	httpclient "github.com/nativebpm/http-client"
 	"github.com/nativebpm/http-client/request"
@@ -14,97 +28,573 @@ import (
 
 // Client is a Gotenberg HTTP client that wraps the base HTTP client
 // with Gotenberg-specific functionality for document conversion.
+// A Client is safe for concurrent use by multiple goroutines.
 type Client struct {
 	*httpclient.Client
+	httpClient *http.Client
+	baseURL    string
+
+	audit AuditSink
+	quota QuotaChecker
+	usage UsageReporter
+	stage *UploadStrategy
+
+	// locale is the default locale of ConvertTemplate requests
+	locale string
+
+	// storage resolves object references of conversion specs
+	storage Storage
+
+	// traces registers the traces of webhook conversions
+	traces *TraceAllowlist
+
+	// profiles provide the defaults of conversion specs
+	profiles Profiles
+
+	// validators check the inputs of every conversion, see WithInputValidators
+	validators []InputValidator
+
+	// diagnostics receives the bundles of failed conversions, see WithDiagnostics
+	diagnostics DiagnosticSink
+
+	stats clientStats
+
+	// limiter bounds the conversions in flight, see WithConcurrencyLimit
+	limiter      *limiter
+	queueTimeout time.Duration
+
+	panicHandler PanicHandler
+}
+
+// ClientOption configures optional Client features.
+type ClientOption func(*Client)
+
+// WithAuditSink sets the sink receiving an audit record for every conversion.
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(c *Client) {
+		c.audit = sink
+	}
 }
 
 // Request represents a Gotenberg conversion request builder.
 // It wraps the underlying multipart request and provides Gotenberg-specific methods.
+// A Request is used by a single goroutine and sent once.
 type Request struct {
-	req *request.Multipart
-	wh  map[string]string
+	req    *request.Multipart
+	wh     map[string]string
+	client *Client
+	ctx    context.Context
+	route  string
+	tenant string
+	trace  string
+	inputs *hashingInputs
+	upload uploadSize
+	files  []formFile
+
+	// downloads are files Gotenberg fetches itself, see DownloadFrom
+	downloads []downloadFrom
+	// staged are the URLs of the files staged with a StageReleaser
+	staged []string
+
+	// htmlSuffix is appended to index.html when the request is sent
+	htmlSuffix []string
+	// coverHTML is composed before index.html, see WithCoverPage
+	coverHTML    string
+	closers      []io.Closer
+	closeOnce    sync.Once
+	err          error
+	printCSS     int
+	pageBreakCSS bool
+	metadata     map[string]any
+	cookies      []cookie
+	extraHeaders map[string]string
+	splitMode    string
+	splitUnify   bool
+
+	// mergeDocuments merges the documents of office conversions, see MergeDocuments
+	mergeDocuments bool
+
+	// screenshotFormat and screenshotQuality are checked together by Validate
+	screenshotFormat  string
+	screenshotQuality bool
+
+	// template is the lazily executed index.html of ConvertTemplate requests
+	template *templateReader
+
+	// archival is the PDF/A format set by Archival or ArchivePDF
+	archival    string
+	attachments bool
+
+	// merged counts the PDFs added by AddPDF
+	merged int
+
+	checkAssets    bool
+	onUnusedAssets func(names []string)
+
+	// validators check the inputs when the request is sent, see ValidateInputs
+	validators []InputValidator
+
+	// fields are the form fields recorded for WithDiagnostics
+	fields map[string]string
+
+	timings *requestTimings
+
+	// verifyStored makes ConvertAndStore check the stored object
+	verifyStored bool
+
+	webhookURL      string
+	webhookErrorURL string
 }
 
+var (
+	// ErrWebhookErrorURLMissing is returned when a webhook URL is configured
+	// without the matching webhook error URL.
+	ErrWebhookErrorURLMissing = errors.New("gotenberg: webhook URL requires a webhook error URL")
+
+	// ErrWebhookURLMissing is returned when a webhook error URL is configured
+	// without the matching webhook URL.
+	ErrWebhookURLMissing = errors.New("gotenberg: webhook error URL requires a webhook URL")
+
+	// ErrInvalidBaseURL is returned by NewClient for base URLs Gotenberg routes
+	// cannot be appended to.
+	ErrInvalidBaseURL = errors.New("gotenberg: invalid base URL")
+
+	// ErrInvalidField is returned for form field values Gotenberg cannot parse.
+	ErrInvalidField = errors.New("gotenberg: invalid form field value")
+
+	// ErrInvalidFilename is returned for filenames that are empty, contain path
+	// separators or control characters, or are "." or "..".
+	ErrInvalidFilename = errors.New("gotenberg: invalid filename")
+)
+
 // Response represents a Gotenberg conversion response.
 // It wraps the HTTP response and provides access to the Gotenberg trace header.
 type Response struct {
 	*http.Response
 	GotenbergTrace string
+
+	// NoContent reports that the response carries no document.
+	// It is set in webhook mode, where Gotenberg delivers the result
+	// asynchronously to the webhook URL and Body is always empty.
+	NoContent bool
+
+	timings *requestTimings
 }
 
 // NewClient creates a new Gotenberg client with the given HTTP client and base URL.
-// Returns an error if the base URL is invalid.
-func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+// Returns an error if the base URL is invalid: it must be an absolute http or
+// https URL without query or fragment.
+func NewClient(httpClient *http.Client, baseURL string, opts ...ClientOption) (*Client, error) {
+	if err := validateBaseURL(baseURL); err != nil {
+		return nil, err
+	}
 	client, err := httpclient.NewClient(httpClient, baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{
-		Client: client,
-	}, nil
+	c := &Client{
+		Client:     client,
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		audit:      NopAuditSink{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// validateBaseURL checks that Gotenberg routes can be appended to baseURL.
+func validateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBaseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: %q is not an http or https URL", ErrInvalidBaseURL, baseURL)
+	}
+	if u.Host == "" || strings.ContainsAny(baseURL, "?#") {
+		return fmt.Errorf("%w: %q", ErrInvalidBaseURL, baseURL)
+	}
+	return nil
+}
+
+// newRequest creates a request builder for the given Gotenberg route.
+func (c *Client) newRequest(ctx context.Context, route string) *Request {
+	timings := &requestTimings{}
+	r := &Request{
+		req:     c.MultipartPOST(httptrace.WithClientTrace(ctx, timings.clientTrace()), route),
+		client:  c,
+		ctx:     ctx,
+		route:   route,
+		timings: timings,
+	}
+	r.inputs = &hashingInputs{ctx: ctx, hash: sha256.New(), stats: &c.stats, panicked: r.panicked}
+	return r
+}
+
+// panicked reports a panic recovered while the request is streamed.
+func (r *Request) panicked(recovered any) error {
+	return r.client.panicked(r.trace, recovered)
 }
 
 // ConvertHTML creates a request to convert HTML content to PDF.
 // The html parameter should contain the HTML content to be converted.
 func (c *Client) ConvertHTML(ctx context.Context, html io.Reader) *Request {
-	r := &Request{}
-	r.req = c.MultipartPOST(ctx, ConvertHTML).File(FieldFiles, FileIndexHTML, html)
-	return r
+	return c.newRequest(ctx, ConvertHTML).File(FieldFiles, FileIndexHTML, html)
 }
 
 // ConvertURL creates a request to convert a web page at the given URL to PDF.
 func (c *Client) ConvertURL(ctx context.Context, url string) *Request {
-	r := &Request{}
-	r.req = c.MultipartPOST(ctx, ConvertURL).Param(FieldURL, url)
-	return r
+	return c.newRequest(ctx, ConvertURL).Param(FieldURL, url)
+}
+
+// Validate checks the request for configuration errors that Gotenberg would
+// otherwise only report at conversion time.
+// Gotenberg requires the webhook URL and the webhook error URL to be set together.
+func (r *Request) Validate() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.webhookURL != "" && r.webhookErrorURL == "" {
+		return ErrWebhookErrorURLMissing
+	}
+	if r.webhookErrorURL != "" && r.webhookURL == "" {
+		return ErrWebhookURLMissing
+	}
+	if r.route == MergePDF && len(r.files) == 0 && len(r.downloads) == 0 {
+		return ErrNoDocuments
+	}
+	if r.route == WriteMetadata && len(r.metadata) == 0 {
+		return fmt.Errorf("%w: no metadata entries to write", ErrInvalidField)
+	}
+	if err := r.validateScreenshot(); err != nil {
+		return err
+	}
+	if err := r.validateOffice(); err != nil {
+		return err
+	}
+	return r.validateSplit()
 }
 
 // Send executes the conversion request and returns the response.
-// Returns an error if the request fails or the conversion cannot be completed.
+// Returns an error if the request is invalid, fails or the conversion cannot be completed.
+//
+// Cancelling the context of the request aborts Send right away, also while
+// it waits for a slot of the concurrency limit or streams the upload: the
+// slot is released, the files opened for the request are closed and the
+// file readers are no longer read.
 func (r *Request) Send() (*Response, error) {
+	defer r.close()
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	r.orderDocuments()
+
+	if r.client.quota != nil {
+		if err := r.client.quota.CheckQuota(r.ctx, r.tenant, r.route); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validateInputs(); err != nil {
+		return nil, err
+	}
+
+	if r.checkAssets {
+		if err := r.verifyAssets(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.writeMetadata(); err != nil {
+		return nil, err
+	}
+
+	if err := r.writeCookies(); err != nil {
+		return nil, err
+	}
+
+	if err := r.writeExtraHTTPHeaders(); err != nil {
+		return nil, err
+	}
+
+	defer r.releaseStaged()
+	if err := r.attachFiles(); err != nil {
+		return nil, err
+	}
+	r.registerTrace()
+
+	stop := context.AfterFunc(r.ctx, r.close)
+	defer stop()
+	queued := time.Now()
+	release, err := r.client.acquireSlot(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	r.timings.queued, r.timings.sent = queued, start
+	r.client.stats.active.Add(1)
 	resp, err := r.req.Send()
+	r.timings.mark(&r.timings.answered)
+	release(resp)
+	r.inputs.uploaded()
+	r.audit(start, resp, err)
+	r.reportUsage(start, resp, err)
+	r.diagnose(queued, start, resp, err)
 	if err != nil {
+		r.client.stats.active.Add(-1)
 		return nil, err
 	}
+	resp.Body = &activeBody{
+		ReadCloser: &timedBody{ReadCloser: resp.Body, timings: r.timings, stats: &r.client.stats},
+		stats:      &r.client.stats,
+	}
+
+	// rejected requests keep their body, the error message of Gotenberg
+	noContent := resp.StatusCode < http.StatusBadRequest && (r.webhookURL != "" || resp.StatusCode == http.StatusNoContent)
+	if noContent {
+		// Nothing to read in webhook mode: release the connection right away
+		// instead of leaving the body for the caller to buffer.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		resp.Body = http.NoBody
+	}
+
 	return &Response{
 		Response:       resp,
 		GotenbergTrace: resp.Header.Get(HeaderGotenbergTrace),
+		NoContent:      noContent,
+		timings:        r.timings,
 	}, nil
 }
 
-// Header adds a header to the conversion request.
+// close releases the resources opened while building the request.
+// It is called once the request is sent or its context is done, whichever
+// comes first, which unblocks uploads waiting on these resources.
+func (r *Request) close() {
+	r.closeOnce.Do(func() {
+		for _, c := range r.closers {
+			c.Close()
+		}
+	})
+}
+
+// setErr records the first error encountered while building the request.
+// It is returned by Validate and Send.
+func (r *Request) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// appendHTML appends markup to the end of index.html when the request is sent.
+// Browsers move trailing elements into the body, where style and link elements still apply.
+func (r *Request) appendHTML(markup string) {
+	r.htmlSuffix = append(r.htmlSuffix, markup)
+	r.upload.total += int64(len(markup))
+}
+
+// Header adds a header to the conversion request. The trace and webhook URL
+// headers are recorded as by Trace, WebhookURL and WebhookErrorURL, so that
+// the request is handled the same way.
 func (r *Request) Header(key, value string) *Request {
+	switch http.CanonicalHeaderKey(key) {
+	case HeaderGotenbergTrace:
+		return r.Trace(value)
+	case HeaderWebhookURL:
+		r.webhookURL = value
+	case HeaderWebhookErrorURL:
+		r.webhookErrorURL = value
+	}
 	r.req.Header(key, value)
 	return r
 }
 
 // Param adds a form parameter to the conversion request.
 func (r *Request) Param(key, value string) *Request {
+	r.upload.addField(key, value)
+	r.noteField(key, value)
 	r.req.Param(key, value)
 	return r
 }
 
 // Bool adds a boolean form parameter to the conversion request.
 func (r *Request) Bool(fieldName string, value bool) *Request {
+	r.upload.addField(fieldName, strconv.FormatBool(value))
+	r.noteField(fieldName, strconv.FormatBool(value))
 	r.req.Bool(fieldName, value)
 	return r
 }
 
 // Float adds a float64 form parameter to the conversion request.
 func (r *Request) Float(fieldName string, value float64) *Request {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		r.setErr(fmt.Errorf("%w: %s is %v", ErrInvalidField, fieldName, value))
+		return r
+	}
+	r.upload.addField(fieldName, strconv.FormatFloat(value, 'f', -1, 64))
+	r.noteField(fieldName, strconv.FormatFloat(value, 'f', -1, 64))
 	r.req.Float(fieldName, value)
 	return r
 }
 
-// File adds a file to the conversion request.
+// File adds a file to the conversion request. Gotenberg keeps all files in
+// one directory, so filename cannot contain path separators.
 func (r *Request) File(key, filename string, content io.Reader) *Request {
-	r.req.File(key, filename, content)
+	if !validFilename(filename) {
+		r.setErr(fmt.Errorf("%w: %q", ErrInvalidFilename, filename))
+		return r
+	}
+	r.upload.addFile(key, filename, content)
+	r.files = append(r.files, formFile{key: key, filename: filename, content: content})
+	return r
+}
+
+// validFilename reports whether filename reaches Gotenberg unchanged.
+func validFilename(filename string) bool {
+	if filename == "" || filename == "." || filename == ".." || !utf8.ValidString(filename) {
+		return false
+	}
+	for _, c := range filename {
+		if c == '/' || c == '\\' || unicode.IsControl(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tenant sets the tenant the conversion is performed for.
+// It is not sent to Gotenberg and only identifies the caller in audit records
+// and quota accounting, and labels the storage operations of the request,
+// see ContextWithTenant.
+func (r *Request) Tenant(tenant string) *Request {
+	r.tenant = tenant
 	return r
 }
 
+// audit hands the outcome of a conversion to the client's audit sink.
+func (r *Request) audit(start time.Time, resp *http.Response, err error) {
+	record := AuditRecord{
+		Time:       start,
+		Tenant:     r.tenant,
+		Route:      r.route,
+		InputHash:  r.inputs.sum(),
+		Duration:   time.Since(start),
+		OutputSize: -1,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if resp != nil {
+		record.Trace = resp.Header.Get(HeaderGotenbergTrace)
+		record.StatusCode = resp.StatusCode
+		record.OutputSize = resp.ContentLength
+	}
+	r.client.audit.Record(r.ctx, record)
+}
+
+// reportUsage hands the resources consumed by a conversion to the client's usage reporter.
+func (r *Request) reportUsage(start time.Time, resp *http.Response, err error) {
+	if r.client.usage == nil {
+		return
+	}
+
+	usage := Usage{
+		Time:        start,
+		Tenant:      r.tenant,
+		Route:       r.route,
+		InputBytes:  r.inputs.size(),
+		OutputBytes: -1,
+		Failed:      err != nil,
+	}
+	if resp != nil {
+		usage.OutputBytes = resp.ContentLength
+		usage.Failed = usage.Failed || resp.StatusCode >= http.StatusBadRequest
+	}
+	r.client.usage.ReportUsage(r.ctx, usage)
+}
+
+// hashingInputs computes a digest over all files uploaded with a request.
+// Files are hashed while the multipart body is streamed. Reading stops once
+// ctx is done, so an abandoned upload does not keep consuming its sources.
+type hashingInputs struct {
+	ctx  context.Context
+	mu   sync.Mutex
+	hash hash.Hash
+	n    int64
+
+	// stats counts the bytes read in UploadBytes until the upload is done
+	stats *clientStats
+	done  bool
+
+	// panicked reports panics of the file readers, which are read by the
+	// goroutine streaming the multipart body
+	panicked func(recovered any) error
+}
+
+func (h *hashingInputs) reader(content io.Reader) io.Reader {
+	return &hashingReader{r: content, h: h}
+}
+
+func (h *hashingInputs) size() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.n
+}
+
+// uploaded removes the bytes read so far from UploadBytes and stops counting.
+func (h *hashingInputs) uploaded() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stats != nil && !h.done {
+		h.stats.uploadBytes.Add(-h.n)
+	}
+	h.done = true
+}
+
+func (h *hashingInputs) sum() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return hex.EncodeToString(h.hash.Sum(nil))
+}
+
+type hashingReader struct {
+	r io.Reader
+	h *hashingInputs
+}
+
+func (hr *hashingReader) Read(p []byte) (n int, err error) {
+	if hr.h.panicked != nil {
+		defer func() {
+			if v := recover(); v != nil {
+				n, err = 0, hr.h.panicked(v)
+			}
+		}()
+	}
+	if hr.h.ctx != nil {
+		if err := hr.h.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = hr.r.Read(p)
+	if n > 0 {
+		hr.h.mu.Lock()
+		hr.h.hash.Write(p[:n])
+		hr.h.n += int64(n)
+		if hr.h.stats != nil && !hr.h.done {
+			hr.h.stats.uploadBytes.Add(int64(n))
+		}
+		hr.h.mu.Unlock()
+	}
+	return n, err
+}
+
 // WebhookURL sets the webhook URL and HTTP method for successful conversions.
 func (r *Request) WebhookURL(url, method string) *Request {
+	r.webhookURL = url
 	r.req.Header(HeaderWebhookURL, url).
 		Header(HeaderWebhookMethod, method)
 	return r
@@ -112,6 +602,7 @@ func (r *Request) WebhookURL(url, method string) *Request {
 
 // WebhookErrorURL sets the webhook URL and HTTP method for failed conversions.
 func (r *Request) WebhookErrorURL(url, method string) *Request {
+	r.webhookErrorURL = url
 	r.req.Header(HeaderWebhookErrorURL, url).
 		Header(HeaderWebhookErrorMethod, method)
 	return r
@@ -138,9 +629,8 @@ func (r *Request) OutputFilename(filename string) *Request {
 
 // PaperSize sets the paper size for the PDF using width and height in inches.
 func (r *Request) PaperSize(width, height float64) *Request {
-	r.req.Float(FieldPaperWidth, width)
-	r.req.Float(FieldPaperHeight, height)
-	return r
+	return r.Float(FieldPaperWidth, width).
+		Float(FieldPaperHeight, height)
 }
 
 // PaperSizeA4 sets the paper size to A4 format.
@@ -161,9 +651,14 @@ func (r *Request) PaperSizeLetter() *Request {
 // Margins sets the page margins for the PDF in inches.
 // Parameters are in order: top, right, bottom, left.
 func (r *Request) Margins(top, right, bottom, left float64) *Request {
-	r.req.Float(FieldMarginTop, top)
-	r.req.Float(FieldMarginRight, right)
-	r.req.Float(FieldMarginBottom, bottom)
-	r.req.Float(FieldMarginLeft, left)
-	return r
+	return r.Float(FieldMarginTop, top).
+		Float(FieldMarginRight, right).
+		Float(FieldMarginBottom, bottom).
+		Float(FieldMarginLeft, left)
+}
+
+// EmulatedMediaType sets the CSS media type used to render the page,
+// MediaTypePrint (Gotenberg's default) or MediaTypeScreen.
+func (r *Request) EmulatedMediaType(mediaType string) *Request {
+	return r.Param(FieldEmulatedMediaType, mediaType)
 }
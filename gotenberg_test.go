@@ -3,9 +3,12 @@ package gotenberg
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -27,6 +30,54 @@ func (m *mockRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
+// captureRoundTripper records the multipart form of the last request
+type captureRoundTripper struct {
+	mu     sync.Mutex
+	path   string
+	header http.Header
+	form   *multipart.Form
+	files  map[string]string
+}
+
+func (c *captureRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.path = req.URL.Path
+	c.header = req.Header.Clone()
+	c.files = make(map[string]string)
+	if err := req.ParseMultipartForm(32 << 20); err == nil {
+		c.form = req.MultipartForm
+		for _, headers := range req.MultipartForm.File {
+			for _, fh := range headers {
+				f, _ := fh.Open()
+				data, _ := io.ReadAll(f)
+				f.Close()
+				c.files[fh.Filename] = string(data)
+			}
+		}
+	}
+	return (&mockRoundTripper{}).RoundTrip(req)
+}
+
+// value returns the first value of a captured form field
+func (c *captureRoundTripper) value(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.form == nil || len(c.form.Value[key]) == 0 {
+		return ""
+	}
+	return c.form.Value[key][0]
+}
+
+func newCaptureClient(t *testing.T, opts ...ClientOption) (*Client, *captureRoundTripper) {
+	capture := &captureRoundTripper{}
+	cli, err := NewClient(&http.Client{Transport: capture}, "http://localhost", opts...)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return cli, capture
+}
+
 func newTestClient(t *testing.T) *Client {
 	httpCli := &http.Client{Transport: &mockRoundTripper{}}
 	cli, err := NewClient(httpCli, "http://localhost")
@@ -104,13 +155,79 @@ func TestRequestHeaderParamBoolFloatFile(t *testing.T) {
 func TestWebhookURL(t *testing.T) {
 	c := newTestClient(t)
 	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
-	r.WebhookURL("http://webhook", "POST")
+	r.WebhookURL("http://webhook", "POST").WebhookErrorURL("http://err", "POST")
 	_, err := r.Send()
 	if err != nil {
 		t.Errorf("Send failed: %v", err)
 	}
 }
 
+func TestWebhookNoContent(t *testing.T) {
+	c := newTestClient(t)
+	resp, err := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>")).
+		WebhookURL("http://webhook", "POST").
+		WebhookErrorURL("http://err", "POST").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if !resp.NoContent {
+		t.Error("expected NoContent in webhook mode")
+	}
+	if resp.Body != http.NoBody {
+		t.Error("expected empty body in webhook mode")
+	}
+}
+
+// rejectingRoundTripper answers every request with a Gotenberg 400 error.
+type rejectingRoundTripper struct{}
+
+func (rejectingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	io.Copy(io.Discard, req.Body)
+	req.Body.Close()
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"status": 400, "message": "Invalid form data: no index.html"}`)),
+	}, nil
+}
+
+func TestWebhookRejectedKeepsMessage(t *testing.T) {
+	c, err := NewClient(&http.Client{Transport: rejectingRoundTripper{}}, "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>")).
+		WebhookURL("http://webhook", "POST").
+		WebhookErrorURL("http://err", "POST").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if resp.NoContent {
+		t.Error("rejected webhook request reported as NoContent")
+	}
+	if err := resp.Err(); err == nil || !strings.Contains(err.Error(), "no index.html") {
+		t.Errorf("expected Gotenberg's message, got %v", err)
+	}
+
+	store := NewMemoryJobStore()
+	job, _ := NewOutbox(c, store, testWebhook).Submit(context.Background(), testSpec())
+	if saved, _ := store.Get(context.Background(), job.ID); !strings.Contains(saved.Error, "no index.html") {
+		t.Errorf("job error %q, want Gotenberg's message", saved.Error)
+	}
+}
+
+func TestWebhookURLWithoutErrorURL(t *testing.T) {
+	c := newTestClient(t)
+	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
+	r.WebhookURL("http://webhook", "POST")
+	_, err := r.Send()
+	if !errors.Is(err, ErrWebhookErrorURLMissing) {
+		t.Errorf("expected ErrWebhookErrorURLMissing, got %v", err)
+	}
+}
+
 func TestOutputFilename(t *testing.T) {
 	c := newTestClient(t)
 	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
@@ -124,13 +241,22 @@ func TestOutputFilename(t *testing.T) {
 func TestWebhookErrorURL(t *testing.T) {
 	c := newTestClient(t)
 	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
-	r.WebhookErrorURL("http://err", "PUT")
+	r.WebhookURL("http://webhook", "PUT").WebhookErrorURL("http://err", "PUT")
 	_, err := r.Send()
 	if err != nil {
 		t.Errorf("Send failed: %v", err)
 	}
 }
 
+func TestWebhookErrorURLWithoutURL(t *testing.T) {
+	c := newTestClient(t)
+	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
+	r.WebhookErrorURL("http://err", "PUT")
+	if err := r.Validate(); !errors.Is(err, ErrWebhookURLMissing) {
+		t.Errorf("expected ErrWebhookURLMissing, got %v", err)
+	}
+}
+
 func TestWebhookHeaders(t *testing.T) {
 	c := newTestClient(t)
 	r := c.ConvertHTML(context.Background(), bytes.NewBufferString("<html></html>"))
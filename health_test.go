@@ -0,0 +1,44 @@
+package gotenberg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != Version {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("8.11.0\n"))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := c.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if version != "8.11.0" {
+		t.Errorf("unexpected version %q", version)
+	}
+}
+
+func TestVersionFailure(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Version(context.Background()); err == nil {
+		t.Fatal("expected error for a failed version query")
+	}
+}
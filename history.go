@@ -0,0 +1,152 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats of the conversion history exports.
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+)
+
+// HistoryFilter selects the conversions of a history export. Zero fields
+// do not filter.
+type HistoryFilter struct {
+	// From and To bound the time of the conversions, From included and To excluded.
+	From, To time.Time
+	Tenant   string
+	// Status is JobCompleted for successful conversions, JobFailed for failed ones.
+	Status JobStatus
+}
+
+func (f HistoryFilter) matches(record AuditRecord) bool {
+	return (f.From.IsZero() || !record.Time.Before(f.From)) &&
+		(f.To.IsZero() || record.Time.Before(f.To)) &&
+		(f.Tenant == "" || record.Tenant == f.Tenant) &&
+		(f.Status == "" || recordStatus(record) == f.Status)
+}
+
+// recordStatus returns JobFailed for conversions that failed or were
+// answered with an error status, JobCompleted otherwise.
+func recordStatus(record AuditRecord) JobStatus {
+	if record.Error != "" || record.StatusCode >= 400 {
+		return JobFailed
+	}
+	return JobCompleted
+}
+
+// historyEntry is a conversion of a JSON history export.
+type historyEntry struct {
+	AuditRecord
+	Status JobStatus `json:"status"`
+}
+
+var historyColumns = []string{"time", "tenant", "route", "status", "status_code", "trace", "input_hash", "duration_ms", "output_size", "error"}
+
+// ExportHistory writes the records matching filter, oldest first, to w in
+// format, ExportCSV or ExportJSON, e.g. for reconciliation or billing.
+// CSV exports start with a header row; JSON exports are an array of the
+// records with their status.
+func ExportHistory(w io.Writer, format string, records []AuditRecord, filter HistoryFilter) error {
+	var selected []AuditRecord
+	for _, record := range records {
+		if filter.matches(record) {
+			selected = append(selected, record)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Time.Before(selected[j].Time) })
+
+	switch format {
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(historyColumns)
+		for _, r := range selected {
+			cw.Write([]string{
+				r.Time.UTC().Format(time.RFC3339Nano),
+				r.Tenant,
+				r.Route,
+				string(recordStatus(r)),
+				strconv.Itoa(r.StatusCode),
+				r.Trace,
+				r.InputHash,
+				strconv.FormatInt(r.Duration.Milliseconds(), 10),
+				strconv.FormatInt(r.OutputSize, 10),
+				r.Error,
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	case ExportJSON:
+		entries := make([]historyEntry, 0, len(selected))
+		for _, r := range selected {
+			entries = append(entries, historyEntry{AuditRecord: r, Status: recordStatus(r)})
+		}
+		return json.NewEncoder(w).Encode(entries)
+	}
+	return fmt.Errorf("gotenberg: unsupported export format %q", format)
+}
+
+// Export writes the stored records matching filter to w, see ExportHistory.
+// The storage of the sink must implement Lister. Days outside the time range
+// of filter are skipped without reading their records.
+func (s *StorageAuditSink) Export(ctx context.Context, w io.Writer, format string, filter HistoryFilter) error {
+	lister, ok := s.Storage.(Lister)
+	if !ok {
+		return fmt.Errorf("gotenberg: audit storage %T cannot list records", s.Storage)
+	}
+
+	prefix := s.Prefix
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+	var records []AuditRecord
+	for object := range lister.ListFiles(ctx, prefix) {
+		if object.Err != nil {
+			return object.Err
+		}
+		if !filter.includesDay(strings.TrimPrefix(path.Dir(object.Key), prefix)) {
+			continue
+		}
+		record, err := s.read(ctx, object.Key)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+	return ExportHistory(w, format, records, filter)
+}
+
+// includesDay reports whether the day directory YYYY/MM/DD of a stored record
+// may hold records in the time range of f.
+func (f HistoryFilter) includesDay(dir string) bool {
+	day, err := time.Parse("2006/01/02", dir)
+	if err != nil {
+		// Not written by the sink, let the record decide
+		return true
+	}
+	return (f.From.IsZero() || day.Add(24*time.Hour).After(f.From)) &&
+		(f.To.IsZero() || day.Before(f.To))
+}
+
+func (s *StorageAuditSink) read(ctx context.Context, object string) (AuditRecord, error) {
+	content, err := s.Storage.DownloadFile(ctx, object)
+	if err != nil {
+		return AuditRecord{}, err
+	}
+	defer content.Close()
+	var record AuditRecord
+	if err := json.NewDecoder(content).Decode(&record); err != nil {
+		return AuditRecord{}, fmt.Errorf("gotenberg: decode audit record %s: %w", object, err)
+	}
+	return record, nil
+}
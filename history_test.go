@@ -0,0 +1,76 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func historyRecords() []AuditRecord {
+	day := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	return []AuditRecord{
+		{Time: day.Add(30 * time.Hour), Tenant: "acme", Route: ConvertHTML, Trace: "t2", StatusCode: 200, Duration: 1500 * time.Millisecond, OutputSize: 2048},
+		{Time: day.Add(2 * time.Hour), Tenant: "acme", Route: ConvertURL, Trace: "t1", StatusCode: 200, Duration: 800 * time.Millisecond, OutputSize: 1024},
+		{Time: day.Add(50 * time.Hour), Tenant: "acme", Route: ConvertHTML, Trace: "t3", StatusCode: 400, Error: "bad html"},
+		{Time: day.Add(3 * time.Hour), Tenant: "globex", Route: ConvertHTML, Trace: "t4", StatusCode: 200},
+	}
+}
+
+func TestExportHistoryCSV(t *testing.T) {
+	var out bytes.Buffer
+	if err := ExportHistory(&out, ExportCSV, historyRecords(), HistoryFilter{Tenant: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "time,tenant,route,status,status_code,trace,input_hash,duration_ms,output_size,error\n" +
+		"2026-09-01T02:00:00Z,acme,/forms/chromium/convert/url,completed,200,t1,,800,1024,\n" +
+		"2026-09-02T06:00:00Z,acme,/forms/chromium/convert/html,completed,200,t2,,1500,2048,\n" +
+		"2026-09-03T02:00:00Z,acme,/forms/chromium/convert/html,failed,400,t3,,0,0,bad html\n"
+	if out.String() != want {
+		t.Errorf("unexpected export:\n%s", out.String())
+	}
+}
+
+func TestExportHistoryJSON(t *testing.T) {
+	var out bytes.Buffer
+	filter := HistoryFilter{Status: JobFailed}
+	if err := ExportHistory(&out, ExportJSON, historyRecords(), filter); err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0]["trace"] != "t3" || entries[0]["status"] != "failed" || entries[0]["error"] != "bad html" {
+		t.Errorf("unexpected export %s", out.String())
+	}
+
+	if err := ExportHistory(&out, "xml", nil, filter); err == nil {
+		t.Error("expected error for an unsupported format")
+	}
+}
+
+func TestStorageAuditSinkExport(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	sink := &StorageAuditSink{Storage: storage, Prefix: "audit"}
+	for _, record := range historyRecords() {
+		sink.Record(ctx, record)
+	}
+	storage.UploadFile(ctx, "auditing/other.json", strings.NewReader("{}"), 2, "application/json")
+
+	var out bytes.Buffer
+	filter := HistoryFilter{
+		From: time.Date(2026, 9, 1, 3, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 9, 3, 0, 0, 0, 0, time.UTC),
+	}
+	if err := sink.Export(ctx, &out, ExportCSV, filter); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], ",t4,") || !strings.Contains(lines[2], ",t2,") {
+		t.Errorf("unexpected export:\n%s", out.String())
+	}
+}
@@ -0,0 +1,183 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// HeaderRequestID identifies a request in error responses when it carries no Gotenberg trace.
+const HeaderRequestID = "X-Request-Id"
+
+// ErrorCode classifies the errors answered by the HTTP handlers of this package.
+type ErrorCode string
+
+const (
+	CodeBadRequest       ErrorCode = "bad_request"
+	CodeUnauthorized     ErrorCode = "unauthorized"
+	CodeForbidden        ErrorCode = "forbidden"
+	CodeNotFound         ErrorCode = "not_found"
+	CodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	CodeConflict         ErrorCode = "conflict"
+	CodePayloadTooLarge  ErrorCode = "payload_too_large"
+	// CodeUnsupportedMediaType is an upload of a content type not accepted.
+	CodeUnsupportedMediaType ErrorCode = "unsupported_media_type"
+	// CodeMalwareDetected is an upload found infected by the Scanner.
+	CodeMalwareDetected ErrorCode = "malware_detected"
+	CodeRateLimited     ErrorCode = "rate_limited"
+	CodeQuotaExceeded   ErrorCode = "quota_exceeded"
+	// CodeConversionFailed is a conversion rejected by Gotenberg, e.g. invalid HTML or options.
+	CodeConversionFailed ErrorCode = "conversion_failed"
+	// CodeUpstream is a failure of Gotenberg itself or of reaching it.
+	CodeUpstream ErrorCode = "upstream_error"
+	// CodeUnavailable is a service temporarily not accepting requests, e.g. while draining.
+	CodeUnavailable    ErrorCode = "unavailable"
+	CodeTimeout        ErrorCode = "timeout"
+	CodeNotImplemented ErrorCode = "not_implemented"
+	CodeInternal       ErrorCode = "internal_error"
+)
+
+// ErrorResponse is the JSON envelope of all errors answered by the HTTP
+// handlers of this package. Error is the human readable message, Trace the
+// Gotenberg trace or request ID of the failed request.
+type ErrorResponse struct {
+	Success bool      `json:"success"`
+	Code    ErrorCode `json:"code"`
+	Error   string    `json:"error"`
+	Trace   string    `json:"trace,omitempty"`
+}
+
+// ErrorStatus maps err to the HTTP status and code it is answered with by the
+// handlers of this package. Unknown errors are internal errors.
+func ErrorStatus(err error) (int, ErrorCode) {
+	return errorStatus(err, http.StatusInternalServerError)
+}
+
+// WriteError answers r with err in the ErrorResponse envelope, so that custom
+// handlers mounted next to the ones of this package report errors the same way.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := ErrorStatus(err)
+	writeError(w, status, code, err.Error(), errorTrace(r, err))
+}
+
+// errorStatus maps the errors known to this package, and err to fallback otherwise.
+func errorStatus(err error, fallback int) (int, ErrorCode) {
+	var (
+		tooLarge  *http.MaxBytesError
+		gotenberg *GotenbergError
+		storage   minio.ErrorResponse
+	)
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge, CodePayloadTooLarge
+	case errors.As(err, &gotenberg):
+		if gotenberg.StatusCode >= 400 && gotenberg.StatusCode < 500 {
+			return http.StatusUnprocessableEntity, CodeConversionFailed
+		}
+		return http.StatusBadGateway, CodeUpstream
+	case errors.As(err, &storage) && storage.StatusCode == http.StatusNotFound:
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, ErrJobNotFound):
+		return http.StatusNotFound, CodeNotFound
+	case errors.Is(err, ErrInvalidDownloadToken), errors.Is(err, ErrDownloadTokenExpired):
+		return http.StatusForbidden, CodeForbidden
+	case errors.Is(err, ErrJobFinished), errors.Is(err, ErrJobChanged):
+		return http.StatusConflict, CodeConflict
+	case errors.Is(err, ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType, CodeUnsupportedMediaType
+	case errors.Is(err, ErrMalwareDetected):
+		return http.StatusUnprocessableEntity, CodeMalwareDetected
+	case errors.Is(err, ErrQuotaExceeded):
+		return http.StatusTooManyRequests, CodeQuotaExceeded
+	case errors.Is(err, ErrInvalidSpec), errors.Is(err, ErrMissingAsset), errors.Is(err, ErrUnsupportedPDFA),
+		errors.Is(err, ErrWebhookURLMissing), errors.Is(err, ErrWebhookErrorURLMissing), errors.Is(err, ErrNoObjects),
+		errors.Is(err, ErrNoDocuments), errors.Is(err, ErrInputRejected),
+		errors.Is(err, ErrInvalidField), errors.Is(err, ErrInvalidFilename):
+		return http.StatusBadRequest, CodeBadRequest
+	case errors.Is(err, ErrNoStorage):
+		return http.StatusNotImplemented, CodeNotImplemented
+	case errors.Is(err, ErrClientQueueTimeout):
+		return http.StatusServiceUnavailable, CodeUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, CodeTimeout
+	}
+	return fallback, statusErrorCode(fallback)
+}
+
+// statusErrorCode is the default code of an HTTP error status.
+func statusErrorCode(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return CodeConversionFailed
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway:
+		return CodeUpstream
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	}
+	return CodeInternal
+}
+
+// errorTrace returns the Gotenberg trace of err or r, or the request ID of r.
+func errorTrace(r *http.Request, err error) string {
+	var gotenberg *GotenbergError
+	if errors.As(err, &gotenberg) && gotenberg.Trace != "" {
+		return gotenberg.Trace
+	}
+	if r == nil {
+		return ""
+	}
+	if trace := r.Header.Get(HeaderGotenbergTrace); trace != "" {
+		return trace
+	}
+	return r.Header.Get(HeaderRequestID)
+}
+
+// writeErrorResponse answers r with message and the default code of status.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeError(w, status, statusErrorCode(status), message, errorTrace(r, nil))
+}
+
+// writeErrorCause answers r with message and err, mapped by errorStatus to
+// its status and code, or fallback.
+func writeErrorCause(w http.ResponseWriter, r *http.Request, fallback int, message string, err error) {
+	status, code := errorStatus(err, fallback)
+	writeError(w, status, code, message+": "+err.Error(), errorTrace(r, err))
+}
+
+// writeError writes an error response in JSON format
+func writeError(w http.ResponseWriter, status int, code ErrorCode, message, trace string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Success: false,
+		Code:    code,
+		Error:   message,
+		Trace:   trace,
+	})
+}
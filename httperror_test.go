@@ -0,0 +1,58 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   ErrorCode
+	}{
+		{&GotenbergError{StatusCode: 400, Message: "invalid form"}, http.StatusUnprocessableEntity, CodeConversionFailed},
+		{&GotenbergError{StatusCode: 503}, http.StatusBadGateway, CodeUpstream},
+		{fmt.Errorf("send: %w", ErrQuotaExceeded), http.StatusTooManyRequests, CodeQuotaExceeded},
+		{fmt.Errorf("%w: abc", ErrJobNotFound), http.StatusNotFound, CodeNotFound},
+		{ErrDownloadTokenExpired, http.StatusForbidden, CodeForbidden},
+		{errNotFound, http.StatusNotFound, CodeNotFound},
+		{&http.MaxBytesError{Limit: 1}, http.StatusRequestEntityTooLarge, CodePayloadTooLarge},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, CodeTimeout},
+		{errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+	for _, tt := range tests {
+		status, code := ErrorStatus(tt.err)
+		if status != tt.status || code != tt.code {
+			t.Errorf("ErrorStatus(%v) = %d %s, want %d %s", tt.err, status, code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/convert", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	WriteError(rec, req, &GotenbergError{StatusCode: 400, Message: "invalid form", Trace: "trace-1"})
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusUnprocessableEntity || resp.Success || resp.Code != CodeConversionFailed || resp.Trace != "trace-1" {
+		t.Errorf("unexpected response %d %+v", rec.Code, resp)
+	}
+
+	rec = httptest.NewRecorder()
+	writeErrorResponse(rec, req, http.StatusMethodNotAllowed, "Method not allowed")
+	resp = ErrorResponse{}
+	json.NewDecoder(rec.Body).Decode(&resp)
+	if resp.Code != CodeMethodNotAllowed || resp.Trace != "req-1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
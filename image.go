@@ -0,0 +1,90 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"image"
+	"image/png"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/nativebpm/gotenberg-client/qrcode"
+)
+
+// QRCodeScale is the number of pixels per module of QR codes attached by WithQRCode.
+const QRCodeScale = 8
+
+// WithImage encodes img as PNG and attaches it to the request as filename,
+// so the document can reference it, e.g. with ImageTag.
+func (r *Request) WithImage(filename string, img image.Image) *Request {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		r.setErr(fmt.Errorf("encode image %s: %w", filename, err))
+		return r
+	}
+	return r.File(FieldFiles, filename, &buf)
+}
+
+// WithQRCode renders content as a QR code with medium error correction and
+// attaches it as the PNG image filename, see WithImage.
+func (r *Request) WithQRCode(filename, content string) *Request {
+	code, err := qrcode.Encode(content, qrcode.Medium)
+	if err != nil {
+		r.setErr(fmt.Errorf("encode QR code %s: %w", filename, err))
+		return r
+	}
+	return r.WithImage(filename, code.Image(QRCodeScale))
+}
+
+// ImageTag returns an <img> element referencing the attached image filename,
+// for use in documents and templates.
+func ImageTag(filename, alt string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<img src="%s" alt="%s">`,
+		template.HTMLEscapeString(filename), template.HTMLEscapeString(alt)))
+}
+
+// imageExtensions are the image formats ConvertImages accepts.
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".svg": true, ".bmp": true,
+}
+
+// imagePagesStyle centers every image on its own page, scaled down to fit it.
+const imagePagesStyle = `html, body { margin: 0; }
+.page { height: 100vh; display: flex; align-items: center; justify-content: center; break-after: page; }
+.page:last-child { break-after: auto; }
+.page img { max-width: 100%; max-height: 100%; object-fit: contain; }`
+
+// ConvertImages creates a request converting images, e.g. scans or photos,
+// to a PDF with one image per page, in order. Images are centered and scaled
+// down to fit the page; set PaperSize, Landscape and Margins as for
+// ConvertHTML:
+//
+//	client.ConvertImages(ctx,
+//		gotenberg.NamedReader{Name: "page1.jpg", Reader: scan1},
+//		gotenberg.NamedReader{Name: "page2.jpg", Reader: scan2}).
+//		Margins(0, 0, 0, 0).
+//		Send()
+//
+// Images must have a png, jpg, jpeg, gif, webp, svg or bmp extension.
+func (c *Client) ConvertImages(ctx context.Context, images ...NamedReader) *Request {
+	r := c.newRequest(ctx, ConvertHTML)
+	if len(images) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><style>" + imagePagesStyle + "</style></head><body>\n")
+	for _, img := range images {
+		if !imageExtensions[strings.ToLower(path.Ext(img.Name))] {
+			r.setErr(fmt.Errorf("%w: %q is not an image", ErrInvalidFilename, img.Name))
+			continue
+		}
+		fmt.Fprintf(&page, "<div class=\"page\"><img src=\"%s\" alt=\"%s\"></div>\n",
+			template.HTMLEscapeString(url.PathEscape(img.Name)), template.HTMLEscapeString(img.Name))
+		r.File(FieldFiles, img.Name, img.Reader)
+	}
+	page.WriteString("</body></html>\n")
+	return r.File(FieldFiles, FileIndexHTML, strings.NewReader(page.String()))
+}
@@ -0,0 +1,95 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/nativebpm/gotenberg-client/qrcode"
+)
+
+func TestWithQRCode(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader(`<html>`+string(ImageTag("qr.png", "Pay"))+`</html>`)).
+		WithQRCode("qr.png", "https://example.com/invoices/42").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	img, err := png.Decode(strings.NewReader(capture.files["qr.png"]))
+	if err != nil {
+		t.Fatalf("attachment is not a PNG: %v", err)
+	}
+	code, _ := qrcode.Encode("https://example.com/invoices/42", qrcode.Medium)
+	if want := (code.Size + 8) * QRCodeScale; img.Bounds().Dx() != want {
+		t.Errorf("unexpected width %d, want %d", img.Bounds().Dx(), want)
+	}
+	if !strings.Contains(capture.files[FileIndexHTML], `<img src="qr.png" alt="Pay">`) {
+		t.Errorf("unexpected index.html %q", capture.files[FileIndexHTML])
+	}
+}
+
+func TestWithQRCodeTooLong(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithQRCode("qr.png", strings.Repeat("x", 3000)).
+		Send()
+	if !errors.Is(err, qrcode.ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestWithImage(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithImage("chart.png", image.NewRGBA(image.Rect(0, 0, 3, 2))).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader([]byte(capture.files["chart.png"])))
+	if err != nil || cfg.Width != 3 || cfg.Height != 2 {
+		t.Errorf("unexpected image %+v: %v", cfg, err)
+	}
+}
+
+func TestImageTagEscapes(t *testing.T) {
+	got := ImageTag(`a"b.png`, "<logo>")
+	if want := `<img src="a&#34;b.png" alt="&lt;logo&gt;">`; string(got) != want {
+		t.Errorf("unexpected tag %s", got)
+	}
+}
+
+func TestConvertImages(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertImages(context.Background(),
+		NamedReader{Name: "scan 1.jpg", Reader: strings.NewReader("jpg")},
+		NamedReader{Name: "photo.PNG", Reader: strings.NewReader("png")}).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != ConvertHTML || capture.files["scan 1.jpg"] != "jpg" || capture.files["photo.PNG"] != "png" {
+		t.Errorf("unexpected request %s with files %v", capture.path, capture.files)
+	}
+	index := capture.files[FileIndexHTML]
+	first, second := strings.Index(index, `src="scan%201.jpg"`), strings.Index(index, `src="photo.PNG"`)
+	if first < 0 || second < first || strings.Count(index, `class="page"`) != 2 {
+		t.Errorf("unexpected index.html %q", index)
+	}
+
+	for _, r := range []*Request{
+		c.ConvertImages(context.Background()),
+		c.ConvertImages(context.Background(), NamedReader{Name: "notes.txt", Reader: strings.NewReader("txt")}),
+	} {
+		if _, err := r.Send(); !errors.Is(err, ErrNoDocuments) && !errors.Is(err, ErrInvalidFilename) {
+			t.Errorf("expected ErrNoDocuments or ErrInvalidFilename, got %v", err)
+		}
+	}
+}
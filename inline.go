@@ -0,0 +1,50 @@
+package gotenberg
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// inlineType reports whether documents of contentType may be displayed by the
+// browser with WithInlineDisposition and WithProxyInline. Other content, HTML
+// in particular, stays an attachment, so stored files cannot run scripts in
+// the origin of the application.
+func inlineType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/pdf", "image/png", "image/jpeg", "image/webp":
+		return true
+	}
+	return false
+}
+
+// setInline makes the document of h, named filename, open in the browser
+// viewer when its type allows it, with caching headers letting the viewer
+// revalidate it for maxAge.
+func setInline(h http.Header, filename string, maxAge time.Duration) {
+	if !inlineType(h.Get("Content-Type")) {
+		return
+	}
+	if filename != "" {
+		h.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	} else {
+		h.Set("Content-Disposition", "inline")
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+	if maxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds())))
+	} else {
+		h.Set("Cache-Control", "private, no-cache")
+	}
+}
+
+// dispositionFilename returns the filename of a Content-Disposition header.
+func dispositionFilename(disposition string) string {
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		return ""
+	}
+	return params["filename"]
+}
@@ -0,0 +1,51 @@
+package gotenberg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetInline(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "application/pdf")
+	h.Set("Content-Disposition", `attachment; filename="report.pdf"`)
+	setInline(h, "report.pdf", time.Hour)
+	if h.Get("Content-Disposition") != "inline; filename=report.pdf" || h.Get("Cache-Control") != "private, max-age=3600" ||
+		h.Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("unexpected headers %v", h)
+	}
+
+	h = http.Header{}
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	h.Set("Content-Disposition", `attachment; filename="page.html"`)
+	setInline(h, "page.html", time.Hour)
+	if h.Get("Content-Disposition") != `attachment; filename="page.html"` || h.Get("Cache-Control") != "" {
+		t.Errorf("HTML served inline: %v", h)
+	}
+}
+
+func TestProxyInline(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, proxy := range []*Proxy{
+		NewProxy(c, WithProxyInline(0)),
+		NewProxy(c, WithProxyInline(0), WithProxyCache(newMemoryStorage(), "cache", 0)),
+	} {
+		for i := 0; i < 2; i++ {
+			body, contentType := multipartForm(t, "<html></html>")
+			req := httptest.NewRequest(http.MethodPost, ConvertHTML, body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, req)
+			if rec.Header().Get("Content-Disposition") != "inline" || rec.Header().Get("Cache-Control") != "private, no-cache" {
+				t.Errorf("unexpected headers %v", rec.Header())
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Default container images, see GOTENBERG_IMAGE and MINIO_IMAGE.
+const (
+	defaultGotenbergImage = "gotenberg/gotenberg:8"
+	defaultMinioImage     = "minio/minio:latest"
+)
+
+// MinIO credentials of the started container.
+const (
+	minioUser     = "minioadmin"
+	minioPassword = "minioadmin"
+)
+
+// dockerHost is the name under which containers reach the host running the tests.
+const dockerHost = "host.docker.internal"
+
+// compose is a running Gotenberg and MinIO pair, the services of docker-compose.yml.
+type compose struct {
+	// GotenbergURL is the base URL of Gotenberg.
+	GotenbergURL string
+	// MinioEndpoint is the host:port of the MinIO API.
+	MinioEndpoint string
+}
+
+// startCompose starts the containers and waits until they are healthy. They
+// are removed when the test ends. The test is skipped without Docker.
+func startCompose(t *testing.T) *compose {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker not found:", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	gotenberg := runContainer(ctx, t, "3000/tcp", envOr("GOTENBERG_IMAGE", defaultGotenbergImage),
+		"--add-host", dockerHost+":host-gateway")
+	minio := runContainer(ctx, t, "9000/tcp", envOr("MINIO_IMAGE", defaultMinioImage),
+		"-e", "MINIO_ROOT_USER="+minioUser,
+		"-e", "MINIO_ROOT_PASSWORD="+minioPassword,
+		"--", "server", "/data")
+
+	c := &compose{
+		GotenbergURL:  "http://" + gotenberg,
+		MinioEndpoint: minio,
+	}
+	waitHealthy(ctx, t, c.GotenbergURL+"/health")
+	waitHealthy(ctx, t, "http://"+c.MinioEndpoint+"/minio/health/live")
+	return c
+}
+
+// runContainer starts image with its port published on a random local port
+// and returns the host:port it is reachable at. Arguments after "--" are
+// passed to the container.
+func runContainer(ctx context.Context, t *testing.T, port, image string, args ...string) string {
+	t.Helper()
+	runArgs := []string{"run", "-d", "--rm", "-p", "127.0.0.1::" + port}
+	var cmd []string
+	for i, a := range args {
+		if a == "--" {
+			cmd = args[i+1:]
+			break
+		}
+		runArgs = append(runArgs, a)
+	}
+	runArgs = append(append(runArgs, image), cmd...)
+
+	id, err := docker(ctx, runArgs...)
+	if err != nil {
+		t.Fatalf("failed to start %s: %v", image, err)
+	}
+	t.Cleanup(func() {
+		if out, err := docker(context.Background(), "rm", "-f", id); err != nil {
+			t.Logf("failed to remove %s: %v %s", image, err, out)
+		}
+	})
+
+	addr, err := docker(ctx, "port", id, port)
+	if err != nil {
+		t.Fatalf("failed to resolve port of %s: %v", image, err)
+	}
+	// docker port lists one line per address family
+	return strings.SplitN(addr, "\n", 2)[0]
+}
+
+// docker runs the docker CLI and returns its trimmed output.
+func docker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// waitHealthy polls url until it answers 200 OK.
+func waitHealthy(ctx context.Context, t *testing.T, url string) {
+	t.Helper()
+	for {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatalf("%s not healthy: %v", url, ctx.Err())
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
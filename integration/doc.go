@@ -0,0 +1,8 @@
+// Package integration runs the client against real Gotenberg and MinIO
+// containers. The tests are behind the integration build tag and need a
+// Docker daemon:
+//
+//	go test -tags integration ./integration
+//
+// GOTENBERG_IMAGE and MINIO_IMAGE override the container images.
+package integration
@@ -0,0 +1,102 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// TestPipeline converts HTML in webhook mode, stores the document posted to
+// the webhook in MinIO and downloads it through a presigned URL.
+func TestPipeline(t *testing.T) {
+	env := startCompose(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := gotenberg.NewClient(&http.Client{}, env.GotenbergURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Health(ctx); err != nil {
+		t.Fatalf("Health failed: %v", err)
+	}
+	storage, err := gotenberg.NewMinioClient(ctx, gotenberg.MinioConfig{
+		Endpoint:        env.MinioEndpoint,
+		AccessKeyID:     minioUser,
+		SecretAccessKey: minioPassword,
+		BucketName:      "integration",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Gotenberg runs in a container and calls back through the Docker host
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callbackURL := fmt.Sprintf("http://%s:%d", dockerHost, listener.Addr().(*net.TCPAddr).Port)
+
+	mux := http.NewServeMux()
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	outbox := gotenberg.NewOutbox(client, gotenberg.NewMemoryJobStore(), gotenberg.WebhookSpec{
+		URL:      callbackURL + "/success",
+		ErrorURL: callbackURL + "/error",
+	})
+	stored := make(chan string, 1)
+	failed := make(chan error, 1)
+	save := gotenberg.SaveToStorage(storage, "results/{job}{ext}")
+	mux.Handle("/success", outbox.ResultHandler(func(ctx context.Context, job gotenberg.Job, result *gotenberg.WebhookResult) error {
+		if err := save(ctx, job, result); err != nil {
+			return err
+		}
+		stored <- "results/" + job.ID + ".pdf"
+		return nil
+	}))
+	mux.Handle("/error", outbox.ErrorHandler(func(ctx context.Context, job gotenberg.Job, err *gotenberg.GotenbergError) {
+		failed <- err
+	}))
+
+	_, err = outbox.Submit(ctx, gotenberg.ConversionSpec{
+		Route: gotenberg.ConvertHTML,
+		Files: []gotenberg.FileRef{{Filename: gotenberg.FileIndexHTML, Data: []byte("<h1>Integration</h1>")}},
+	})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+
+	var key string
+	select {
+	case key = <-stored:
+	case err := <-failed:
+		t.Fatalf("conversion failed: %v", err)
+	case <-ctx.Done():
+		t.Fatal("no webhook callback:", ctx.Err())
+	}
+
+	u, err := storage.PresignedURL(ctx, key, time.Minute, "integration.pdf")
+	if err != nil {
+		t.Fatalf("PresignedURL failed: %v", err)
+	}
+	resp, err := http.Get(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	pdf, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Errorf("unexpected download %d %.20q", resp.StatusCode, pdf)
+	}
+}
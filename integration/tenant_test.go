@@ -0,0 +1,59 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// TestTenantBuckets stores conversions of two tenants in buckets of their own
+// and enforces the bucket quota.
+func TestTenantBuckets(t *testing.T) {
+	env := startCompose(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	client, err := gotenberg.NewClient(&http.Client{}, env.GotenbergURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	minioClient, err := gotenberg.NewMinioClient(ctx, gotenberg.MinioConfig{
+		Endpoint:        env.MinioEndpoint,
+		AccessKeyID:     minioUser,
+		SecretAccessKey: minioPassword,
+		BucketName:      "shared",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &gotenberg.TenantBuckets{Minio: minioClient, Prefix: "tenant-", Quota: 1 << 20}
+
+	for _, tenant := range []string{"acme", "globex"} {
+		_, err := client.ConvertHTML(ctx, strings.NewReader("<h1>"+tenant+"</h1>")).
+			Tenant(tenant).
+			ConvertAndStore(storage, "report.pdf")
+		if err != nil {
+			t.Fatalf("ConvertAndStore for %s failed: %v", tenant, err)
+		}
+		usage, err := storage.Usage(ctx, tenant)
+		if err != nil || usage == 0 {
+			t.Fatalf("unexpected usage %d of %s: %v", usage, tenant, err)
+		}
+	}
+	if _, err := minioClient.GetFileInfo(ctx, "report.pdf"); err == nil {
+		t.Error("tenant document stored in the shared bucket")
+	}
+
+	tenantCtx := gotenberg.ContextWithTenant(ctx, "acme")
+	_, err = storage.UploadFile(tenantCtx, "large.bin", strings.NewReader(strings.Repeat("x", 2<<20)), 2<<20, "application/octet-stream")
+	if !errors.Is(err, gotenberg.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+}
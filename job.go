@@ -0,0 +1,265 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrJobNotFound is returned by JobStore.Get for unknown job IDs.
+var ErrJobNotFound = errors.New("gotenberg: job not found")
+
+// JobStatus is the state of an asynchronous conversion job.
+type JobStatus string
+
+const (
+	// JobPending jobs are persisted and possibly submitted, their result has not arrived yet.
+	JobPending JobStatus = "pending"
+	// JobCompleted jobs received their result on the webhook URL.
+	JobCompleted JobStatus = "completed"
+	// JobFailed jobs received a Gotenberg error on the webhook error URL.
+	JobFailed JobStatus = "failed"
+	// JobCancelled jobs were cancelled with Job.Cancel, their callbacks are ignored.
+	JobCancelled JobStatus = "cancelled"
+)
+
+// Job is a conversion spec submitted in webhook mode. Its ID is sent as the
+// Gotenberg trace, so webhook callbacks can be matched to the job.
+type Job struct {
+	ID       string         `json:"id"`
+	Spec     ConversionSpec `json:"spec"`
+	Status   JobStatus      `json:"status"`
+	Attempts int            `json:"attempts"`
+	Error    string         `json:"error,omitempty"`
+
+	CreatedAt   time.Time `json:"createdAt"`
+	SubmittedAt time.Time `json:"submittedAt,omitempty"`
+	CompletedAt time.Time `json:"completedAt,omitempty"`
+
+	// Result describes the document of a completed job.
+	Result *Result `json:"result,omitempty"`
+
+	// outbox is the Outbox the job was obtained from, see Cancel
+	outbox *Outbox
+}
+
+// Cancel cancels the job: a submission still uploading is aborted and
+// callbacks arriving later are acknowledged and discarded.
+// Only jobs returned by an Outbox can be cancelled.
+func (j Job) Cancel() error {
+	if j.outbox == nil {
+		return fmt.Errorf("gotenberg: job %s was not obtained from an outbox", j.ID)
+	}
+	return j.outbox.Cancel(context.Background(), j.ID)
+}
+
+// JobStore persists jobs. Implementations must be safe for concurrent use.
+type JobStore interface {
+	// Save creates or replaces the job with the same ID.
+	Save(ctx context.Context, job Job) error
+	// Get returns the job with the given ID or an error wrapping ErrJobNotFound.
+	Get(ctx context.Context, id string) (Job, error)
+	// List returns the jobs with the given status, oldest first.
+	List(ctx context.Context, status JobStatus) ([]Job, error)
+	// Update replaces the job id with the result of update, atomically: no
+	// other Save or Update of the job happens between reading the stored job
+	// passed to update and saving its result. An error of update leaves the
+	// job unchanged and is returned with the stored job.
+	Update(ctx context.Context, id string, update func(job Job) (Job, error)) (Job, error)
+}
+
+// newJobID returns a random job ID that is also a valid Gotenberg trace.
+func newJobID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// MemoryJobStore is an in-process JobStore. Jobs do not survive a restart,
+// use DirJobStore or a database backed store for durable job queues.
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore creates an empty MemoryJobStore.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+// Save implements JobStore.
+func (s *MemoryJobStore) Save(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+// Get implements JobStore.
+func (s *MemoryJobStore) Get(ctx context.Context, id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// Update implements JobStore.
+func (s *MemoryJobStore) Update(ctx context.Context, id string, update func(job Job) (Job, error)) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	updated, err := update(job)
+	if err != nil {
+		return job, err
+	}
+	s.jobs[id] = updated
+	return updated, nil
+}
+
+// List implements JobStore.
+func (s *MemoryJobStore) List(ctx context.Context, status JobStatus) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var jobs []Job
+	for _, job := range s.jobs {
+		if job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+// DirJobStore is a JobStore keeping every job as a JSON file in Dir.
+// Files are replaced atomically, so a crash never leaves a partially written job.
+// Updates are atomic within one process: processes sharing Dir need a
+// database backed store instead.
+type DirJobStore struct {
+	Dir string
+
+	mu sync.Mutex
+}
+
+// Save implements JobStore.
+func (s *DirJobStore) Save(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(job)
+}
+
+// Update implements JobStore.
+func (s *DirJobStore) Update(ctx context.Context, id string, update func(job Job) (Job, error)) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, err := s.read(s.path(id))
+	if err != nil {
+		return Job{}, err
+	}
+	updated, err := update(job)
+	if err != nil {
+		return job, err
+	}
+	if err := s.write(updated); err != nil {
+		return job, err
+	}
+	return updated, nil
+}
+
+// write replaces the file of job, with s.mu held.
+func (s *DirJobStore) write(job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, ".job-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(job.ID))
+}
+
+// Get implements JobStore.
+func (s *DirJobStore) Get(ctx context.Context, id string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(s.path(id))
+}
+
+// List implements JobStore.
+func (s *DirJobStore) List(ctx context.Context, status JobStatus) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var jobs []Job
+	for _, p := range paths {
+		job, err := s.read(p)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	sortJobs(jobs)
+	return jobs, nil
+}
+
+func (s *DirJobStore) path(id string) string {
+	// IDs come from callbacks, never let them escape Dir
+	return filepath.Join(s.Dir, strings.ReplaceAll(filepath.Base(id), ".", "_")+".json")
+}
+
+func (s *DirJobStore) read(path string) (Job, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Job{}, fmt.Errorf("%w: %s", ErrJobNotFound, strings.TrimSuffix(filepath.Base(path), ".json"))
+	}
+	if err != nil {
+		return Job{}, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return Job{}, fmt.Errorf("gotenberg: decode job %s: %w", path, err)
+	}
+	return job, nil
+}
+
+func sortJobs(jobs []Job) {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+}
@@ -0,0 +1,75 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDirJobStore(t *testing.T) {
+	ctx := context.Background()
+	store := &DirJobStore{Dir: t.TempDir()}
+
+	now := time.Now()
+	first := Job{ID: "a", Status: JobPending, CreatedAt: now, Spec: ConversionSpec{Route: ConvertHTML}}
+	second := Job{ID: "b", Status: JobPending, CreatedAt: now.Add(-time.Minute)}
+	done := Job{ID: "c", Status: JobCompleted, CreatedAt: now}
+	for _, job := range []Job{first, second, done} {
+		if err := store.Save(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Route != ConvertHTML || got.Status != JobPending {
+		t.Errorf("unexpected job %+v", got)
+	}
+
+	pending, err := store.List(ctx, JobPending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].ID != "b" || pending[1].ID != "a" {
+		t.Errorf("unexpected pending jobs %+v", pending)
+	}
+
+	if _, err := store.Get(ctx, "../missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestJobStoreUpdate(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range map[string]JobStore{"memory": NewMemoryJobStore(), "dir": &DirJobStore{Dir: t.TempDir()}} {
+		t.Run(name, func(t *testing.T) {
+			store.Save(ctx, Job{ID: "a", Status: JobPending})
+
+			job, err := store.Update(ctx, "a", func(job Job) (Job, error) {
+				job.Attempts++
+				return job, nil
+			})
+			if err != nil || job.Attempts != 1 {
+				t.Fatalf("Update returned %+v, %v", job, err)
+			}
+
+			conflict := errors.New("conflict")
+			if _, err := store.Update(ctx, "a", func(job Job) (Job, error) {
+				job.Status = JobFailed
+				return job, conflict
+			}); !errors.Is(err, conflict) {
+				t.Errorf("expected the update error, got %v", err)
+			}
+			if saved, _ := store.Get(ctx, "a"); saved.Status != JobPending || saved.Attempts != 1 {
+				t.Errorf("failed update changed the job: %+v", saved)
+			}
+
+			if _, err := store.Update(ctx, "missing", func(job Job) (Job, error) { return job, nil }); !errors.Is(err, ErrJobNotFound) {
+				t.Errorf("expected ErrJobNotFound, got %v", err)
+			}
+		})
+	}
+}
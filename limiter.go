@@ -0,0 +1,190 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrClientQueueTimeout is returned by Send when a conversion waited longer
+// than the queue timeout for a slot of the client's concurrency limit.
+var ErrClientQueueTimeout = errors.New("gotenberg: timed out waiting for a conversion slot")
+
+// WithConcurrencyLimit limits the conversions the client sends to Gotenberg at
+// the same time to n. Further conversions wait in Send for a slot, see
+// WithQueueTimeout. Gotenberg converts a limited number of documents at once
+// and queues the others itself, with requests timing out on its side.
+func WithConcurrencyLimit(n int) ClientOption {
+	return func(c *Client) {
+		c.limiter = newLimiter(n)
+	}
+}
+
+// WithQueueTimeout makes Send fail with ErrClientQueueTimeout when a
+// conversion waits longer than d for a slot of the concurrency limit, instead
+// of waiting until its context is done.
+func WithQueueTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.queueTimeout = d
+	}
+}
+
+// WithAdaptiveConcurrency limits the conversions the client sends at the same
+// time like WithConcurrencyLimit, adapting the limit between min and max to
+// the capacity of Gotenberg: the limit starts at max, is halved when Gotenberg
+// answers 503 Service Unavailable or 429 Too Many Requests, and grows by one
+// after a limit's worth of successful conversions (AIMD). Only the first
+// overload answer of conversions sent under the same limit halves it.
+func WithAdaptiveConcurrency(min, max int) ClientOption {
+	return func(c *Client) {
+		if min < 1 {
+			min = 1
+		}
+		if max < min {
+			max = min
+		}
+		c.limiter = newLimiter(max)
+		c.limiter.min, c.limiter.max = min, max
+	}
+}
+
+// limiter is a semaphore whose limit can change while it is in use.
+// Waiters are served in order.
+type limiter struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters []chan struct{}
+
+	// min and max bound the limit adapted by done, both zero for fixed limits
+	min, max  int
+	successes int
+	// generation changes with every decrease of the limit
+	generation int
+}
+
+func newLimiter(n int) *limiter {
+	if n < 1 {
+		n = 1
+	}
+	return &limiter{limit: n}
+}
+
+// acquire waits for a slot until ctx is done.
+func (l *limiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.active < l.limit && len(l.waiters) == 0 {
+		l.active++
+		l.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, w := range l.waiters {
+			if w == ready {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// The slot was granted meanwhile: hand it on
+		l.active--
+		l.wake()
+		return ctx.Err()
+	}
+}
+
+// release frees a slot acquired with acquire.
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.wake()
+}
+
+// done adapts the limit to the outcome of a conversion acquired in
+// generation, with resp nil when no response was received.
+func (l *limiter) done(generation int, resp *http.Response) {
+	if l.max == 0 || resp == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusTooManyRequests:
+		if generation != l.generation {
+			// Sent before the last decrease, which accounted for it
+			return
+		}
+		l.generation++
+		l.successes = 0
+		l.limit = max(l.min, l.limit/2)
+	case resp.StatusCode < http.StatusInternalServerError:
+		l.successes++
+		if l.successes >= l.limit && l.limit < l.max {
+			l.successes = 0
+			l.limit++
+			l.wake()
+		}
+	}
+}
+
+// current returns the limit and its generation.
+func (l *limiter) current() (limit, generation int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit, l.generation
+}
+
+// queued returns the number of waiting acquire calls.
+func (l *limiter) queued() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
+
+// wake grants free slots to waiters. l.mu must be held.
+func (l *limiter) wake() {
+	for l.active < l.limit && len(l.waiters) > 0 {
+		close(l.waiters[0])
+		l.waiters = l.waiters[1:]
+		l.active++
+	}
+}
+
+// acquireSlot waits for a slot of the client's concurrency limit, if any,
+// and returns the function releasing it with the response of the conversion.
+func (c *Client) acquireSlot(ctx context.Context) (func(resp *http.Response), error) {
+	if c.limiter == nil {
+		return func(*http.Response) {}, nil
+	}
+	wait := ctx
+	if c.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		wait, cancel = context.WithTimeout(ctx, c.queueTimeout)
+		defer cancel()
+	}
+	if err := c.limiter.acquire(wait); err != nil {
+		if ctx.Err() == nil {
+			return nil, ErrClientQueueTimeout
+		}
+		return nil, err
+	}
+	_, generation := c.limiter.current()
+	var once sync.Once
+	return func(resp *http.Response) {
+		once.Do(func() {
+			c.limiter.done(generation, resp)
+			c.limiter.release()
+		})
+	}, nil
+}
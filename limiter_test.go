@@ -0,0 +1,183 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	var active, peak int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		w.Write([]byte("%PDF"))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL, WithConcurrencyLimit(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			resp, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+			if err == nil {
+				resp.Body.Close()
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if peak != 2 {
+		t.Errorf("expected at most 2 concurrent conversions, got %d", peak)
+	}
+}
+
+func TestQueueTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("%PDF"))
+	}))
+	defer srv.Close()
+	defer close(release)
+	c, err := NewClient(srv.Client(), srv.URL, WithConcurrencyLimit(1), WithQueueTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+	for c.Stats().ActiveRequests == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	_, err = c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+	if !errors.Is(err, ErrClientQueueTimeout) {
+		t.Fatalf("expected ErrClientQueueTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("queue timeout took %v", elapsed)
+	}
+
+	// A cancelled caller gets its own context error
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ConvertHTML(ctx, strings.NewReader("<html></html>")).Send(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if got := c.Stats().QueuedRequests; got != 0 {
+		t.Errorf("expected no queued requests, got %d", got)
+	}
+}
+
+func TestLimiterHandsOnGrantedSlot(t *testing.T) {
+	l := newLimiter(1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.acquire(ctx) }()
+	for l.queued() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	l.release()
+	cancel()
+	if err := <-done; err == nil {
+		l.release()
+	}
+	// Whatever won the race, the slot is free again
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if l.active != 1 {
+		t.Errorf("expected 1 active slot, got %d", l.active)
+	}
+}
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	var overloaded atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if overloaded.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("%PDF"))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL, WithAdaptiveConcurrency(2, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	convert := func() {
+		resp, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if got := c.Stats().ConcurrencyLimit; got != 8 {
+		t.Fatalf("expected the limit to start at max, got %d", got)
+	}
+	overloaded.Store(true)
+	convert()
+	if got := c.Stats().ConcurrencyLimit; got != 4 {
+		t.Errorf("expected 503 to halve the limit, got %d", got)
+	}
+	convert()
+	convert()
+	if got := c.Stats().ConcurrencyLimit; got != 2 {
+		t.Errorf("expected the limit to stay at min, got %d", got)
+	}
+
+	overloaded.Store(false)
+	convert()
+	convert()
+	if got := c.Stats().ConcurrencyLimit; got != 3 {
+		t.Errorf("expected a limit's worth of successes to add one, got %d", got)
+	}
+}
+
+func TestAdaptiveLimiterHalvesOncePerGeneration(t *testing.T) {
+	l := newLimiter(8)
+	l.min, l.max = 1, 8
+	_, generation := l.current()
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+
+	// Conversions sent together fail together: one decrease
+	l.done(generation, unavailable)
+	l.done(generation, unavailable)
+	l.done(generation, unavailable)
+	if limit, _ := l.current(); limit != 4 {
+		t.Errorf("expected one decrease to 4, got %d", limit)
+	}
+	_, generation = l.current()
+	l.done(generation, unavailable)
+	if limit, _ := l.current(); limit != 2 {
+		t.Errorf("expected a new generation to decrease to 2, got %d", limit)
+	}
+	l.done(generation, nil)
+	if limit, _ := l.current(); limit != 2 {
+		t.Errorf("expected transport errors to keep the limit, got %d", limit)
+	}
+}
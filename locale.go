@@ -0,0 +1,322 @@
+package gotenberg
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"math"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// DefaultLocale is the locale used by FuncMap when no locale is configured.
+const DefaultLocale = "en-US"
+
+// Locale describes how numbers, amounts and dates are written in a language.
+type Locale struct {
+	Tag string // BCP 47 tag, e.g. "de-DE"
+
+	Decimal string // decimal separator
+	Group   string // thousands separator
+
+	// CurrencyAfter places the currency symbol after the amount.
+	CurrencyAfter bool
+	// PercentSpace separates the percent sign from the number.
+	PercentSpace bool
+
+	// DateLayout and DateTimeLayout are time layouts. English month names in
+	// the output are replaced with Months, if set.
+	DateLayout     string
+	DateTimeLayout string
+	Months         []string
+}
+
+// locales are the built-in locales, by tag.
+var locales = map[string]Locale{
+	"en-US": {
+		Tag: "en-US", Decimal: ".", Group: ",",
+		DateLayout: "January 2, 2006", DateTimeLayout: "January 2, 2006 3:04 PM",
+	},
+	"en-GB": {
+		Tag: "en-GB", Decimal: ".", Group: ",",
+		DateLayout: "2 January 2006", DateTimeLayout: "2 January 2006 15:04",
+	},
+	"de-DE": {
+		Tag: "de-DE", Decimal: ",", Group: ".", CurrencyAfter: true, PercentSpace: true,
+		DateLayout: "2. January 2006", DateTimeLayout: "2. January 2006, 15:04",
+		Months: []string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	},
+	"fr-FR": {
+		Tag: "fr-FR", Decimal: ",", Group: narrowNbsp, CurrencyAfter: true, PercentSpace: true,
+		DateLayout: "2 January 2006", DateTimeLayout: "2 January 2006 15:04",
+		Months: []string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	},
+	"es-ES": {
+		Tag: "es-ES", Decimal: ",", Group: ".", CurrencyAfter: true, PercentSpace: true,
+		DateLayout: "2 de January de 2006", DateTimeLayout: "2 de January de 2006, 15:04",
+		Months: []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	},
+	"ru-RU": {
+		Tag: "ru-RU", Decimal: ",", Group: nbsp, CurrencyAfter: true, PercentSpace: true,
+		DateLayout: "2 January 2006 г.", DateTimeLayout: "2 January 2006 г., 15:04",
+		Months: []string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+	},
+	"ja-JP": {
+		Tag: "ja-JP", Decimal: ".", Group: ",",
+		DateLayout: "2006年1月2日", DateTimeLayout: "2006年1月2日 15:04",
+	},
+}
+
+// currencySymbols maps ISO 4217 codes to their symbols. Other codes are written as is.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "¥",
+	"RUB": "₽",
+	"INR": "₹",
+}
+
+// currencyDecimals lists currencies without minor units; all others use two decimals.
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// nbsp separates amounts from their units and groups the digits of ru-RU
+// numbers, so they never wrap across lines.
+const nbsp = "\u00a0"
+
+// narrowNbsp groups the digits of fr-FR numbers, as in CLDR.
+const narrowNbsp = "\u202f"
+
+// languageLocales maps languages to their default locale when it is not
+// named after the language itself, as in "de" for "de-DE".
+var languageLocales = map[string]string{
+	"en": "en-US",
+	"ja": "ja-JP",
+}
+
+// LookupLocale returns the built-in locale for tag. Tags are matched exactly,
+// then by language ("de" or "de-AT" match "de-DE"), falling back to DefaultLocale.
+func LookupLocale(tag string) Locale {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if l, ok := locales[tag]; ok {
+		return l
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	name, ok := languageLocales[lang]
+	if !ok {
+		name = lang + "-" + strings.ToUpper(lang)
+	}
+	if l, ok := locales[name]; ok {
+		return l
+	}
+	return locales[DefaultLocale]
+}
+
+// FuncMap returns the template functions formatting values for the locale tag:
+//
+//	{{number .Quantity 2}}       1,234.50
+//	{{currency .Total "EUR"}}    €1,234.50
+//	{{percent .TaxRate}}         19%
+//	{{date .IssuedAt}}           May 3, 2024
+//	{{datetime .CreatedAt}}      May 3, 2024 2:30 PM
+//	{{locale}}                   en-US
+//
+// Templates must be parsed with these functions, e.g. with FuncMap(DefaultLocale);
+// ConvertTemplate rebinds them to the locale of the request before execution,
+// see WithLocale and Request.Locale.
+func FuncMap(tag string) map[string]any {
+	return LookupLocale(tag).FuncMap()
+}
+
+// FuncMap returns the template functions formatting values for l, see FuncMap.
+func (l Locale) FuncMap() map[string]any {
+	return map[string]any{
+		"number":   l.FormatNumber,
+		"currency": l.FormatCurrency,
+		"percent":  l.FormatPercent,
+		"date":     l.FormatDate,
+		"datetime": l.FormatDateTime,
+		"locale":   func() string { return l.Tag },
+	}
+}
+
+// FormatNumber formats v with the given number of decimals and grouped thousands.
+// A negative decimals value uses the smallest number of decimals necessary.
+func (l Locale) FormatNumber(v any, decimals int) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+
+	if decimals >= 0 {
+		// Round half away from zero, as expected for amounts
+		scale := math.Pow10(decimals)
+		f = math.Round(f*scale) / scale
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(l.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String(), nil
+}
+
+// FormatCurrency formats the amount v in the ISO 4217 currency code.
+func (l Locale) FormatCurrency(v any, code string) (string, error) {
+	code = strings.ToUpper(code)
+	decimals, ok := currencyDecimals[code]
+	if !ok {
+		decimals = 2
+	}
+	amount, err := l.FormatNumber(v, decimals)
+	if err != nil {
+		return "", err
+	}
+
+	symbol, ok := currencySymbols[code]
+	if !ok {
+		symbol = code
+	}
+	if l.CurrencyAfter {
+		return amount + nbsp + symbol, nil
+	}
+
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	if !ok {
+		symbol += nbsp // codes are separated from the amount
+	}
+	return sign + symbol + amount, nil
+}
+
+// FormatPercent formats the fraction v as a percentage, e.g. 0.19 as "19%".
+func (l Locale) FormatPercent(v any) (string, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	s, _ := l.FormatNumber(math.Round(f*1e6)/1e4, -1)
+	if l.PercentSpace {
+		return s + nbsp + "%", nil
+	}
+	return s + "%", nil
+}
+
+// FormatDate formats t with the locale's date layout.
+func (l Locale) FormatDate(t time.Time) string {
+	return l.formatTime(t, l.DateLayout)
+}
+
+// FormatDateTime formats t with the locale's date and time layout.
+func (l Locale) FormatDateTime(t time.Time) string {
+	return l.formatTime(t, l.DateTimeLayout)
+}
+
+func (l Locale) formatTime(t time.Time, layout string) string {
+	s := t.Format(layout)
+	if len(l.Months) == 12 {
+		s = strings.Replace(s, t.Month().String(), l.Months[t.Month()-1], 1)
+	}
+	return s
+}
+
+// toFloat converts the numeric template value v to float64.
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int8:
+		return float64(n), nil
+	case int16:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint8:
+		return float64(n), nil
+	case uint16:
+		return float64(n), nil
+	case uint32:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf("gotenberg: cannot format %T as a number", v)
+	}
+}
+
+// WithLocale sets the default locale of the formatting functions of templates
+// converted with ConvertTemplate, see FuncMap.
+func WithLocale(tag string) ClientOption {
+	return func(c *Client) {
+		c.locale = tag
+	}
+}
+
+// Locale sets the locale of the formatting functions of a template request created
+// by ConvertTemplate, overriding the client default. It has no effect on other requests.
+func (r *Request) Locale(tag string) *Request {
+	if r.template != nil {
+		r.template.locale = tag
+	}
+	return r
+}
+
+// localize returns the copy of tmpl ConvertTemplate executes, with the
+// formatting functions bound to the locale tag if set. html/template
+// templates are always cloned, so the original is never executed and stays
+// clonable for later localized requests. Templates other than html/template
+// and text/template, and text/template without a locale, are returned as is.
+func localize(tmpl Template, tag string) (Template, error) {
+	switch t := tmpl.(type) {
+	case *htmltemplate.Template:
+		clone, err := t.Clone()
+		switch {
+		case err != nil && tag == "":
+			// executed by its owner already, which only prevents localizing it
+			return tmpl, nil
+		case err != nil:
+			return nil, fmt.Errorf("gotenberg: localize template %s: %w", t.Name(), err)
+		case tag == "":
+			return clone, nil
+		}
+		return clone.Funcs(FuncMap(tag)), nil
+	case *texttemplate.Template:
+		if tag == "" {
+			return tmpl, nil
+		}
+		clone, err := t.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("gotenberg: localize template %s: %w", t.Name(), err)
+		}
+		return clone.Funcs(FuncMap(tag)), nil
+	default:
+		return tmpl, nil
+	}
+}
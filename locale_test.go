@@ -0,0 +1,113 @@
+package gotenberg
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookupLocale(t *testing.T) {
+	tests := map[string]string{
+		"de-DE": "de-DE",
+		"de_AT": "de-DE",
+		"en":    "en-US",
+		"ja":    "ja-JP",
+		"xx-YY": DefaultLocale,
+		"":      DefaultLocale,
+	}
+	for tag, want := range tests {
+		if got := LookupLocale(tag).Tag; got != want {
+			t.Errorf("LookupLocale(%q) = %s, want %s", tag, got, want)
+		}
+	}
+}
+
+func TestLocaleFormatting(t *testing.T) {
+	date := time.Date(2024, time.May, 3, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		tag                     string
+		number, currency, yen   string
+		percent, date, datetime string
+	}{
+		{"en-US", "-1,234,567.891", "€1,234.50", "¥1,235", "19%", "May 3, 2024", "May 3, 2024 2:30 PM"},
+		{"de-DE", "-1.234.567,891", "1.234,50" + nbsp + "€", "1.235" + nbsp + "¥", "19" + nbsp + "%", "3. Mai 2024", "3. Mai 2024, 14:30"},
+		{"fr-FR", "-1" + narrowNbsp + "234" + narrowNbsp + "567,891", "1" + narrowNbsp + "234,50" + nbsp + "€", "1" + narrowNbsp + "235" + nbsp + "¥", "19" + nbsp + "%", "3 mai 2024", "3 mai 2024 14:30"},
+		{"ru-RU", "-1" + nbsp + "234" + nbsp + "567,891", "1" + nbsp + "234,50" + nbsp + "€", "1" + nbsp + "235" + nbsp + "¥", "19" + nbsp + "%", "3 мая 2024 г.", "3 мая 2024 г., 14:30"},
+	}
+	for _, tt := range tests {
+		l := LookupLocale(tt.tag)
+		if got, _ := l.FormatNumber(-1234567.891, -1); got != tt.number {
+			t.Errorf("%s number: %q", tt.tag, got)
+		}
+		if got, _ := l.FormatCurrency(1234.5, "eur"); got != tt.currency {
+			t.Errorf("%s currency: %q", tt.tag, got)
+		}
+		if got, _ := l.FormatCurrency(1234.5, "JPY"); got != tt.yen {
+			t.Errorf("%s yen: %q", tt.tag, got)
+		}
+		if got, _ := l.FormatPercent(0.19); got != tt.percent {
+			t.Errorf("%s percent: %q", tt.tag, got)
+		}
+		if got := l.FormatDate(date); got != tt.date {
+			t.Errorf("%s date: %q", tt.tag, got)
+		}
+		if got := l.FormatDateTime(date); got != tt.datetime {
+			t.Errorf("%s datetime: %q", tt.tag, got)
+		}
+	}
+}
+
+func TestFormatCurrencyEdgeCases(t *testing.T) {
+	l := LookupLocale("en-US")
+	if got, _ := l.FormatCurrency(-5, "USD"); got != "-$5.00" {
+		t.Errorf("negative amount: %q", got)
+	}
+	if got, _ := l.FormatCurrency(12, "CHF"); got != "CHF"+nbsp+"12.00" {
+		t.Errorf("unknown code: %q", got)
+	}
+	if got, _ := l.FormatNumber(-0.001, 2); got != "0.00" {
+		t.Errorf("negative zero: %q", got)
+	}
+	if _, err := l.FormatNumber("12", 2); err == nil {
+		t.Error("expected error for non-numeric value")
+	}
+}
+
+func TestConvertTemplateLocale(t *testing.T) {
+	tmpl := template.Must(template.New("t").Funcs(FuncMap(DefaultLocale)).
+		Parse(`<html lang="{{locale}}">{{currency . "EUR"}}</html>`))
+
+	c, capture := newCaptureClient(t, WithLocale("de"))
+	if _, err := c.ConvertTemplate(context.Background(), tmpl, 1234.5).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.files[FileIndexHTML]; got != "<html lang=\"de-DE\">1.234,50\u00a0€</html>" {
+		t.Errorf("unexpected client locale output %q", got)
+	}
+
+	_, err := c.ConvertTemplate(context.Background(), tmpl, 1234.5).Locale("en-GB").Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.files[FileIndexHTML]; !strings.Contains(got, `lang="en-GB">€1,234.50`) {
+		t.Errorf("unexpected request locale output %q", got)
+	}
+}
+
+func TestConvertTemplatePlainThenLocalized(t *testing.T) {
+	tmpl := template.Must(template.New("t").Funcs(FuncMap(DefaultLocale)).
+		Parse(`<html lang="{{locale}}"></html>`))
+
+	c, capture := newCaptureClient(t)
+	if _, err := c.ConvertTemplate(context.Background(), tmpl, nil).Send(); err != nil {
+		t.Fatalf("plain Send failed: %v", err)
+	}
+	if _, err := c.ConvertTemplate(context.Background(), tmpl, nil).Locale("fr").Send(); err != nil {
+		t.Fatalf("localized Send failed: %v", err)
+	}
+	if got := capture.files[FileIndexHTML]; !strings.Contains(got, `lang="fr-FR"`) {
+		t.Errorf("unexpected localized output %q", got)
+	}
+}
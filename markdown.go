@@ -0,0 +1,37 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ConvertMarkdown creates a request to convert markdown files to PDF. Gotenberg
+// renders index, an HTML page inserting each markdown file by name with the
+// toHTML template function:
+//
+//	<html><body>{{ toHTML "report.md" }}</body></html>
+//
+// Markdown files must have the .md extension. The request shares the page
+// properties of ConvertHTML, e.g. PaperSize and Margins.
+func (c *Client) ConvertMarkdown(ctx context.Context, index io.Reader, markdownFiles ...NamedReader) *Request {
+	r := c.newRequest(ctx, ConvertMarkdown).File(FieldFiles, FileIndexHTML, index)
+	return r.markdownFiles(markdownFiles)
+}
+
+// markdownFiles adds the markdown files of a markdown route.
+func (r *Request) markdownFiles(files []NamedReader) *Request {
+	if len(files) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	for _, md := range files {
+		if !strings.EqualFold(path.Ext(md.Name), ".md") {
+			r.setErr(fmt.Errorf("%w: %q is not a markdown file", ErrInvalidFilename, md.Name))
+			continue
+		}
+		r.File(FieldFiles, md.Name, md.Reader)
+	}
+	return r
+}
@@ -0,0 +1,46 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertMarkdown(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	index := `<html><body>{{ toHTML "intro.md" }}{{ toHTML "usage.md" }}</body></html>`
+	_, err := c.ConvertMarkdown(context.Background(), strings.NewReader(index),
+		NamedReader{Name: "intro.md", Reader: strings.NewReader("# Intro")},
+		NamedReader{Name: "usage.md", Reader: strings.NewReader("## Usage")}).
+		PaperSizeA4().
+		Margins(1, 1, 1, 1).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.path != ConvertMarkdown {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	if capture.files[FileIndexHTML] != index || capture.files["intro.md"] != "# Intro" || capture.files["usage.md"] != "## Usage" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+	if got := capture.value(FieldPaperWidth); got != "8.27" {
+		t.Errorf("unexpected paper width %q", got)
+	}
+}
+
+func TestConvertMarkdownRejectsOtherFiles(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	index := strings.NewReader("<html></html>")
+
+	if _, err := c.ConvertMarkdown(context.Background(), index).Send(); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+	_, err := c.ConvertMarkdown(context.Background(), index,
+		NamedReader{Name: "notes.txt", Reader: strings.NewReader("notes")}).Send()
+	if !errors.Is(err, ErrInvalidFilename) {
+		t.Errorf("expected ErrInvalidFilename, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// ErrNoObjects is returned when a merge is requested without objects.
+var ErrNoObjects = errors.New("gotenberg: no objects to merge")
+
+// MergePDFs creates a request merging PDFs into one document with
+// Gotenberg's pdfengines merge route. The PDFs are merged in the order they
+// are given, followed by those added with AddPDF:
+//
+//	client.MergePDFs(ctx,
+//		gotenberg.NamedReader{Name: "cover.pdf", Reader: cover},
+//		gotenberg.NamedReader{Name: "report.pdf", Reader: report}).
+//		AddPDF("appendix.pdf", appendix).
+//		Send()
+func (c *Client) MergePDFs(ctx context.Context, pdfs ...NamedReader) *Request {
+	r := c.newRequest(ctx, MergePDF)
+	for _, pdf := range pdfs {
+		r.AddPDF(pdf.Name, pdf.Reader)
+	}
+	return r
+}
+
+// AddPDF appends the PDF name to a merge request. Gotenberg merges files in
+// alphabetical order of their names, so name is prefixed with the position of
+// the PDF to keep the order of the AddPDF calls.
+func (r *Request) AddPDF(name string, pdf io.Reader) *Request {
+	r.File(FieldFiles, mergeFilename(r.merged, name), pdf)
+	r.merged++
+	return r
+}
+
+// MergeStoredPDFs merges the stored PDFs objects, in the given order, into the
+// object target, e.g. to assemble statements from stored pages.
+// The objects are streamed to Gotenberg and the merged document is streamed
+// back to storage without buffering.
+func MergeStoredPDFs(ctx context.Context, client *Client, storage Storage, objects []string, target string) error {
+	if len(objects) == 0 {
+		return ErrNoObjects
+	}
+
+	req := client.MergePDFs(ctx)
+	for _, object := range objects {
+		content, err := storage.DownloadFile(ctx, object)
+		if err != nil {
+			req.close()
+			return err
+		}
+		req.closers = append(req.closers, content)
+		req.AddPDF(object, content)
+	}
+
+	resp, err := req.Send()
+	if err != nil {
+		return err
+	}
+	if err := resp.Err(); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = storage.UploadFile(ctx, target, resp.Body, resp.ContentLength, "application/pdf")
+	return err
+}
+
+// mergeFilename returns the filename of the i-th merged document.
+// Gotenberg merges files in alphabetical order of their names.
+func mergeFilename(i int, object string) string {
+	return fmt.Sprintf("%04d_%s", i, path.Base(object))
+}
@@ -0,0 +1,62 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMergeStoredPDFs(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	for _, name := range []string{"statements/b.pdf", "statements/a.pdf"} {
+		storage.UploadFile(ctx, name, bytes.NewReader([]byte(name)), -1, "application/pdf")
+	}
+
+	c, capture := newCaptureClient(t)
+	err := MergeStoredPDFs(ctx, c, storage, []string{"statements/b.pdf", "statements/a.pdf"}, "statements/merged.pdf")
+	if err != nil {
+		t.Fatalf("MergeStoredPDFs failed: %v", err)
+	}
+
+	if capture.files["0000_b.pdf"] != "statements/b.pdf" || capture.files["0001_a.pdf"] != "statements/a.pdf" {
+		t.Errorf("unexpected merged files %v", capture.files)
+	}
+	if got := string(storage.files["statements/merged.pdf"]); got != "pdf-bytes" {
+		t.Errorf("unexpected merged object %q", got)
+	}
+
+	if err := MergeStoredPDFs(ctx, c, storage, []string{"statements/missing.pdf"}, "x.pdf"); err == nil {
+		t.Error("expected error for missing object")
+	}
+	if err := MergeStoredPDFs(ctx, c, storage, nil, "x.pdf"); !errors.Is(err, ErrNoObjects) {
+		t.Errorf("expected ErrNoObjects, got %v", err)
+	}
+}
+
+func TestMergePDFs(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.MergePDFs(context.Background(),
+		NamedReader{Name: "z-cover.pdf", Reader: bytes.NewBufferString("cover")},
+		NamedReader{Name: "a-report.pdf", Reader: bytes.NewBufferString("report")}).
+		AddPDF("appendix.pdf", bytes.NewBufferString("appendix")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.path != MergePDF {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	want := map[string]string{"0000_z-cover.pdf": "cover", "0001_a-report.pdf": "report", "0002_appendix.pdf": "appendix"}
+	for name, content := range want {
+		if capture.files[name] != content {
+			t.Errorf("expected %s to hold %q, got files %v", name, content, capture.files)
+		}
+	}
+
+	if _, err := c.MergePDFs(context.Background()).Send(); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata sets a metadata entry of the generated PDF, such as "Title", "Author",
+// "Subject", "Keywords" or "Language". Entries are sent as a single JSON object
+// when the request is sent; setting a key again replaces its value.
+func (r *Request) Metadata(key string, value any) *Request {
+	if r.metadata == nil {
+		r.metadata = make(map[string]any)
+	}
+	r.metadata[key] = value
+	return r
+}
+
+// MetadataEntries sets the metadata entries of the generated PDF from a
+// PDFMetadata, whose set fields are used, or from a map or struct encoding to
+// a JSON object, stamping them during the conversion on the Chromium and
+// LibreOffice routes:
+//
+//	client.ConvertHTML(ctx, html).
+//		MetadataEntries(gotenberg.PDFMetadata{Title: "Q3 report", Author: "Finance", Producer: "Reports"}).
+//		Send()
+//
+// Entries are merged with those of Metadata, the last set value winning.
+func (r *Request) MetadataEntries(metadata any) *Request {
+	entries, err := metadataEntries(metadata)
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+	for key, value := range entries {
+		r.Metadata(key, value)
+	}
+	return r
+}
+
+// writeMetadata sets the metadata form field from the collected entries.
+func (r *Request) writeMetadata() error {
+	if len(r.metadata) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(r.metadata)
+	if err != nil {
+		return fmt.Errorf("gotenberg: encode metadata: %w", err)
+	}
+	r.Param(FieldMetadata, string(data))
+	return nil
+}
+
+// Accessible configures the request for accessible, PDF/UA compliant output:
+// it enables tagged PDF generation, the document outline and PDF/UA conversion,
+// and sets the document title and language (a BCP 47 tag such as "en-US"),
+// which PDF/UA requires.
+func (r *Request) Accessible(title, lang string) *Request {
+	return r.Bool(FieldGenerateTaggedPDF, true).
+		Bool(FieldGenerateDocumentOutline, true).
+		PDFUA(true).
+		Metadata("Title", title).
+		Metadata("Language", lang)
+}
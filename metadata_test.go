@@ -0,0 +1,82 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAccessible(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Accessible("Annual report", "en-US").
+		Metadata("Author", "Finance").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	for _, field := range []string{FieldGenerateTaggedPDF, FieldGenerateDocumentOutline, FieldPDFUA} {
+		if got := capture.value(field); got != "true" {
+			t.Errorf("%s = %q, want true", field, got)
+		}
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(capture.value(FieldMetadata)), &metadata); err != nil {
+		t.Fatalf("invalid metadata: %v", err)
+	}
+	if metadata["Title"] != "Annual report" || metadata["Language"] != "en-US" || metadata["Author"] != "Finance" {
+		t.Errorf("unexpected metadata %v", metadata)
+	}
+	if n := len(capture.form.Value[FieldMetadata]); n != 1 {
+		t.Errorf("metadata sent %d times", n)
+	}
+}
+
+func TestMetadataEncodeError(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Metadata("Bad", func() {}).
+		Send()
+	if err == nil || !strings.Contains(err.Error(), "encode metadata") {
+		t.Errorf("expected metadata encoding error, got %v", err)
+	}
+}
+
+func TestMetadataEntries(t *testing.T) {
+	for _, metadata := range []any{
+		PDFMetadata{Title: "Q3 report", Author: "Finance"},
+		map[string]any{"Title": "Q3 report", "Author": "Finance"},
+		map[string]string{"Title": "Q3 report", "Author": "Finance"},
+		struct {
+			Title  string
+			Author string
+		}{"Q3 report", "Finance"},
+	} {
+		c, capture := newCaptureClient(t)
+		_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+			Metadata("Author", "Sales").
+			MetadataEntries(metadata).
+			Metadata("Producer", "Reports").
+			Send()
+		if err != nil {
+			t.Fatalf("%T: Send failed: %v", metadata, err)
+		}
+		var got map[string]string
+		if err := json.Unmarshal([]byte(capture.value(FieldMetadata)), &got); err != nil {
+			t.Fatalf("%T: invalid metadata: %v", metadata, err)
+		}
+		if len(got) != 3 || got["Title"] != "Q3 report" || got["Author"] != "Finance" || got["Producer"] != "Reports" {
+			t.Errorf("%T: unexpected metadata %v", metadata, got)
+		}
+	}
+
+	c, _ := newCaptureClient(t)
+	err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).MetadataEntries([]string{"Title"}).Validate()
+	if !errors.Is(err, ErrInvalidField) {
+		t.Errorf("expected ErrInvalidField, got %v", err)
+	}
+}
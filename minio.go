@@ -4,6 +4,9 @@ package gotenberg
 import (
 	"context"
 	"io"
+	"mime"
+	"net/url"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -107,3 +110,14 @@ func (m *MinioClient) ListFiles(ctx context.Context, prefix string) <-chan minio
 		Recursive: true,
 	})
 }
+
+// PresignedURL returns a presigned URL to download the object without credentials
+// expiry - how long the URL stays valid
+// filename - optional filename announced in the Content-Disposition of the download
+func (m *MinioClient) PresignedURL(ctx context.Context, objectName string, expiry time.Duration, filename string) (*url.URL, error) {
+	params := url.Values{}
+	if filename != "" {
+		params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	return m.client.PresignedGetObject(ctx, m.bucketName, objectName, expiry, params)
+}
@@ -2,24 +2,115 @@
 package gotenberg
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // MinioAPI provides HTTP handlers for MinIO operations
 type MinioAPI struct {
 	minioClient *MinioClient
+	gotenberg   *Client
+	cors        *CORSConfig
+	bodyLimits  map[string]int64
+	tokens      *DownloadTokens
+
+	// inline serves documents to the browser viewer, see WithInlineDisposition
+	inline       bool
+	inlineMaxAge time.Duration
+
+	// uploadTypes are the accepted media types of uploads, see WithUploadTypes
+	uploadTypes []string
+	// scanner scans uploaded files, see WithScanner
+	scanner Scanner
+	// previewRenderer renders the previews of PDFs, see WithPreviewRenderer
+	previewRenderer ThumbnailRenderer
+}
+
+// MinioAPIOption configures optional MinioAPI features
+type MinioAPIOption func(*MinioAPI)
+
+// WithGotenberg enables the conversion and preview endpoints using the given Gotenberg client
+func WithGotenberg(client *Client) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.gotenberg = client
+	}
+}
+
+// WithPreviewRenderer sets the renderer of the previews of PDFs without an HTML
+// source, e.g. uploaded PDFs. It defaults to a GotenbergThumbnailRenderer using
+// the client set by WithGotenberg and DefaultPDFJSURL, which Gotenberg loads
+// from the internet.
+func WithPreviewRenderer(renderer ThumbnailRenderer) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.previewRenderer = renderer
+	}
+}
+
+// WithCORS enables cross-origin requests to the routes registered by RegisterRoutes
+func WithCORS(config CORSConfig) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.cors = &config
+	}
+}
+
+// WithDownloadTokens makes the download and preview endpoints take a token
+// signed by tokens instead of an object name, preventing the enumeration of
+// stored objects. The upload and conversion endpoints return a token for the
+// objects they store, other tokens are made with DownloadTokens.Sign.
+// It panics when the key of tokens is shorter than MinDownloadTokenKeySize.
+func WithDownloadTokens(tokens *DownloadTokens) MinioAPIOption {
+	if len(tokens.Key) < MinDownloadTokenKeySize {
+		panic(fmt.Sprintf("gotenberg: download token key must have at least %d bytes, got %d", MinDownloadTokenKeySize, len(tokens.Key)))
+	}
+	return func(api *MinioAPI) {
+		api.tokens = tokens
+	}
+}
+
+// WithInlineDisposition makes the download endpoint serve PDFs and images with
+// Content-Disposition inline, so they open in the browser viewer instead of
+// being downloaded, and with caching headers letting the browser keep them
+// for maxAge before revalidating them by ETag. Zero revalidates every time.
+// Other content, e.g. HTML, is still served as an attachment.
+func WithInlineDisposition(maxAge time.Duration) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.inline = true
+		api.inlineMaxAge = maxAge
+	}
+}
+
+// WithBodyLimit sets the request body limit of a route registered by RegisterRoutes,
+// e.g. "/api/upload". Larger requests are answered with 413, zero disables the limit.
+// The upload and conversion routes default to DefaultMaxBodySize.
+func WithBodyLimit(route string, maxBytes int64) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.bodyLimits[route] = maxBytes
+	}
 }
 
 // NewMinioAPI creates a new MinIO API handler
-func NewMinioAPI(minioClient *MinioClient) *MinioAPI {
-	return &MinioAPI{
+func NewMinioAPI(minioClient *MinioClient, opts ...MinioAPIOption) *MinioAPI {
+	api := &MinioAPI{
 		minioClient: minioClient,
+		bodyLimits: map[string]int64{
+			"/api/upload":  DefaultMaxBodySize,
+			"/api/convert": DefaultMaxBodySize,
+		},
+		uploadTypes: DefaultUploadTypes,
+	}
+	for _, opt := range opts {
+		opt(api)
 	}
+	return api
 }
 
 // UploadRequest represents the upload response
@@ -29,35 +120,45 @@ type UploadResponse struct {
 	Size       int64  `json:"size"`
 	ETag       string `json:"etag"`
 	Message    string `json:"message,omitempty"`
+	// DownloadToken is set with WithDownloadTokens
+	DownloadToken string `json:"download_token,omitempty"`
 }
 
-// DownloadErrorResponse represents an error response
-type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
+// ConvertResponse represents the conversion response
+type ConvertResponse struct {
+	Success        bool   `json:"success"`
+	ObjectName     string `json:"object_name"`
+	Size           int64  `json:"size"`
+	ETag           string `json:"etag"`
+	GotenbergTrace string `json:"gotenberg_trace,omitempty"`
+	// DownloadToken is set with WithDownloadTokens
+	DownloadToken string `json:"download_token,omitempty"`
 }
 
 // HandleUpload handles file upload to MinIO
 // POST /api/upload
 // Expects multipart/form-data with a file field named "file"
 // Optional query parameter: objectName (if not provided, uses the original filename)
+// The content type is detected from the content, files of types not accepted
+// by WithUploadTypes and executables are answered with 415
+// With WithScanner, infected files are answered with 422
 func (api *MinioAPI) HandleUpload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeErrorResponse(w, r, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse multipart form (max 100MB in memory)
 	err := r.ParseMultipartForm(100 << 20)
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse multipart form: "+err.Error())
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to parse multipart form", err)
 		return
 	}
 
 	// Get file from request
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		writeErrorResponse(w, http.StatusBadRequest, "Failed to get file from request: "+err.Error())
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to get file from request", err)
 		return
 	}
 	defer file.Close()
@@ -68,17 +169,23 @@ func (api *MinioAPI) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		objectName = header.Filename
 	}
 
-	// Get content type
-	contentType := header.Header.Get("Content-Type")
-	if contentType == "" {
-		contentType = "application/octet-stream"
+	// Detect the content type, the declared one cannot be trusted
+	contentType, err := sniffUpload(header.Filename, file, api.uploadTypes)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to accept file", err)
+		return
 	}
 
-	// Upload to MinIO
 	ctx := r.Context()
+	if err := scanUpload(ctx, api.scanner, header.Filename, file); err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to scan file", err)
+		return
+	}
+
+	// Upload to MinIO
 	uploadInfo, err := api.minioClient.UploadFile(ctx, objectName, file, header.Size, contentType)
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to upload file: "+err.Error())
+		writeErrorCause(w, r, http.StatusInternalServerError, "Failed to upload file", err)
 		return
 	}
 
@@ -90,6 +197,9 @@ func (api *MinioAPI) HandleUpload(w http.ResponseWriter, r *http.Request) {
 		ETag:       uploadInfo.ETag,
 		Message:    "File uploaded successfully",
 	}
+	if api.tokens != nil {
+		response.DownloadToken = api.tokens.Sign(uploadInfo.Key)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -99,16 +209,18 @@ func (api *MinioAPI) HandleUpload(w http.ResponseWriter, r *http.Request) {
 // HandleDownload handles file download from MinIO
 // GET /api/download?objectName=filename.pdf
 // Query parameter: objectName (required) - the name of the file in MinIO
+// With WithDownloadTokens: GET /api/download?token=... instead
+// Conditional requests (If-None-Match, If-Modified-Since) are answered with 304
+// when the object did not change
 func (api *MinioAPI) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeErrorResponse(w, r, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get object name from query parameter
-	objectName := r.URL.Query().Get("objectName")
-	if objectName == "" {
-		writeErrorResponse(w, http.StatusBadRequest, "Missing objectName parameter")
+	objectName, ok := api.requestedObject(w, r)
+	if !ok {
 		return
 	}
 
@@ -117,14 +229,21 @@ func (api *MinioAPI) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	// Get file info first to set proper headers
 	fileInfo, err := api.minioClient.GetFileInfo(ctx, objectName)
 	if err != nil {
-		writeErrorResponse(w, http.StatusNotFound, "File not found: "+err.Error())
+		writeErrorCause(w, r, http.StatusNotFound, "File not found", err)
+		return
+	}
+
+	// Repeated downloads revalidate their copy
+	setValidators(w.Header(), fileInfo.ETag, fileInfo.LastModified)
+	if notModified(r, fileInfo.ETag, fileInfo.LastModified) {
+		writeNotModified(w)
 		return
 	}
 
 	// Download from MinIO
 	object, err := api.minioClient.DownloadFile(ctx, objectName)
 	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to download file: "+err.Error())
+		writeErrorCause(w, r, http.StatusInternalServerError, "Failed to download file", err)
 		return
 	}
 	defer object.Close()
@@ -133,7 +252,9 @@ func (api *MinioAPI) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", fileInfo.ContentType)
 	w.Header().Set("Content-Length", strconv.FormatInt(fileInfo.Size, 10))
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filepath.Base(objectName)))
-	w.Header().Set("ETag", fileInfo.ETag)
+	if api.inline {
+		setInline(w.Header(), filepath.Base(objectName), api.inlineMaxAge)
+	}
 
 	// Stream file to response
 	_, err = io.Copy(w, object)
@@ -144,18 +265,279 @@ func (api *MinioAPI) HandleDownload(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// writeErrorResponse writes an error response in JSON format
-func writeErrorResponse(w http.ResponseWriter, statusCode int, errorMsg string) {
+// HandleConvert handles HTML to PDF conversion and stores the result in MinIO
+// POST /api/convert
+// Expects multipart/form-data with the HTML document in a field named "file"
+// and optional assets (images, stylesheets) in fields named "assets"
+// Optional query parameter: objectName (if not provided, uses the HTML filename with a .pdf extension)
+// The HTML source is stored next to the PDF so that previews can be rendered later
+// With WithScanner, infected documents and assets are answered with 422 before conversion
+func (api *MinioAPI) HandleConvert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if api.gotenberg == nil {
+		writeErrorResponse(w, r, http.StatusNotImplemented, "Gotenberg client not configured")
+		return
+	}
+
+	// Parse multipart form (max 100MB in memory)
+	err := r.ParseMultipartForm(100 << 20)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to parse multipart form", err)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to get file from request", err)
+		return
+	}
+	defer file.Close()
+
+	html, err := io.ReadAll(file)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to read file", err)
+		return
+	}
+
+	objectName := r.URL.Query().Get("objectName")
+	if objectName == "" {
+		objectName = strings.TrimSuffix(header.Filename, filepath.Ext(header.Filename)) + ".pdf"
+	}
+
+	ctx := r.Context()
+	if err := scanUpload(ctx, api.scanner, header.Filename, bytes.NewReader(html)); err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to scan file", err)
+		return
+	}
+	req := api.gotenberg.ConvertHTML(ctx, bytes.NewReader(html))
+	for _, asset := range r.MultipartForm.File["assets"] {
+		f, err := asset.Open()
+		if err != nil {
+			writeErrorCause(w, r, http.StatusBadRequest, "Failed to open asset", err)
+			return
+		}
+		defer f.Close()
+		if err := scanUpload(ctx, api.scanner, asset.Filename, f); err != nil {
+			writeErrorCause(w, r, http.StatusBadGateway, "Failed to scan asset", err)
+			return
+		}
+		req.File(FieldFiles, filepath.Base(asset.Filename), f)
+	}
+
+	resp, err := req.Send()
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to convert file", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if err := resp.Err(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	// Store the source first, a preview without it is impossible
+	_, err = api.minioClient.UploadFile(ctx, previewSourceName(objectName), bytes.NewReader(html), int64(len(html)), "text/html")
+	if err != nil {
+		writeErrorCause(w, r, http.StatusInternalServerError, "Failed to upload source", err)
+		return
+	}
+
+	uploadInfo, err := api.minioClient.UploadFile(ctx, objectName, resp.Body, resp.ContentLength, "application/pdf")
+	if err != nil {
+		writeErrorCause(w, r, http.StatusInternalServerError, "Failed to upload file", err)
+		return
+	}
+
+	response := ConvertResponse{
+		Success:        true,
+		ObjectName:     uploadInfo.Key,
+		Size:           uploadInfo.Size,
+		ETag:           uploadInfo.ETag,
+		GotenbergTrace: resp.GotenbergTrace,
+	}
+	if api.tokens != nil {
+		response.DownloadToken = api.tokens.Sign(uploadInfo.Key)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Success: false,
-		Error:   errorMsg,
-	})
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandlePreview returns a PNG preview of a stored document
+// GET /api/preview?objectName=filename.pdf
+// Query parameter: objectName (required) - the name of the file in MinIO
+// With WithDownloadTokens: GET /api/preview?token=... instead
+// HTML documents and documents converted by HandleConvert are rendered from
+// their HTML source via the Gotenberg screenshot route, other PDFs by the
+// first page with the renderer set by WithPreviewRenderer
+// The preview is rendered on first request and cached in MinIO next to the
+// original until the original is overwritten
+func (api *MinioAPI) HandlePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	objectName, ok := api.requestedObject(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	previewName := previewImageName(objectName)
+
+	document, err := api.minioClient.GetFileInfo(ctx, objectName)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusNotFound, "Document not found", err)
+		return
+	}
+
+	// Serve the cached preview unless the document was written after it. Times
+	// have a resolution of one second, a preview rendered in the second of the
+	// write counts as fresh
+	if info, err := api.minioClient.GetFileInfo(ctx, previewName); err == nil && !info.LastModified.Before(document.LastModified) {
+		setValidators(w.Header(), info.ETag, info.LastModified)
+		if notModified(r, info.ETag, info.LastModified) {
+			writeNotModified(w)
+			return
+		}
+		if cached, err := api.minioClient.DownloadFile(ctx, previewName); err == nil {
+			defer cached.Close()
+			w.Header().Set("Content-Type", "image/png")
+			io.Copy(w, cached)
+			return
+		}
+		w.Header().Del("ETag")
+		w.Header().Del("Last-Modified")
+	}
+
+	if api.gotenberg == nil {
+		writeErrorResponse(w, r, http.StatusNotImplemented, "Gotenberg client not configured")
+		return
+	}
+
+	png, err := api.renderPreview(ctx, objectName, document.ContentType)
+	if errors.Is(err, errNoPreview) {
+		writeErrorCause(w, r, http.StatusUnsupportedMediaType, "No preview available for document", err)
+		return
+	}
+	if err != nil {
+		var gerr *GotenbergError
+		if errors.As(err, &gerr) {
+			WriteError(w, r, err)
+			return
+		}
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to render preview", err)
+		return
+	}
+
+	_, err = api.minioClient.UploadFile(ctx, previewName, bytes.NewReader(png), int64(len(png)), "image/png")
+	if err != nil {
+		writeErrorCause(w, r, http.StatusInternalServerError, "Failed to cache preview", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(png)))
+	w.Write(png)
+}
+
+// errNoPreview is returned by renderPreview for documents it cannot render
+var errNoPreview = errors.New("gotenberg: no HTML source and not a PDF")
+
+// renderPreview renders the PNG preview of the document objectName of the given content type
+func (api *MinioAPI) renderPreview(ctx context.Context, objectName, contentType string) ([]byte, error) {
+	sourceName := objectName
+	if !strings.HasPrefix(contentType, "text/html") {
+		sourceName = previewSourceName(objectName)
+	}
+
+	var preview io.ReadCloser
+	if source, err := api.minioClient.DownloadFile(ctx, sourceName); err == nil {
+		defer source.Close()
+		resp, err := api.gotenberg.ScreenshotHTML(ctx, source).Send()
+		if err != nil {
+			return nil, err
+		}
+		if err := resp.Err(); err != nil {
+			return nil, err
+		}
+		preview = resp.Body
+	} else if strings.HasPrefix(contentType, "application/pdf") {
+		renderer := api.previewRenderer
+		if renderer == nil {
+			renderer = &GotenbergThumbnailRenderer{Client: api.gotenberg}
+		}
+		document, err := api.minioClient.DownloadFile(ctx, objectName)
+		if err != nil {
+			return nil, err
+		}
+		defer document.Close()
+		if preview, err = renderer.RenderThumbnail(ctx, document); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf("%w: %s is %s", errNoPreview, objectName, contentType)
+	}
+	defer preview.Close()
+	return io.ReadAll(preview)
+}
+
+// requestedObject returns the object named by the objectName query parameter,
+// or with WithDownloadTokens by the token parameter, and answers the request
+// if there is none
+func (api *MinioAPI) requestedObject(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if api.tokens == nil {
+		objectName := r.URL.Query().Get("objectName")
+		if objectName == "" {
+			writeErrorResponse(w, r, http.StatusBadRequest, "Missing objectName parameter")
+			return "", false
+		}
+		return objectName, true
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeErrorResponse(w, r, http.StatusBadRequest, "Missing token parameter")
+		return "", false
+	}
+	objectName, err := api.tokens.Verify(token)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusForbidden, "Invalid download token", err)
+		return "", false
+	}
+	return objectName, true
+}
+
+// previewSourceName returns the object name of the HTML source stored for a converted document
+func previewSourceName(objectName string) string {
+	return objectName + ".source.html"
+}
+
+// previewImageName returns the object name of the cached preview of a document
+func previewImageName(objectName string) string {
+	return objectName + ".preview.png"
 }
 
 // RegisterRoutes registers the MinIO API routes on the provided mux
 func (api *MinioAPI) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/api/upload", api.HandleUpload)
-	mux.HandleFunc("/api/download", api.HandleDownload)
+	mux.Handle("/api/upload", api.route("/api/upload", api.HandleUpload))
+	mux.Handle("/api/download", api.route("/api/download", api.HandleDownload))
+	mux.Handle("/api/convert", api.route("/api/convert", api.HandleConvert))
+	mux.Handle("/api/preview", api.route("/api/preview", api.HandlePreview))
+}
+
+// route wraps the handler of pattern with the configured middleware
+func (api *MinioAPI) route(pattern string, handler http.HandlerFunc) http.Handler {
+	h := LimitBody(api.bodyLimits[pattern], handler)
+	if api.cors != nil {
+		return api.cors.Middleware(h)
+	}
+	return h
 }
@@ -0,0 +1,249 @@
+package gotenberg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Object is an object stored by newMemoryS3.
+type s3Object struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+// memoryS3 is a bucket "docs" kept in memory. Every write advances its clock
+// by step, a second by default, the resolution of Last-Modified.
+type memoryS3 struct {
+	mu      sync.Mutex
+	objects map[string]s3Object
+	now     time.Time
+	step    time.Duration
+}
+
+// newMemoryS3 serves the objects of s3 to a MinioClient.
+func newMemoryS3(t *testing.T) (*MinioClient, *memoryS3) {
+	s3 := &memoryS3{objects: make(map[string]s3Object), now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), step: time.Second}
+	srv := httptest.NewServer(s3)
+	t.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL)
+	client, err := minio.New(u.Host, &minio.Options{Creds: credentials.NewStaticV4("key", "secret", ""), Region: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &MinioClient{client: client, bucketName: "docs"}, s3
+}
+
+func (s *memoryS3) put(name, contentType string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(s.step)
+	s.objects[name] = s3Object{data: data, contentType: contentType, modified: s.now}
+}
+
+func (s *memoryS3) get(name string) (s3Object, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[name]
+	return obj, ok
+}
+
+func (s *memoryS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/docs/")
+	if r.Method == http.MethodPut {
+		data, err := readS3Body(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.put(name, r.Header.Get("Content-Type"), data)
+		w.Header().Set("ETag", fmt.Sprintf(`"%x"`, len(data)))
+		return
+	}
+	obj, ok := s.get(name)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+		return
+	}
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, obj.modified.Unix()))
+	w.Header().Set("Last-Modified", obj.modified.Format(http.TimeFormat))
+	w.Header().Set("Content-Type", obj.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	if r.Method == http.MethodGet {
+		w.Write(obj.data)
+	}
+}
+
+// readS3Body reads the body of a PUT, decoding the aws-chunked encoding of
+// streaming signatures.
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var data []byte
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(line), ";", 2)[0], 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(br, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+// newScreenshotServer converts HTML to "pdf:<html>" and screenshots it as
+// "png:<html>", counting the screenshots.
+func newScreenshotServer(t *testing.T, screenshots *int32) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile(FieldFiles)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		html, _ := io.ReadAll(file)
+		switch r.URL.Path {
+		case ScreenshotHTML:
+			atomic.AddInt32(screenshots, 1)
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(append([]byte("png:"), html...))
+		default:
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(append([]byte("pdf:"), html...))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func convertHTMLUpload(t *testing.T, handler http.Handler, objectName, html string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "page.html")
+	io.WriteString(fw, html)
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/api/convert?objectName="+objectName, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func preview(handler http.Handler, objectName string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/preview?objectName="+objectName, nil))
+	return rec
+}
+
+func TestConvertAndPreview(t *testing.T) {
+	var screenshots int32
+	storage, s3 := newMemoryS3(t)
+	mux := http.NewServeMux()
+	NewMinioAPI(storage, WithGotenberg(newScreenshotServer(t, &screenshots))).RegisterRoutes(mux)
+
+	rec := convertHTMLUpload(t, mux, "doc.pdf", "<h1>v1</h1>")
+	var converted ConvertResponse
+	if err := json.NewDecoder(rec.Body).Decode(&converted); err != nil || rec.Code != http.StatusOK || converted.ObjectName != "doc.pdf" {
+		t.Fatalf("convert answered %d with %+v, %v", rec.Code, converted, err)
+	}
+	if obj, _ := s3.get("doc.pdf"); string(obj.data) != "pdf:<h1>v1</h1>" {
+		t.Errorf("unexpected document %q", obj.data)
+	}
+	if obj, _ := s3.get(previewSourceName("doc.pdf")); string(obj.data) != "<h1>v1</h1>" {
+		t.Errorf("unexpected source %q", obj.data)
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := preview(mux, "doc.pdf"); rec.Code != http.StatusOK || rec.Body.String() != "png:<h1>v1</h1>" {
+			t.Fatalf("preview %d answered %d: %q", i, rec.Code, rec.Body)
+		}
+	}
+	if n := atomic.LoadInt32(&screenshots); n != 1 {
+		t.Errorf("expected the cached preview to be served, got %d screenshots", n)
+	}
+
+	// overwriting the document invalidates its preview
+	convertHTMLUpload(t, mux, "doc.pdf", "<h1>v2</h1>")
+	if rec := preview(mux, "doc.pdf"); rec.Body.String() != "png:<h1>v2</h1>" {
+		t.Errorf("stale preview %q", rec.Body)
+	}
+}
+
+func TestPreviewUploadedDocuments(t *testing.T) {
+	var screenshots int32
+	storage, s3 := newMemoryS3(t)
+	s3.put("upload.pdf", "application/pdf", []byte("%PDF-1.7"))
+	s3.put("page.html", "text/html; charset=utf-8", []byte("<p>hi</p>"))
+	s3.put("data.csv", "text/csv", []byte("a,b"))
+	mux := http.NewServeMux()
+	NewMinioAPI(storage, WithGotenberg(newScreenshotServer(t, &screenshots)), WithPreviewRenderer(stubRenderer{})).RegisterRoutes(mux)
+
+	tests := []struct {
+		object string
+		code   int
+		body   string
+	}{
+		{"upload.pdf", http.StatusOK, "png:%PDF-1.7"},
+		{"page.html", http.StatusOK, "png:<p>hi</p>"},
+		{"data.csv", http.StatusUnsupportedMediaType, ""},
+		{"missing.pdf", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		rec := preview(mux, tt.object)
+		if rec.Code != tt.code || tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("preview of %s answered %d: %q", tt.object, rec.Code, rec.Body)
+		}
+	}
+	if obj, ok := s3.get(previewImageName("upload.pdf")); !ok || string(obj.data) != "png:%PDF-1.7" {
+		t.Errorf("preview of upload.pdf not cached: %q", obj.data)
+	}
+}
+
+func TestPreviewCachedInTheSecondOfTheDocument(t *testing.T) {
+	var screenshots int32
+	storage, s3 := newMemoryS3(t)
+	s3.step = 0
+	s3.put("page.html", "text/html", []byte("<p>hi</p>"))
+	mux := http.NewServeMux()
+	NewMinioAPI(storage, WithGotenberg(newScreenshotServer(t, &screenshots))).RegisterRoutes(mux)
+
+	for i := 0; i < 3; i++ {
+		if rec := preview(mux, "page.html"); rec.Code != http.StatusOK {
+			t.Fatalf("preview %d answered %d", i, rec.Code)
+		}
+	}
+	if n := atomic.LoadInt32(&screenshots); n != 1 {
+		t.Errorf("expected a preview of the same second to be cached, got %d screenshots", n)
+	}
+}
@@ -0,0 +1,92 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// Buffered vs streamed multipart bodies
+//
+// BenchmarkMultipartBody sends an HTML conversion with one index.html of the
+// given size through Client.ConvertHTML to a transport that discards the
+// body, in two ways:
+//
+//   - buffered: the transport reads the whole form into a bytes.Buffer before
+//     sending it with a known Content-Length, as transports replaying bodies
+//     for retries do;
+//   - streamed: the form is sent as the client writes it through an io.Pipe,
+//     with chunked transfer encoding.
+//
+// testdata/bench/multipart.txt holds benchstat input of a run of
+//
+//	go test -run '^$' -bench BenchmarkMultipartBody -benchmem -count 6 .
+//
+// Buffering allocates about four times the document as its buffer grows, and
+// copies it once more, while streaming stays at about 40 KB per request
+// whatever the size. Streaming is also 1.6 to 1.9 times as fast. Streaming is
+// therefore the default for conversion bodies; buffering only pays off for
+// small forms that have to be sent more than once, e.g. to retry an upload.
+func BenchmarkMultipartBody(b *testing.B) {
+	streamed, err := NewClient(&http.Client{Transport: &mockRoundTripper{}}, "http://localhost")
+	if err != nil {
+		b.Fatal(err)
+	}
+	buffered, err := NewClient(&http.Client{Transport: bufferingRoundTripper{next: &mockRoundTripper{}}}, "http://localhost")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, size := range []int{64 << 10, 1 << 20, 16 << 20} {
+		payload := bytes.Repeat([]byte("<p>lorem ipsum</p>\n"), size/19+1)[:size]
+		for _, mode := range []struct {
+			name   string
+			client *Client
+		}{
+			{"buffered", buffered},
+			{"streamed", streamed},
+		} {
+			b.Run(fmt.Sprintf("%s/%s", mode.name, byteSize(size)), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					resp, err := mode.client.ConvertHTML(context.Background(), bytes.NewReader(payload)).
+						PaperSize(8.27, 11.7).
+						Send()
+					if err != nil {
+						b.Fatal(err)
+					}
+					resp.Body.Close()
+				}
+			})
+		}
+	}
+}
+
+// bufferingRoundTripper reads request bodies into memory before passing them
+// to next with a known Content-Length.
+type bufferingRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (t bufferingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(req.Body); err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(&body)
+	req.ContentLength = int64(body.Len())
+	return t.next.RoundTrip(req)
+}
+
+// byteSize formats n for benchmark names, e.g. 64KiB.
+func byteSize(n int) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%dMiB", n>>20)
+	}
+	return fmt.Sprintf("%dKiB", n>>10)
+}
@@ -0,0 +1,220 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrNoDocuments is returned when a conversion is requested without input documents.
+var ErrNoDocuments = errors.New("gotenberg: no documents to convert")
+
+// NamedReader is an input file of a conversion: its filename, which tells
+// Gotenberg the document format, and its content.
+type NamedReader struct {
+	Name   string
+	Reader io.Reader
+}
+
+// ConvertOffice creates a request to convert office documents (docx, xlsx,
+// pptx, odt, ...) to PDF with LibreOffice. Gotenberg detects the format of
+// each document from the extension of its name. Several documents produce a
+// zip of PDFs, one per document.
+//
+//	client.ConvertOffice(ctx,
+//		gotenberg.NamedReader{Name: "report.docx", Reader: docx}).
+//		Landscape(true).
+//		Send()
+func (c *Client) ConvertOffice(ctx context.Context, docs ...NamedReader) *Request {
+	r := c.newRequest(ctx, ConvertOffice)
+	if len(docs) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	for _, doc := range docs {
+		r.File(FieldFiles, doc.Name, doc.Reader)
+	}
+	return r
+}
+
+// AddDocument appends the document name to an office conversion, after the
+// documents given to ConvertOffice.
+func (r *Request) AddDocument(name string, doc io.Reader) *Request {
+	return r.File(FieldFiles, name, doc)
+}
+
+// MergeDocuments combines the converted documents into a single PDF instead
+// of a zip of PDFs, in the order they were given to ConvertOffice and
+// AddDocument:
+//
+//	client.ConvertOffice(ctx,
+//		gotenberg.NamedReader{Name: "cover.docx", Reader: cover},
+//		gotenberg.NamedReader{Name: "appendix.xlsx", Reader: appendix}).
+//		MergeDocuments(true).
+//		Send()
+//
+// Gotenberg merges documents in alphabetical order of their names, so the
+// names are prefixed with their position when the request is sent.
+// LibreOffice only.
+func (r *Request) MergeDocuments(merge bool) *Request {
+	r.mergeDocuments = merge
+	return r.Bool(FieldMerge, merge)
+}
+
+// NativePageRanges limits the conversion to page ranges, e.g. "1-3,5". For
+// LibreOffice, the ranges apply to every document of the request; convert
+// documents needing different ranges with separate requests.
+func (r *Request) NativePageRanges(ranges string) *Request {
+	if !pageRanges.MatchString(ranges) {
+		r.setErr(fmt.Errorf("%w: page ranges such as \"1-3,5\" expected, not %q", ErrInvalidField, ranges))
+		return r
+	}
+	return r.Param(FieldNativePageRanges, ranges)
+}
+
+// validateOffice checks the LibreOffice options against the route.
+func (r *Request) validateOffice() error {
+	if r.mergeDocuments && r.route != ConvertOffice {
+		return fmt.Errorf("%w: route %s does not merge documents, use MergePDFs", ErrInvalidField, r.route)
+	}
+	return nil
+}
+
+// orderDocuments prefixes the names of the documents merged by
+// MergeDocuments with their position, which Gotenberg merges by.
+func (r *Request) orderDocuments() {
+	if !r.mergeDocuments {
+		return
+	}
+	i := 0
+	for j, f := range r.files {
+		if f.key != FieldFiles {
+			continue
+		}
+		r.files[j].filename = mergeFilename(i, f.filename)
+		i++
+	}
+}
+
+// Password sets the password opening encrypted documents, e.g. protected
+// docx or xlsx files. It applies to all documents of the request and is left
+// out of diagnostics. LibreOffice only.
+func (r *Request) Password(password string) *Request {
+	return r.Param(FieldPassword, password)
+}
+
+// LosslessImageCompression compresses the images of the documents without
+// loss, e.g. as PNG, instead of as JPEG of ImageQuality. LibreOffice only.
+func (r *Request) LosslessImageCompression(lossless bool) *Request {
+	return r.Bool(FieldLosslessImageCompression, lossless)
+}
+
+// ImageQuality sets the JPEG quality of the images of the documents, from 1
+// to 100 (Gotenberg's default is 90). Lower qualities make smaller PDFs of
+// image-heavy documents. LibreOffice only.
+func (r *Request) ImageQuality(quality int) *Request {
+	if quality < 1 || quality > 100 {
+		r.setErr(fmt.Errorf("%w: image quality must be between 1 and 100, got %d", ErrInvalidField, quality))
+		return r
+	}
+	return r.Param(FieldImageQuality, strconv.Itoa(quality))
+}
+
+// MaxImageResolution downsamples the images of the documents to dpi, one of
+// 75, 150, 300, 600 and 1200, and enables reduceImageResolution.
+// LibreOffice only.
+func (r *Request) MaxImageResolution(dpi int) *Request {
+	switch dpi {
+	case 75, 150, 300, 600, 1200:
+	default:
+		r.setErr(fmt.Errorf("%w: image resolution must be 75, 150, 300, 600 or 1200 DPI, got %d", ErrInvalidField, dpi))
+		return r
+	}
+	return r.Bool(FieldReduceImageResolution, true).Param(FieldMaxImageResolution, strconv.Itoa(dpi))
+}
+
+// ReduceImageResolution downsamples the images of the documents to the
+// resolution of MaxImageResolution, 300 DPI by default. LibreOffice only.
+func (r *Request) ReduceImageResolution(reduce bool) *Request {
+	return r.Bool(FieldReduceImageResolution, reduce)
+}
+
+// Landscape sets the paper orientation to landscape.
+func (r *Request) Landscape(landscape bool) *Request {
+	return r.Bool(FieldLandscape, landscape)
+}
+
+// SinglePageSheets renders each spreadsheet sheet on a single page, however
+// large. LibreOffice only.
+func (r *Request) SinglePageSheets(single bool) *Request {
+	return r.Bool(FieldSinglePageSheets, single)
+}
+
+// SkipEmptyPages leaves out pages without content. LibreOffice only.
+func (r *Request) SkipEmptyPages(skip bool) *Request {
+	return r.Bool(FieldSkipEmptyPages, skip)
+}
+
+// UpdateIndexes updates the indexes, e.g. the table of contents, of the
+// documents before converting them (Gotenberg's default). LibreOffice only.
+func (r *Request) UpdateIndexes(update bool) *Request {
+	return r.Bool(FieldUpdateIndexes, update)
+}
+
+// NativePDFFormats sets whether LibreOffice itself produces the PDF/A format
+// of the request, rather than the PDF engines of Gotenberg. Gotenberg 7 reads
+// the field; Gotenberg 8 always lets LibreOffice produce the format.
+// LibreOffice only.
+func (r *Request) NativePDFFormats(native bool) *Request {
+	return r.Bool(FieldNativePDFFormats, native)
+}
+
+// ExportFormFields sets whether the form fields of the documents become PDF
+// form fields (Gotenberg's default) or plain text. LibreOffice only.
+func (r *Request) ExportFormFields(export bool) *Request {
+	return r.Bool(FieldExportFormFields, export)
+}
+
+// AllowDuplicateFieldNames allows several form fields of the same name, e.g.
+// a radio group spread over pages, instead of renaming them. LibreOffice only.
+func (r *Request) AllowDuplicateFieldNames(allow bool) *Request {
+	return r.Bool(FieldAllowDuplicateFieldNames, allow)
+}
+
+// ExportBookmarks sets whether the headings of the documents become PDF
+// bookmarks (Gotenberg's default). LibreOffice only.
+func (r *Request) ExportBookmarks(export bool) *Request {
+	return r.Bool(FieldExportBookmarks, export)
+}
+
+// ExportBookmarksToPDFDestination exports the bookmarks of the documents as
+// named destinations, the targets of links such as report.pdf#Summary.
+// LibreOffice only.
+func (r *Request) ExportBookmarksToPDFDestination(export bool) *Request {
+	return r.Bool(FieldExportBookmarksToPDFDestination, export)
+}
+
+// ExportPlaceholders sets whether the placeholder fields of the documents are
+// printed, visibly marked. LibreOffice only.
+func (r *Request) ExportPlaceholders(export bool) *Request {
+	return r.Bool(FieldExportPlaceholders, export)
+}
+
+// ExportNotes sets whether the comments of the documents become PDF
+// annotations. LibreOffice only.
+func (r *Request) ExportNotes(export bool) *Request {
+	return r.Bool(FieldExportNotes, export)
+}
+
+// ExportNotesPages appends the notes pages of presentations to the slides.
+// LibreOffice only.
+func (r *Request) ExportNotesPages(export bool) *Request {
+	return r.Bool(FieldExportNotesPages, export)
+}
+
+// ExportHiddenSlides includes the hidden slides of presentations.
+// LibreOffice only.
+func (r *Request) ExportHiddenSlides(export bool) *Request {
+	return r.Bool(FieldExportHiddenSlides, export)
+}
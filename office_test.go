@@ -0,0 +1,158 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertOffice(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertOffice(context.Background(),
+		NamedReader{Name: "report.docx", Reader: strings.NewReader("docx")},
+		NamedReader{Name: "figures.xlsx", Reader: strings.NewReader("xlsx")}).
+		Landscape(true).
+		SinglePageSheets(true).
+		SkipEmptyPages(true).
+		UpdateIndexes(false).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.path != ConvertOffice {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	if capture.files["report.docx"] != "docx" || capture.files["figures.xlsx"] != "xlsx" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+	for field, want := range map[string]string{
+		FieldLandscape:        "true",
+		FieldSinglePageSheets: "true",
+		FieldSkipEmptyPages:   "true",
+		FieldUpdateIndexes:    "false",
+	} {
+		if got := capture.value(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestConvertOfficeWithoutDocuments(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	if _, err := c.ConvertOffice(context.Background()).Send(); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
+
+func TestConvertOfficeMerge(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertOffice(context.Background(),
+		NamedReader{Name: "report.docx", Reader: strings.NewReader("docx")},
+		NamedReader{Name: "figures.xlsx", Reader: strings.NewReader("xlsx")}).
+		AddDocument("appendix.odt", strings.NewReader("odt")).
+		MergeDocuments(true).
+		NativePageRanges("1-3, 5").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.value(FieldMerge) != "true" || capture.value(FieldNativePageRanges) != "1-3, 5" {
+		t.Errorf("unexpected fields merge=%q nativePageRanges=%q", capture.value(FieldMerge), capture.value(FieldNativePageRanges))
+	}
+	for name, want := range map[string]string{"0000_report.docx": "docx", "0001_figures.xlsx": "xlsx", "0002_appendix.odt": "odt"} {
+		if capture.files[name] != want {
+			t.Errorf("unexpected files %v", capture.files)
+		}
+	}
+}
+
+func TestConvertOfficeInvalidOptions(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	for _, r := range []*Request{
+		c.ConvertOffice(context.Background(), NamedReader{Name: "a.docx", Reader: strings.NewReader("a")}).NativePageRanges("first"),
+		c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).MergeDocuments(true),
+	} {
+		if _, err := r.Send(); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("expected ErrInvalidField, got %v", err)
+		}
+	}
+}
+
+func TestConvertOfficeImageOptions(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertOffice(context.Background(), NamedReader{Name: "figures.xlsx", Reader: strings.NewReader("xlsx")}).
+		LosslessImageCompression(false).
+		ImageQuality(60).
+		MaxImageResolution(150).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	for field, want := range map[string]string{
+		FieldLosslessImageCompression: "false",
+		FieldImageQuality:             "60",
+		FieldReduceImageResolution:    "true",
+		FieldMaxImageResolution:       "150",
+	} {
+		if got := capture.value(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+
+	for _, r := range []*Request{
+		c.ConvertOffice(context.Background(), NamedReader{Name: "a.docx", Reader: strings.NewReader("a")}).ImageQuality(0),
+		c.ConvertOffice(context.Background(), NamedReader{Name: "a.docx", Reader: strings.NewReader("a")}).MaxImageResolution(200),
+	} {
+		if _, err := r.Send(); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("expected ErrInvalidField, got %v", err)
+		}
+	}
+}
+
+func TestConvertOfficePassword(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertOffice(context.Background(), NamedReader{Name: "salaries.xlsx", Reader: strings.NewReader("xlsx")}).
+		Password("s3cret").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldPassword); got != "s3cret" {
+		t.Errorf("password = %q", got)
+	}
+}
+
+func TestConvertOfficeExportOptions(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertOffice(context.Background(), NamedReader{Name: "form.odt", Reader: strings.NewReader("odt")}).
+		NativePDFFormats(false).
+		ExportFormFields(true).
+		AllowDuplicateFieldNames(true).
+		ExportBookmarks(true).
+		ExportBookmarksToPDFDestination(true).
+		ExportPlaceholders(false).
+		ExportNotes(true).
+		ExportNotesPages(false).
+		ExportHiddenSlides(false).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	for field, want := range map[string]string{
+		FieldNativePDFFormats:                "false",
+		FieldExportFormFields:                "true",
+		FieldAllowDuplicateFieldNames:        "true",
+		FieldExportBookmarks:                 "true",
+		FieldExportBookmarksToPDFDestination: "true",
+		FieldExportPlaceholders:              "false",
+		FieldExportNotes:                     "true",
+		FieldExportNotesPages:                "false",
+		FieldExportHiddenSlides:              "false",
+	} {
+		if got := capture.value(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrJobFinished is returned when a job that is no longer pending is resubmitted or cancelled.
+var ErrJobFinished = errors.New("gotenberg: job already finished")
+
+// ErrJobChanged is returned when a job was submitted again concurrently, e.g.
+// by a Reaper of another instance, since it was read.
+var ErrJobChanged = errors.New("gotenberg: job changed concurrently")
+
+// Outbox submits conversion specs in webhook mode without losing them.
+// Every spec is persisted as a pending Job before it is sent to Gotenberg and
+// stays pending until its result arrives on the webhook, so specs submitted
+// before a crash can be found with Pending and sent again with Resubmit.
+//
+// The job ID is sent as the Gotenberg trace, which Gotenberg passes back with
+// the callbacks served by ResultHandler and ErrorHandler.
+type Outbox struct {
+	client  *Client
+	store   JobStore
+	webhook WebhookSpec
+	now     func() time.Time
+
+	// maxBody and memoryThreshold bound the documents received by ResultHandler
+	maxBody         int64
+	memoryThreshold int64
+
+	// pollInterval paces ConvertAndWait
+	pollInterval time.Duration
+
+	// inflight cancels the submissions in progress by job ID
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+// OutboxOption configures optional Outbox features.
+type OutboxOption func(*Outbox)
+
+// WithWebhookBodyLimit sets the maximum size of the documents accepted by
+// ResultHandler. Larger documents are answered with 413 and fail their job.
+// Documents are not limited by default.
+func WithWebhookBodyLimit(maxBytes int64) OutboxOption {
+	return func(o *Outbox) {
+		o.maxBody = maxBytes
+	}
+}
+
+// WithWebhookMemoryThreshold sets the size up to which ResultHandler buffers
+// documents in memory, larger documents are spooled to a temporary file.
+// The default is DefaultWebhookMemoryThreshold.
+func WithWebhookMemoryThreshold(n int64) OutboxOption {
+	return func(o *Outbox) {
+		o.memoryThreshold = n
+	}
+}
+
+// NewOutbox creates an Outbox persisting jobs in store. Specs without their own
+// webhook configuration are submitted with webhook.
+func NewOutbox(client *Client, store JobStore, webhook WebhookSpec, opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		client:          client,
+		store:           store,
+		webhook:         webhook,
+		now:             time.Now,
+		memoryThreshold: DefaultWebhookMemoryThreshold,
+		inflight:        make(map[string]context.CancelFunc),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Submit persists spec, with the defaults of its profile applied, as a pending
+// job and sends it to Gotenberg.
+// When sending fails the job is kept pending with the error recorded, and the
+// returned job is valid along with the error.
+func (o *Outbox) Submit(ctx context.Context, spec ConversionSpec) (Job, error) {
+	spec, err := o.client.ResolveSpec(spec)
+	if err != nil {
+		return Job{}, err
+	}
+	if spec.Webhook == nil {
+		webhook := o.webhook
+		spec.Webhook = &webhook
+	}
+	if err := spec.Validate(); err != nil {
+		return Job{}, err
+	}
+	if spec.Webhook.URL == "" {
+		return Job{}, fmt.Errorf("%w: outbox requires a webhook", ErrInvalidSpec)
+	}
+
+	job := Job{
+		ID:        newJobID(),
+		Spec:      spec,
+		Status:    JobPending,
+		CreatedAt: o.now(),
+		outbox:    o,
+	}
+	if err := o.store.Save(ctx, job); err != nil {
+		return Job{}, err
+	}
+	return o.send(ctx, job)
+}
+
+// Resubmit sends the pending job id to Gotenberg again, e.g. after a crash
+// or a failed submission.
+func (o *Outbox) Resubmit(ctx context.Context, id string) (Job, error) {
+	job, err := o.store.Get(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+	job.outbox = o
+	if job.Status != JobPending {
+		return job, fmt.Errorf("%w: %s is %s", ErrJobFinished, id, job.Status)
+	}
+	return o.send(ctx, job)
+}
+
+// Pending returns the jobs whose result has not arrived yet, oldest first.
+func (o *Outbox) Pending(ctx context.Context) ([]Job, error) {
+	jobs, err := o.store.List(ctx, JobPending)
+	for i := range jobs {
+		jobs[i].outbox = o
+	}
+	return jobs, err
+}
+
+// Cancel marks the pending job id cancelled and aborts its submission
+// if it is still uploading. Callbacks arriving later are acknowledged
+// and discarded.
+func (o *Outbox) Cancel(ctx context.Context, id string) error {
+	if err := o.finish(ctx, id, JobCancelled, "", nil); err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	cancel := o.inflight[id]
+	o.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+// send submits job and records the attempt. The attempt is only recorded if
+// the stored job is still pending with the attempts of job, so a job completed
+// or submitted again since it was read is not sent twice: send fails with
+// ErrJobFinished or ErrJobChanged and returns the stored job.
+func (o *Outbox) send(ctx context.Context, job Job) (Job, error) {
+	attempts := job.Attempts
+	stored, err := o.store.Update(ctx, job.ID, func(stored Job) (Job, error) {
+		if err := checkAttempt(stored, attempts); err != nil {
+			return stored, err
+		}
+		stored.Attempts++
+		stored.SubmittedAt = o.now()
+		stored.Error = ""
+		return stored, nil
+	})
+	stored.outbox = o
+	if err != nil {
+		return stored, err
+	}
+	job = stored
+
+	submitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	o.mu.Lock()
+	o.inflight[job.ID] = cancel
+	o.mu.Unlock()
+	defer func() {
+		o.mu.Lock()
+		delete(o.inflight, job.ID)
+		o.mu.Unlock()
+	}()
+
+	resp, err := o.client.FromSpec(submitCtx, job.Spec).
+		Trace(job.ID).
+		Send()
+	if err == nil {
+		err = resp.Err()
+	}
+	if err != nil {
+		// record the error on this attempt only, the job may have been
+		// cancelled, completed or submitted again meanwhile
+		message := err.Error()
+		saved, serr := o.store.Update(ctx, job.ID, func(stored Job) (Job, error) {
+			if err := checkAttempt(stored, job.Attempts); err != nil {
+				return stored, err
+			}
+			stored.Error = message
+			return stored, nil
+		})
+		saved.outbox = o
+		if serr != nil && !errors.Is(serr, ErrJobFinished) && !errors.Is(serr, ErrJobChanged) {
+			return saved, errors.Join(err, serr)
+		}
+		return saved, err
+	}
+	return job, nil
+}
+
+// checkAttempt reports whether the stored job is still pending with attempts
+// submissions.
+func checkAttempt(stored Job, attempts int) error {
+	if stored.Status != JobPending {
+		return fmt.Errorf("%w: %s is %s", ErrJobFinished, stored.ID, stored.Status)
+	}
+	if stored.Attempts != attempts {
+		return fmt.Errorf("%w: %s was submitted %d times, not %d", ErrJobChanged, stored.ID, stored.Attempts, attempts)
+	}
+	return nil
+}
+
+// finish records the final status of the job id, and the result of completed
+// jobs. Only pending jobs are finished, so of a cancellation racing a callback
+// exactly one wins; the other fails with ErrJobFinished.
+func (o *Outbox) finish(ctx context.Context, id string, status JobStatus, message string, result *Result) error {
+	_, err := o.store.Update(ctx, id, func(job Job) (Job, error) {
+		if job.Status != JobPending {
+			return job, fmt.Errorf("%w: %s is %s", ErrJobFinished, id, job.Status)
+		}
+		job.Status = status
+		job.Error = message
+		job.Result = result
+		job.CompletedAt = o.now()
+		return job, nil
+	})
+	return err
+}
+
+// ResultHandler returns the handler of the webhook URL. It passes the document
+// of a pending job to consume and marks the job completed once consume succeeds.
+// When consume fails the job stays pending and Gotenberg receives a 500; when
+// consume panics the panic is reported to the client's PanicHandler and the
+// job is marked failed.
+// The document is spooled before consume is called, see WithWebhookBodyLimit
+// and WithWebhookMemoryThreshold.
+// Callbacks for unknown jobs are answered with 404, callbacks for finished
+// or cancelled jobs are acknowledged without calling consume. A job cancelled
+// while consume runs stays cancelled, although its document was consumed.
+func (o *Outbox) ResultHandler(consume ResultConsumer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job, ok := o.callbackJob(w, r)
+		if !ok {
+			return
+		}
+		body, ok := o.spool(w, r, job)
+		if !ok {
+			return
+		}
+		defer body.Close()
+
+		result := newWebhookResult(r, body)
+		if err := o.consume(r.Context(), consume, job, result); err != nil {
+			if errors.Is(err, ErrPanic) {
+				if ferr := o.finish(r.Context(), job.ID, JobFailed, err.Error(), nil); ferr != nil && !errors.Is(ferr, ErrJobFinished) {
+					err = ferr
+				}
+			}
+			WriteError(w, r, err)
+			return
+		}
+		// a job cancelled meanwhile keeps its status, the callback is done
+		if err := o.finish(r.Context(), job.ID, JobCompleted, "", result.describe(job)); err != nil && !errors.Is(err, ErrJobFinished) {
+			WriteError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// consume calls consume, recovering its panics.
+func (o *Outbox) consume(ctx context.Context, consume ResultConsumer, job Job, result *WebhookResult) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = o.client.panicked(job.ID, p)
+		}
+	}()
+	return consume(ctx, job, result)
+}
+
+// spool reads the document of a callback for job. It answers the callback
+// itself and returns false when the document cannot be read.
+func (o *Outbox) spool(w http.ResponseWriter, r *http.Request, job Job) (*spooledBody, bool) {
+	var reader io.Reader = r.Body
+	if o.maxBody > 0 {
+		reader = http.MaxBytesReader(w, r.Body, o.maxBody)
+	}
+
+	body, err := o.client.spoolBody(reader, o.memoryThreshold)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		message := fmt.Sprintf("gotenberg: webhook document exceeds %d bytes", tooLarge.Limit)
+		if err := o.finish(r.Context(), job.ID, JobFailed, message, nil); err != nil && !errors.Is(err, ErrJobFinished) {
+			WriteError(w, r, err)
+			return nil, false
+		}
+		writeErrorResponse(w, r, http.StatusRequestEntityTooLarge, message)
+		return nil, false
+	}
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to read document", err)
+		return nil, false
+	}
+	return body, true
+}
+
+// ErrorHandler returns the handler of the webhook error URL. It marks the job
+// failed, keeping the message of the error posted by Gotenberg, and passes the
+// decoded error to onError, if not nil. Panics of onError are reported to the
+// client's PanicHandler.
+func (o *Outbox) ErrorHandler(onError func(ctx context.Context, job Job, err *GotenbergError)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job, ok := o.callbackJob(w, r)
+		if !ok {
+			return
+		}
+		gerr, err := DecodeWebhookError(r)
+		if err != nil {
+			writeErrorCause(w, r, http.StatusBadRequest, "Failed to decode error", err)
+			return
+		}
+		err = o.finish(r.Context(), job.ID, JobFailed, gerr.Error(), nil)
+		if errors.Is(err, ErrJobFinished) {
+			// cancelled or finished meanwhile
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		if onError != nil {
+			job.Status, job.Error = JobFailed, gerr.Error()
+			func() {
+				defer func() {
+					if p := recover(); p != nil {
+						o.client.panicked(job.ID, p)
+					}
+				}()
+				onError(r.Context(), job, gerr)
+			}()
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// callbackJob returns the pending job of a webhook callback.
+// It answers the callback itself and returns false when there is nothing to do.
+func (o *Outbox) callbackJob(w http.ResponseWriter, r *http.Request) (Job, bool) {
+	trace := r.Header.Get(HeaderGotenbergTrace)
+	if trace == "" {
+		writeErrorResponse(w, r, http.StatusBadRequest, "Missing "+HeaderGotenbergTrace+" header")
+		return Job{}, false
+	}
+	job, err := o.store.Get(r.Context(), trace)
+	if errors.Is(err, ErrJobNotFound) {
+		writeErrorResponse(w, r, http.StatusNotFound, "Unknown job")
+		return Job{}, false
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return Job{}, false
+	}
+	if job.Status != JobPending {
+		w.WriteHeader(http.StatusOK)
+		return Job{}, false
+	}
+	return job, true
+}
@@ -0,0 +1,304 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+var testWebhook = WebhookSpec{URL: "http://hook/result", ErrorURL: "http://hook/error"}
+
+func testSpec() ConversionSpec {
+	return ConversionSpec{
+		Route: ConvertHTML,
+		Files: []FileRef{{Filename: FileIndexHTML, Data: []byte("<html></html>")}},
+	}
+}
+
+func TestOutboxSubmit(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+
+	job, err := outbox.Submit(context.Background(), testSpec())
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	if got := capture.header.Get(HeaderGotenbergTrace); got != job.ID {
+		t.Errorf("expected trace %s, got %s", job.ID, got)
+	}
+	if got := capture.header.Get(HeaderWebhookURL); got != testWebhook.URL {
+		t.Errorf("unexpected webhook url %s", got)
+	}
+
+	pending, _ := outbox.Pending(context.Background())
+	if len(pending) != 1 || pending[0].ID != job.ID || pending[0].Attempts != 1 {
+		t.Errorf("unexpected pending jobs %+v", pending)
+	}
+}
+
+type failingRoundTripper struct{}
+
+func (failingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestOutboxKeepsFailedSubmissions(t *testing.T) {
+	store := NewMemoryJobStore()
+	failing, err := NewClient(&http.Client{Transport: failingRoundTripper{}}, "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := NewOutbox(failing, store, testWebhook).Submit(context.Background(), testSpec())
+	if err == nil {
+		t.Fatal("expected submission error")
+	}
+	saved, _ := store.Get(context.Background(), job.ID)
+	if saved.Status != JobPending || saved.Error == "" {
+		t.Errorf("expected pending job with error, got %+v", saved)
+	}
+
+	// after a restart the job is sent again
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	job, err = outbox.Resubmit(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("Resubmit failed: %v", err)
+	}
+	if job.Attempts != 2 || job.Error != "" {
+		t.Errorf("unexpected job %+v", job)
+	}
+}
+
+func TestOutboxResultHandler(t *testing.T) {
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	job, err := outbox.Submit(context.Background(), testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var consumed []string
+	handler := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		data, _ := io.ReadAll(result.Body)
+		consumed = append(consumed, job.ID+":"+result.Filename+":"+string(data))
+		return nil
+	})
+
+	callback := func(trace string) int {
+		req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+		req.Header.Set(HeaderGotenbergTrace, trace)
+		req.Header.Set("Content-Disposition", `attachment; filename="out.pdf"`)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := callback(job.ID); code != http.StatusOK {
+		t.Fatalf("unexpected status %d", code)
+	}
+	if len(consumed) != 1 || consumed[0] != job.ID+":out.pdf:pdf" {
+		t.Errorf("unexpected consumed results %v", consumed)
+	}
+	saved, _ := store.Get(context.Background(), job.ID)
+	if saved.Status != JobCompleted {
+		t.Errorf("expected completed job, got %s", saved.Status)
+	}
+
+	// duplicate callbacks are acknowledged without consuming the result again
+	if code := callback(job.ID); code != http.StatusOK || len(consumed) != 1 {
+		t.Errorf("duplicate callback: status %d, consumed %v", code, consumed)
+	}
+	if code := callback("unknown"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown job, got %d", code)
+	}
+}
+
+func TestOutboxErrorHandler(t *testing.T) {
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	job, err := outbox.Submit(context.Background(), testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/error", strings.NewReader(`{"status":400,"message":"bad html"}`))
+	req.Header.Set(HeaderGotenbergTrace, job.ID)
+	rec := httptest.NewRecorder()
+	var reported *GotenbergError
+	outbox.ErrorHandler(func(ctx context.Context, job Job, err *GotenbergError) {
+		reported = err
+	}).ServeHTTP(rec, req)
+
+	saved, _ := store.Get(context.Background(), job.ID)
+	if rec.Code != http.StatusOK || saved.Status != JobFailed || !strings.Contains(saved.Error, "bad html") {
+		t.Errorf("unexpected status %d, job %+v", rec.Code, saved)
+	}
+	if reported == nil || reported.StatusCode != 400 || reported.Message != "bad html" || reported.Trace != job.ID {
+		t.Errorf("unexpected error %+v", reported)
+	}
+	if _, err := outbox.Resubmit(context.Background(), job.ID); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("expected ErrJobFinished, got %v", err)
+	}
+}
+
+// blockingRoundTripper blocks until the request is cancelled
+type blockingRoundTripper struct {
+	started chan struct{}
+}
+
+func (b *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	close(b.started)
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestJobCancel(t *testing.T) {
+	ctx := context.Background()
+	transport := &blockingRoundTripper{started: make(chan struct{})}
+	c, err := NewClient(&http.Client{Transport: transport}, "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+
+	submitted := make(chan error, 1)
+	go func() {
+		_, err := outbox.Submit(ctx, testSpec())
+		submitted <- err
+	}()
+	<-transport.started
+
+	pending, _ := outbox.Pending(ctx)
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending job, got %d", len(pending))
+	}
+	job := pending[0]
+	if err := job.Cancel(); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if err := <-submitted; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancelled submission, got %v", err)
+	}
+	if saved, _ := store.Get(ctx, job.ID); saved.Status != JobCancelled {
+		t.Errorf("expected cancelled job, got %s", saved.Status)
+	}
+
+	// late callbacks are discarded
+	handler := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		t.Error("result of cancelled job consumed")
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+	req.Header.Set(HeaderGotenbergTrace, job.ID)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+
+	if err := job.Cancel(); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("expected ErrJobFinished, got %v", err)
+	}
+}
+
+func TestJobCancelDuringConsume(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	job, err := outbox.Submit(ctx, testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		if err := job.Cancel(); err != nil {
+			t.Errorf("Cancel failed: %v", err)
+		}
+		return nil
+	})
+	req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+	req.Header.Set(HeaderGotenbergTrace, job.ID)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("unexpected status %d", rec.Code)
+	}
+	if saved, _ := store.Get(ctx, job.ID); saved.Status != JobCancelled {
+		t.Errorf("cancelled job overwritten with %s", saved.Status)
+	}
+}
+
+func TestJobCancelRacingCallback(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	handler := outbox.ResultHandler(func(context.Context, Job, *WebhookResult) error { return nil })
+
+	for i := 0; i < 20; i++ {
+		job, err := outbox.Submit(ctx, testSpec())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cancelled := make(chan error, 1)
+		go func() { cancelled <- job.Cancel() }()
+		req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+		req.Header.Set(HeaderGotenbergTrace, job.ID)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		err = <-cancelled
+		saved, _ := store.Get(ctx, job.ID)
+		switch {
+		case err == nil && saved.Status != JobCancelled:
+			t.Errorf("successful Cancel left the job %s", saved.Status)
+		case err != nil && (!errors.Is(err, ErrJobFinished) || saved.Status != JobCompleted):
+			t.Errorf("Cancel failed with %v, job %s", err, saved.Status)
+		}
+	}
+}
+
+func TestOutboxSpoolsLargeResults(t *testing.T) {
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook,
+		WithWebhookMemoryThreshold(4), WithWebhookBodyLimit(16))
+
+	var spooled *spooledBody
+	handler := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		spooled = result.Body.(*spooledBody)
+		data, _ := io.ReadAll(result.Body)
+		if string(data) != "0123456789" || result.Size != 10 || spooled.file == nil {
+			t.Errorf("unexpected result %q, size %d", data, result.Size)
+		}
+		return nil
+	})
+
+	post := func(body string) (Job, int) {
+		job, err := outbox.Submit(context.Background(), testSpec())
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader(body))
+		req.Header.Set(HeaderGotenbergTrace, job.ID)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		saved, _ := store.Get(context.Background(), job.ID)
+		return saved, rec.Code
+	}
+
+	if job, code := post("0123456789"); code != http.StatusOK || job.Status != JobCompleted {
+		t.Errorf("unexpected status %d, job %+v", code, job)
+	}
+	if _, err := os.Stat(spooled.file.Name()); !os.IsNotExist(err) {
+		t.Errorf("temporary file not removed: %v", err)
+	}
+
+	if job, code := post(strings.Repeat("x", 17)); code != http.StatusRequestEntityTooLarge || job.Status != JobFailed {
+		t.Errorf("unexpected status %d, job %+v", code, job)
+	}
+}
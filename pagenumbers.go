@@ -0,0 +1,129 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// pageNumberBand is the height in points added below every page for the footer.
+const pageNumberBand = 28
+
+// PageNumberStamper regenerates existing PDFs with page numbers in the footer.
+//
+// Gotenberg cannot edit existing PDFs, so the stamper uses Chromium: the PDF
+// is embedded into an HTML viewer page that draws every page with pdf.js,
+// one page per sheet, and the page is converted with a footer.html containing
+// Chromium's pageNumber and totalPages placeholders. The sheets keep the size
+// of the original pages, plus a band below them holding the footer.
+//
+// The pages are rasterized: text is no longer selectable and Scale trades
+// resolution for output size. Use it for scanned or generated documents, not
+// when the text layer must be preserved.
+type PageNumberStamper struct {
+	Client *Client
+
+	// Format is the footer text, where {page} and {total} are replaced
+	// with the page number and the page count. "{page} / {total}" if empty.
+	Format string
+
+	// Scale is the pdf.js render scale of the pages, 2 (144 dpi) if zero.
+	Scale float64
+
+	// PDFJSURL is the base URL of the pdf.js build, DefaultPDFJSURL if
+	// empty, which Gotenberg loads from the internet.
+	PDFJSURL string
+}
+
+// Stamp returns pdf with page numbers.
+func (s *PageNumberStamper) Stamp(ctx context.Context, pdf io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := s.Scale
+	if scale == 0 {
+		scale = 2
+	}
+	pdfjs := s.PDFJSURL
+	if pdfjs == "" {
+		pdfjs = DefaultPDFJSURL
+	}
+
+	resp, err := s.Client.ConvertHTML(ctx, strings.NewReader(pageNumberViewer(pdfjs, scale, data))).
+		FooterHTML(strings.NewReader(pageNumberFooter(s.Format))).
+		Bool(FieldPreferCSSPageSize, true).
+		Margins(0, 0, pageNumberBand/72.0, 0).
+		Param(FieldWaitForExpression, "window.stampReady === true").
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// pageNumberFooter returns the footer.html showing format.
+func pageNumberFooter(format string) string {
+	if format == "" {
+		format = "{page} / {total}"
+	}
+	text := strings.NewReplacer(
+		"{page}", `<span class="pageNumber"></span>`,
+		"{total}", `<span class="totalPages"></span>`,
+	).Replace(html.EscapeString(format))
+
+	return `<!DOCTYPE html>
+<html>
+<head>
+<style>body { margin: 0; font-family: sans-serif; font-size: 10px; } p { margin: 0 0 8px; width: 100%; text-align: center; }</style>
+</head>
+<body><p>` + text + `</p></body>
+</html>`
+}
+
+// pageNumberViewer returns an HTML page drawing every page of pdf on its own sheet.
+// The sheet size is taken from the first page.
+func pageNumberViewer(pdfjs string, scale float64, pdf []byte) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<style>html, body { margin: 0; background: #fff; } canvas { display: block; break-after: page; } canvas:last-child { break-after: auto; }</style>
+<style id="page-size"></style>
+<script src="%[1]s/pdf.min.js"></script>
+</head>
+<body>
+<script>
+pdfjsLib.GlobalWorkerOptions.workerSrc = "%[1]s/pdf.worker.min.js";
+pdfjsLib.getDocument({ data: atob("%[2]s") }).promise.then(async function (doc) {
+	for (var i = 1; i <= doc.numPages; i++) {
+		var page = await doc.getPage(i);
+		var size = page.getViewport({ scale: 1 });
+		var viewport = page.getViewport({ scale: %[3]g });
+		if (i === 1) {
+			document.getElementById("page-size").textContent =
+				"@page { size: " + size.width + "pt " + (size.height + %[4]d) + "pt; margin: 0 0 %[4]dpt 0; }";
+		}
+		var canvas = document.createElement("canvas");
+		canvas.width = viewport.width;
+		canvas.height = viewport.height;
+		canvas.style.width = size.width + "pt";
+		canvas.style.height = size.height + "pt";
+		document.body.appendChild(canvas);
+		await page.render({ canvasContext: canvas.getContext("2d"), viewport: viewport }).promise;
+	}
+	window.stampReady = true;
+});
+</script>
+</body>
+</html>`, pdfjs, base64.StdEncoding.EncodeToString(pdf), scale, pageNumberBand)
+	return b.String()
+}
@@ -0,0 +1,35 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPageNumberStamper(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	stamper := &PageNumberStamper{Client: c, Format: "Page {page} of {total} <draft>"}
+
+	out, err := stamper.Stamp(context.Background(), strings.NewReader("%PDF-1.7"))
+	if err != nil {
+		t.Fatalf("Stamp failed: %v", err)
+	}
+	data, _ := io.ReadAll(out)
+	out.Close()
+	if string(data) != "pdf-bytes" {
+		t.Errorf("unexpected output %q", data)
+	}
+
+	if !strings.Contains(capture.files[FileIndexHTML], base64.StdEncoding.EncodeToString([]byte("%PDF-1.7"))) {
+		t.Error("PDF not embedded into the viewer page")
+	}
+	footer := capture.files[FileFooterHTML]
+	if !strings.Contains(footer, `Page <span class="pageNumber"></span> of <span class="totalPages"></span> &lt;draft&gt;`) {
+		t.Errorf("unexpected footer %s", footer)
+	}
+	if capture.value(FieldPreferCSSPageSize) != "true" || capture.value(FieldWaitForExpression) != "window.stampReady === true" {
+		t.Error("missing page size or wait fields")
+	}
+}
@@ -0,0 +1,43 @@
+package gotenberg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// ErrPanic is returned for work that panicked in a background goroutine of
+// the client, such as a file reader read while the upload is streamed, a
+// template executed for ConvertTemplate or a webhook consumer.
+var ErrPanic = errors.New("gotenberg: recovered panic")
+
+// PanicHandler receives the panics recovered in background goroutines, with
+// the trace of the conversion or the ID of the job it belongs to, if known,
+// and the stack of the panicking goroutine.
+type PanicHandler func(trace string, recovered any, stack []byte)
+
+// WithPanicHandler sets the handler of the panics the client recovers
+// instead of crashing the process. By default they are logged with the
+// standard logger.
+func WithPanicHandler(handler PanicHandler) ClientOption {
+	return func(c *Client) {
+		c.panicHandler = handler
+	}
+}
+
+// logPanic is the default PanicHandler.
+func logPanic(trace string, recovered any, stack []byte) {
+	log.Printf("gotenberg: panic (trace %q): %v\n%s", trace, recovered, stack)
+}
+
+// panicked reports recovered, the value of a recover call, to the panic
+// handler and returns it as an error wrapping ErrPanic.
+func (c *Client) panicked(trace string, recovered any) error {
+	handler := c.panicHandler
+	if handler == nil {
+		handler = logPanic
+	}
+	handler(trace, recovered, debug.Stack())
+	return fmt.Errorf("%w: %v", ErrPanic, recovered)
+}
@@ -0,0 +1,140 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// panickingReader panics on its first read
+type panickingReader struct{}
+
+func (panickingReader) Read(p []byte) (int, error) {
+	panic("reader exploded")
+}
+
+type panicTemplate struct{}
+
+func (panicTemplate) Execute(w io.Writer, data any) error {
+	panic("template exploded")
+}
+
+// recordingPanicHandler records the recovered panics by trace
+type recordingPanicHandler struct {
+	mu     sync.Mutex
+	panics map[string]any
+}
+
+func (h *recordingPanicHandler) handle(trace string, recovered any, stack []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.panics == nil {
+		h.panics = make(map[string]any)
+	}
+	h.panics[trace] = recovered
+}
+
+func (h *recordingPanicHandler) get(trace string) any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.panics[trace]
+}
+
+func TestPanicInFileReader(t *testing.T) {
+	handler := &recordingPanicHandler{}
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL, WithPanicHandler(handler.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ConvertHTML(context.Background(), panickingReader{}).Trace("trace-1").Send()
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected ErrPanic, got %v", err)
+	}
+	if got := handler.get("trace-1"); got != "reader exploded" {
+		t.Errorf("unexpected recovered panic %v", got)
+	}
+	if stats := c.Stats(); stats.ActiveRequests != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestPanicInTemplate(t *testing.T) {
+	handler := &recordingPanicHandler{}
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL, WithPanicHandler(handler.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.ConvertTemplate(context.Background(), panicTemplate{}, nil).Trace("trace-2").Send()
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected ErrPanic, got %v", err)
+	}
+	if got := handler.get("trace-2"); got != "template exploded" {
+		t.Errorf("unexpected recovered panic %v", got)
+	}
+}
+
+func TestPanicInResultConsumer(t *testing.T) {
+	handler := &recordingPanicHandler{}
+	c, _ := newCaptureClient(t, WithPanicHandler(handler.handle))
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+	job, err := outbox.Submit(context.Background(), testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		panic("consumer exploded")
+	})
+	req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+	req.Header.Set(HeaderGotenbergTrace, job.ID)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", rec.Code)
+	}
+	if got := handler.get(job.ID); got != "consumer exploded" {
+		t.Errorf("unexpected recovered panic %v", got)
+	}
+	if saved, _ := store.Get(context.Background(), job.ID); saved.Status != JobFailed || !strings.Contains(saved.Error, "consumer exploded") {
+		t.Errorf("expected failed job, got %+v", saved)
+	}
+}
+
+func TestPanicInReaper(t *testing.T) {
+	ctx := context.Background()
+	handler := &recordingPanicHandler{}
+	c, _ := newCaptureClient(t, WithPanicHandler(handler.handle))
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+	now := time.Now()
+	outbox.now = func() time.Time { return now }
+	job, _ := outbox.Submit(ctx, testSpec())
+	now = now.Add(10 * time.Minute)
+
+	reaper := &Reaper{
+		Outbox:     outbox,
+		Timeout:    5 * time.Minute,
+		OnResubmit: func(job Job, err error) { panic("callback exploded") },
+	}
+	if err := reaper.Reap(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := handler.get(job.ID); got != "callback exploded" {
+		t.Errorf("unexpected recovered panic %v", got)
+	}
+	if saved, _ := store.Get(ctx, job.ID); saved.Status != JobFailed {
+		t.Errorf("expected failed job, got %+v", saved)
+	}
+}
@@ -0,0 +1,206 @@
+package gotenberg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PDFMetadata is the metadata of a PDF read by ReadMetadata.
+type PDFMetadata struct {
+	Title        string
+	Author       string
+	Subject      string
+	Keywords     []string
+	Creator      string
+	Producer     string
+	PDFVersion   string
+	CreationDate time.Time
+	ModDate      time.Time
+
+	// Raw holds every entry reported by Gotenberg, including those above
+	// in their original form.
+	Raw map[string]any
+}
+
+// exifDateLayouts are the date formats of the metadata Gotenberg reports.
+var exifDateLayouts = []string{
+	"2006:01:02 15:04:05Z07:00",
+	"2006:01:02 15:04:05",
+	time.RFC3339,
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *PDFMetadata) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.Raw); err != nil {
+		return err
+	}
+	m.Title = metadataString(m.Raw["Title"])
+	m.Author = metadataString(m.Raw["Author"])
+	m.Subject = metadataString(m.Raw["Subject"])
+	m.Creator = metadataString(m.Raw["Creator"])
+	m.Producer = metadataString(m.Raw["Producer"])
+	m.PDFVersion = metadataString(m.Raw["PDFVersion"])
+	m.Keywords = metadataList(m.Raw["Keywords"])
+	m.CreationDate = metadataDate(m.Raw["CreateDate"], m.Raw["CreationDate"])
+	m.ModDate = metadataDate(m.Raw["ModifyDate"], m.Raw["ModDate"])
+	return nil
+}
+
+// metadataString formats a metadata value, e.g. the number 1.7 of PDFVersion.
+func metadataString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// metadataList returns a list value, or a comma separated string, as a list.
+func metadataList(v any) []string {
+	switch v := v.(type) {
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, metadataString(item))
+		}
+		return list
+	case string:
+		var list []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// metadataDate parses the first date of values that is set.
+func metadataDate(values ...any) time.Time {
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			continue
+		}
+		for _, layout := range exifDateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// ReadMetadata reads the metadata of PDFs with Gotenberg's pdfengines
+// metadata read route and returns it by filename.
+func (c *Client) ReadMetadata(ctx context.Context, pdfs ...NamedReader) (map[string]PDFMetadata, error) {
+	r := c.newRequest(ctx, ReadMetadata)
+	if len(pdfs) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	for _, pdf := range pdfs {
+		r.File(FieldFiles, pdf.Name, pdf.Reader)
+	}
+	resp, err := r.Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var metadata map[string]PDFMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("gotenberg: decode metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// entries returns the set fields of m as the metadata entries written by
+// WriteMetadata. PDFVersion and Raw are not written.
+func (m PDFMetadata) entries() map[string]any {
+	entries := make(map[string]any)
+	for key, value := range map[string]string{
+		"Title":    m.Title,
+		"Author":   m.Author,
+		"Subject":  m.Subject,
+		"Creator":  m.Creator,
+		"Producer": m.Producer,
+	} {
+		if value != "" {
+			entries[key] = value
+		}
+	}
+	if len(m.Keywords) > 0 {
+		entries["Keywords"] = m.Keywords
+	}
+	if !m.CreationDate.IsZero() {
+		entries["CreationDate"] = m.CreationDate.Format(time.RFC3339)
+	}
+	if !m.ModDate.IsZero() {
+		entries["ModDate"] = m.ModDate.Format(time.RFC3339)
+	}
+	return entries
+}
+
+// WriteMetadata creates a request writing metadata to existing PDFs through
+// Gotenberg's pdfengines metadata write route; the response holds the
+// rewritten PDFs. metadata is either a PDFMetadata, whose set fields are
+// written, or a map or struct encoding to a JSON object of entries:
+//
+//	client.WriteMetadata(ctx, map[string]any{"Author": "Jane Doe", "Keywords": []string{"invoice"}},
+//		gotenberg.NamedReader{Name: "invoice.pdf", Reader: pdf}).
+//		Send()
+//
+// More entries can be set with Metadata. Send fails with ErrInvalidField
+// when no entry is set.
+func (c *Client) WriteMetadata(ctx context.Context, metadata any, pdfs ...NamedReader) *Request {
+	r := c.newRequest(ctx, WriteMetadata)
+	if len(pdfs) == 0 {
+		r.setErr(ErrNoDocuments)
+	}
+	for _, pdf := range pdfs {
+		r.File(FieldFiles, pdf.Name, pdf.Reader)
+	}
+	return r.MetadataEntries(metadata)
+}
+
+// metadataEntries returns the entries of the metadata given to WriteMetadata
+// and MetadataEntries.
+func metadataEntries(metadata any) (map[string]any, error) {
+	switch m := metadata.(type) {
+	case nil:
+		return nil, nil
+	case PDFMetadata:
+		return m.entries(), nil
+	case *PDFMetadata:
+		if m == nil {
+			return nil, nil
+		}
+		return m.entries(), nil
+	case map[string]any:
+		return m, nil
+	case map[string]string:
+		entries := make(map[string]any, len(m))
+		for key, value := range m {
+			entries[key] = value
+		}
+		return entries, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("gotenberg: encode metadata: %w", err)
+	}
+	var entries map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%w: metadata is not an object of entries", ErrInvalidField)
+	}
+	return entries, nil
+}
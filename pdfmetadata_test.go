@@ -0,0 +1,119 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != ReadMetadata {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"report.pdf": {
+				"Author": "Jane Doe",
+				"CreateDate": "2026:09:30 17:50:06+02:00",
+				"Creator": "Chromium",
+				"Keywords": ["invoice", "2026"],
+				"Marked": true,
+				"ModifyDate": "2026:10:01 08:00:00",
+				"PDFVersion": 1.7,
+				"Producer": "Skia/PDF m118",
+				"Title": "Report"
+			},
+			"scan.pdf": {"Keywords": "scan, archive", "PDFVersion": 1.4}
+		}`))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := c.ReadMetadata(context.Background(),
+		NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF")},
+		NamedReader{Name: "scan.pdf", Reader: strings.NewReader("%PDF")})
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+
+	report := metadata["report.pdf"]
+	if report.Title != "Report" || report.Author != "Jane Doe" || report.Creator != "Chromium" || report.Producer != "Skia/PDF m118" || report.PDFVersion != "1.7" {
+		t.Errorf("unexpected metadata %+v", report)
+	}
+	if want := time.Date(2026, 9, 30, 15, 50, 6, 0, time.UTC); !report.CreationDate.Equal(want) {
+		t.Errorf("unexpected creation date %v", report.CreationDate)
+	}
+	if want := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC); !report.ModDate.Equal(want) {
+		t.Errorf("unexpected modification date %v", report.ModDate)
+	}
+	if strings.Join(report.Keywords, "|") != "invoice|2026" || report.Raw["Marked"] != true {
+		t.Errorf("unexpected keywords %v or raw %v", report.Keywords, report.Raw)
+	}
+	if scan := metadata["scan.pdf"]; strings.Join(scan.Keywords, "|") != "scan|archive" || scan.PDFVersion != "1.4" {
+		t.Errorf("unexpected metadata %+v", scan)
+	}
+
+	if _, err := c.ReadMetadata(context.Background()); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
+
+func TestWriteMetadata(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	created := time.Date(2026, 9, 30, 17, 50, 6, 0, time.UTC)
+	_, err := c.WriteMetadata(context.Background(),
+		PDFMetadata{Title: "Report", Keywords: []string{"invoice", "2026"}, CreationDate: created, PDFVersion: "1.7"},
+		NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF")}).
+		Metadata("Trapped", "Unknown").
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != WriteMetadata {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	want := `{"CreationDate":"2026-09-30T17:50:06Z","Keywords":["invoice","2026"],"Title":"Report","Trapped":"Unknown"}`
+	if got := capture.value(FieldMetadata); got != want {
+		t.Errorf("unexpected metadata %s", got)
+	}
+	if capture.files["report.pdf"] != "%PDF" {
+		t.Errorf("unexpected files %v", capture.files)
+	}
+
+	type invoiceMetadata struct {
+		Author  string `json:"Author"`
+		Subject string `json:"Subject,omitempty"`
+	}
+	if _, err := c.WriteMetadata(context.Background(), invoiceMetadata{Author: "Jane Doe"},
+		NamedReader{Name: "invoice.pdf", Reader: strings.NewReader("%PDF")}).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.value(FieldMetadata); got != `{"Author":"Jane Doe"}` {
+		t.Errorf("unexpected metadata %s", got)
+	}
+}
+
+func TestWriteMetadataRequiresEntries(t *testing.T) {
+	c := newTestClient(t)
+	pdf := NamedReader{Name: "report.pdf", Reader: strings.NewReader("%PDF")}
+	for _, metadata := range []any{nil, map[string]any{}, PDFMetadata{PDFVersion: "1.7"}, (*PDFMetadata)(nil)} {
+		if err := c.WriteMetadata(context.Background(), metadata, pdf).Validate(); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("%#v: expected ErrInvalidField, got %v", metadata, err)
+		}
+	}
+	if err := c.WriteMetadata(context.Background(), "Report", pdf).Validate(); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("expected ErrInvalidField for a non-object, got %v", err)
+	}
+	if err := c.WriteMetadata(context.Background(), map[string]string{"Title": "Report"}).Validate(); !errors.Is(err, ErrNoDocuments) {
+		t.Errorf("expected ErrNoDocuments, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+)
+
+// Post-processing actions of a PostStep.
+const (
+	// StepStore saves the document to storage.
+	StepStore = "store"
+	// StepNotify posts a Notification to a URL.
+	StepNotify = "notify"
+	// StepMerge merges a stored cover page before the document.
+	StepMerge = "merge"
+)
+
+// PostStep is a post-processing step of a Profile, executed by a Pipeline once
+// the webhook result of a job with the profile arrives.
+type PostStep struct {
+	// Action is StepStore, StepNotify or StepMerge.
+	Action string `json:"action"`
+
+	// Key is the object key of StepStore, DefaultResultKey if empty.
+	Key KeyTemplate `json:"key,omitempty"`
+	// URL receives the Notification of StepNotify.
+	URL string `json:"url,omitempty"`
+	// Cover is the stored PDF StepMerge puts before the document.
+	Cover string `json:"cover,omitempty"`
+}
+
+// validate reports whether the step can be executed.
+func (s PostStep) validate() error {
+	switch {
+	case s.Action == StepStore:
+	case s.Action == StepNotify && s.URL != "":
+	case s.Action == StepMerge && s.Cover != "":
+	case s.Action == StepNotify, s.Action == StepMerge:
+		return fmt.Errorf("%w: %s step is incomplete", ErrInvalidSpec, s.Action)
+	default:
+		return fmt.Errorf("%w: unknown post-processing action %q", ErrInvalidSpec, s.Action)
+	}
+	return nil
+}
+
+// Notification is posted as JSON by StepNotify.
+type Notification struct {
+	JobID   string `json:"jobId"`
+	Tenant  string `json:"tenant,omitempty"`
+	Profile string `json:"profile"`
+	Trace   string `json:"trace,omitempty"`
+	// Object is the key of the document saved by the last StepStore before the notification.
+	Object string `json:"object,omitempty"`
+	Size   int64  `json:"size"`
+}
+
+// Pipeline is a ResultConsumer executing the post-processing steps of the
+// profile of every job, in order, e.g. merge a cover page, store the merged
+// document and notify a downstream service. Use Consume as the ResultConsumer
+// of Outbox.ResultHandler. Jobs without a profile or steps are left alone.
+type Pipeline struct {
+	// Client provides the profiles and performs merges.
+	Client *Client
+	// Storage stores documents and provides cover pages.
+	Storage Storage
+	// HTTPClient sends notifications, http.DefaultClient if nil.
+	HTTPClient *http.Client
+}
+
+// Consume implements ResultConsumer. A failing step stops the pipeline and
+// keeps the job pending, so the result is processed again when redelivered.
+// Storage operations are labelled with the tenant of the job, see ContextWithTenant.
+func (p *Pipeline) Consume(ctx context.Context, job Job, result *WebhookResult) error {
+	if job.Spec.Profile == "" {
+		return nil
+	}
+	ctx = ContextWithTenant(ctx, job.Spec.Tenant)
+	profile, ok := p.Client.profiles[job.Spec.Profile]
+	if !ok {
+		return fmt.Errorf("%w: unknown profile %q", ErrInvalidSpec, job.Spec.Profile)
+	}
+
+	var object string
+	for i, step := range profile.PostProcess {
+		if _, err := result.Body.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		var err error
+		switch step.Action {
+		case StepStore:
+			object = step.Key.Expand(job, result)
+			_, err = p.Storage.UploadFile(ctx, object, result.Body, result.Size, result.ContentType)
+		case StepNotify:
+			err = p.notify(ctx, step.URL, Notification{
+				JobID:   job.ID,
+				Tenant:  job.Spec.Tenant,
+				Profile: job.Spec.Profile,
+				Trace:   result.Trace,
+				Object:  object,
+				Size:    result.Size,
+			})
+		case StepMerge:
+			var merged *spooledBody
+			if merged, err = p.mergeCover(ctx, step.Cover, result); err == nil {
+				defer merged.Close()
+				result = &WebhookResult{
+					Trace:       result.Trace,
+					ContentType: "application/pdf",
+					Filename:    result.Filename,
+					Size:        merged.size,
+					Checksum:    merged.checksum,
+					Body:        merged,
+				}
+			}
+		default:
+			err = step.validate()
+		}
+		if err != nil {
+			return fmt.Errorf("gotenberg: profile %s step %d (%s): %w", job.Spec.Profile, i, step.Action, err)
+		}
+	}
+	return nil
+}
+
+// mergeCover merges the stored cover before the document of result.
+func (p *Pipeline) mergeCover(ctx context.Context, cover string, result *WebhookResult) (*spooledBody, error) {
+	content, err := p.Storage.DownloadFile(ctx, cover)
+	if err != nil {
+		return nil, err
+	}
+	req := p.Client.MergePDFs(ctx,
+		NamedReader{Name: cover, Reader: content},
+		NamedReader{Name: documentName(result), Reader: result.Body})
+	req.closers = append(req.closers, content)
+	resp, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return p.Client.spoolBody(resp.Body, DefaultWebhookMemoryThreshold)
+}
+
+// documentName is the filename of result, "document" with its extension if it has none.
+func documentName(result *WebhookResult) string {
+	if result.Filename != "" {
+		return path.Base(result.Filename)
+	}
+	return "document" + resultExt(result)
+}
+
+// notify posts n as JSON to url.
+func (p *Pipeline) notify(ctx context.Context, url string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("gotenberg: notification to %s failed with status %d", url, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPipeline(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+
+	var notified Notification
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&notified)
+	}))
+	defer hook.Close()
+
+	profiles := Profiles{"invoice": {PostProcess: []PostStep{
+		{Action: StepStore, Key: "raw/{job}{ext}"},
+		{Action: StepMerge, Cover: "covers/invoice.pdf"},
+		{Action: StepStore, Key: "invoices/{job}.pdf"},
+		{Action: StepNotify, URL: hook.URL},
+	}}}
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL, WithProfiles(profiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+	storage.UploadFile(context.Background(), "covers/invoice.pdf", strings.NewReader("cover"), 5, "application/pdf")
+
+	pipeline := &Pipeline{Client: c, Storage: storage}
+	job := Job{ID: "job1", Spec: ConversionSpec{Profile: "invoice", Tenant: "acme"}, CreatedAt: time.Now()}
+	result := &WebhookResult{Trace: "job1", ContentType: "application/pdf", Size: 3, Body: bytes.NewReader([]byte("pdf"))}
+	if err := pipeline.Consume(context.Background(), job, result); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	if got := string(storage.files["raw/job1.pdf"]); got != "pdf" {
+		t.Errorf("unexpected raw document %q", got)
+	}
+	if got := string(storage.files["invoices/job1.pdf"]); got != "pdf:"+MergePDF {
+		t.Errorf("unexpected merged document %q", got)
+	}
+	if notified.JobID != "job1" || notified.Object != "invoices/job1.pdf" || notified.Tenant != "acme" {
+		t.Errorf("unexpected notification %+v", notified)
+	}
+}
+
+func TestLoadProfilesRejectsSteps(t *testing.T) {
+	_, err := LoadProfiles(strings.NewReader(`{"report": {"postProcess": [{"action": "notify"}]}}`))
+	if !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("expected ErrInvalidSpec, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package gotenberg
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Preset bundles the rendering settings a script needs to display correctly:
+// text direction, a font stack covering its characters and line breaking rules.
+// Apply it with Request.WithPreset.
+//
+// The font stacks of the built-in presets name fonts shipped with the official
+// Gotenberg image. Images without them render missing characters as boxes.
+// Attach fallback fonts with Preset.WithFonts to avoid depending on the image,
+// either bundled with the application or the subsetted ones of the optional
+// fonts package, see fonts.Fallback.
+type Preset struct {
+	Name string
+
+	// Direction is the CSS text direction, "ltr" or "rtl".
+	Direction string
+	// FontFamily is the font stack applied to the document body, in order of preference.
+	FontFamily []string
+	// EmulatedMediaType is sent as the emulatedMediaType field, if set.
+	EmulatedMediaType string
+	// CSS holds additional rules, e.g. line breaking.
+	CSS string
+
+	fonts     fs.FS
+	fontFiles []string
+}
+
+var (
+	// PresetArabic renders right-to-left Arabic documents.
+	PresetArabic = Preset{
+		Name:              "arabic",
+		Direction:         "rtl",
+		FontFamily:        []string{"Noto Naskh Arabic", "Amiri", "DejaVu Sans"},
+		EmulatedMediaType: MediaTypePrint,
+	}
+
+	// PresetPersian renders right-to-left Persian documents.
+	PresetPersian = Preset{
+		Name:              "persian",
+		Direction:         "rtl",
+		FontFamily:        []string{"Vazirmatn", "Noto Naskh Arabic", "DejaVu Sans"},
+		EmulatedMediaType: MediaTypePrint,
+	}
+
+	// PresetHebrew renders right-to-left Hebrew documents.
+	PresetHebrew = Preset{
+		Name:              "hebrew",
+		Direction:         "rtl",
+		FontFamily:        []string{"Noto Sans Hebrew", "David CLM", "DejaVu Sans"},
+		EmulatedMediaType: MediaTypePrint,
+	}
+
+	// PresetChineseSimplified renders Simplified Chinese documents.
+	PresetChineseSimplified = Preset{
+		Name:              "chinese-simplified",
+		Direction:         "ltr",
+		FontFamily:        []string{"Noto Sans CJK SC", "WenQuanYi Zen Hei", "AR PL UMing CN"},
+		EmulatedMediaType: MediaTypePrint,
+		CSS:               "body { line-break: strict; word-break: normal; }",
+	}
+
+	// PresetChineseTraditional renders Traditional Chinese documents.
+	PresetChineseTraditional = Preset{
+		Name:              "chinese-traditional",
+		Direction:         "ltr",
+		FontFamily:        []string{"Noto Sans CJK TC", "AR PL UMing TW", "WenQuanYi Zen Hei"},
+		EmulatedMediaType: MediaTypePrint,
+		CSS:               "body { line-break: strict; word-break: normal; }",
+	}
+
+	// PresetJapanese renders Japanese documents.
+	PresetJapanese = Preset{
+		Name:              "japanese",
+		Direction:         "ltr",
+		FontFamily:        []string{"Noto Sans CJK JP", "IPAGothic", "IPAPGothic"},
+		EmulatedMediaType: MediaTypePrint,
+		CSS:               "body { line-break: strict; word-break: normal; }",
+	}
+
+	// PresetKorean renders Korean documents, breaking lines between words.
+	PresetKorean = Preset{
+		Name:              "korean",
+		Direction:         "ltr",
+		FontFamily:        []string{"Noto Sans CJK KR", "UnDotum", "Baekmuk Gulim"},
+		EmulatedMediaType: MediaTypePrint,
+		CSS:               "body { word-break: keep-all; }",
+	}
+)
+
+// presets are the built-in presets, by name.
+var presets = map[string]Preset{}
+
+func init() {
+	for _, p := range []Preset{
+		PresetArabic, PresetPersian, PresetHebrew,
+		PresetChineseSimplified, PresetChineseTraditional, PresetJapanese, PresetKorean,
+	} {
+		presets[p.Name] = p
+	}
+}
+
+// LookupPreset returns the built-in preset with the given name, e.g. "arabic" or "japanese".
+func LookupPreset(name string) (Preset, bool) {
+	p, ok := presets[strings.ToLower(name)]
+	return p, ok
+}
+
+// WithFonts returns a copy of p attaching the font files names from fsys, typically
+// an embed.FS bundled with the application. Their families, named after the files
+// as in Request.WithFont, take precedence over FontFamily.
+func (p Preset) WithFonts(fsys fs.FS, names ...string) Preset {
+	p.fonts = fsys
+	p.fontFiles = append(p.fontFiles[:len(p.fontFiles):len(p.fontFiles)], names...)
+	return p
+}
+
+// CSSRules returns the stylesheet applying the direction, fonts and rules of p.
+func (p Preset) CSSRules() string {
+	var b strings.Builder
+	if p.Direction != "" {
+		fmt.Fprintf(&b, "html { direction: %s; }\n", p.Direction)
+	}
+
+	var families []string
+	for _, name := range p.fontFiles {
+		name = path.Base(name)
+		families = append(families, fmt.Sprintf("%q", strings.TrimSuffix(name, path.Ext(name))))
+	}
+	for _, family := range p.FontFamily {
+		families = append(families, fmt.Sprintf("%q", family))
+	}
+	if len(families) > 0 {
+		fmt.Fprintf(&b, "body { font-family: %s, sans-serif; }\n", strings.Join(families, ", "))
+	}
+
+	if p.CSS != "" {
+		b.WriteString(p.CSS)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// WithPreset applies p to the request: its rules are appended to index.html,
+// its fallback fonts attached and its emulated media type set.
+// Only HTML based routes read the stylesheet.
+func (r *Request) WithPreset(p Preset) *Request {
+	for _, name := range p.fontFiles {
+		r.WithFontFS(p.fonts, name)
+	}
+	if p.EmulatedMediaType != "" {
+		r.EmulatedMediaType(p.EmulatedMediaType)
+	}
+	r.appendHTML("<style>\n" + p.CSSRules() + "</style>")
+	return r
+}
@@ -0,0 +1,57 @@
+package gotenberg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithPreset(t *testing.T) {
+	fonts := fstest.MapFS{"fonts/Amiri-Regular.ttf": {Data: []byte("font-data")}}
+
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithPreset(PresetArabic.WithFonts(fonts, "fonts/Amiri-Regular.ttf")).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.files["Amiri-Regular.ttf"] != "font-data" {
+		t.Errorf("fallback font not attached: %v", capture.files)
+	}
+	if got := capture.value(FieldEmulatedMediaType); got != MediaTypePrint {
+		t.Errorf("unexpected emulated media type %q", got)
+	}
+	index := capture.files[FileIndexHTML]
+	for _, want := range []string{
+		"html { direction: rtl; }",
+		`body { font-family: "Amiri-Regular", "Noto Naskh Arabic", "Amiri", "DejaVu Sans", sans-serif; }`,
+		`@font-face { font-family: "Amiri-Regular"`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %q: %s", want, index)
+		}
+	}
+}
+
+func TestPresetWithFontsCopies(t *testing.T) {
+	fonts := fstest.MapFS{}
+	base := PresetJapanese.WithFonts(fonts, "a.otf")
+	_ = base.WithFonts(fonts, "b.otf")
+	_ = base.WithFonts(fonts, "c.otf")
+	if len(base.fontFiles) != 1 || len(PresetJapanese.fontFiles) != 0 {
+		t.Errorf("WithFonts modified its receiver: %v %v", base.fontFiles, PresetJapanese.fontFiles)
+	}
+}
+
+func TestLookupPreset(t *testing.T) {
+	p, ok := LookupPreset("Korean")
+	if !ok || p.Name != "korean" || !strings.Contains(p.CSSRules(), "word-break: keep-all") {
+		t.Errorf("unexpected preset %+v", p)
+	}
+	if _, ok := LookupPreset("klingon"); ok {
+		t.Error("unexpected preset for unknown name")
+	}
+}
@@ -0,0 +1,121 @@
+package gotenberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+)
+
+// Profile is a named set of conversion defaults, e.g. for invoices, contracts
+// or reports, defined in configuration and referenced by ConversionSpec.Profile.
+// Specs override the defaults of their profile selectively: fields, headers
+// and metadata entries of the spec replace those of the profile by key, the
+// route and webhook of the spec replace those of the profile if set.
+type Profile struct {
+	// Route is used by specs without a route.
+	Route string `json:"route,omitempty"`
+
+	// Fields are default form fields, e.g. page size and margins.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Headers are default request headers, e.g. HeaderOutputFilename.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// PDFA is the PDF/A format of the output, e.g. PDFA3b.
+	PDFA string `json:"pdfa,omitempty"`
+	// Metadata are default PDF metadata entries, see Request.Metadata.
+	Metadata map[string]any `json:"metadata,omitempty"`
+
+	Webhook *WebhookSpec `json:"webhook,omitempty"`
+
+	// Inputs limit the inputs of the specs of the profile.
+	Inputs *InputLimits `json:"inputs,omitempty"`
+
+	// PostProcess are the steps a Pipeline executes once the webhook result arrives.
+	PostProcess []PostStep `json:"postProcess,omitempty"`
+}
+
+// Profiles are profiles by name.
+type Profiles map[string]Profile
+
+// LoadProfiles decodes profiles from a JSON configuration object keyed by profile name:
+//
+//	{
+//	  "invoice": {
+//	    "route": "/forms/chromium/convert/html",
+//	    "fields": {"paperWidth": "8.27", "paperHeight": "11.7"},
+//	    "pdfa": "PDF/A-3b",
+//	    "metadata": {"Author": "Billing"},
+//	    "webhook": {"url": "https://app/results", "errorUrl": "https://app/errors"},
+//	    "inputs": {"maxFiles": 20, "maxSize": 10485760, "noExternalReferences": true},
+//	    "postProcess": [
+//	      {"action": "merge", "cover": "covers/invoice.pdf"},
+//	      {"action": "store", "key": "invoices/{date}/{job}.pdf"},
+//	      {"action": "notify", "url": "https://app/invoices/ready"}
+//	    ]
+//	  }
+//	}
+func LoadProfiles(r io.Reader) (Profiles, error) {
+	var profiles Profiles
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("gotenberg: decode profiles: %w", err)
+	}
+	for name, p := range profiles {
+		switch p.PDFA {
+		case "", PDFA1b, PDFA2b, PDFA3b:
+		default:
+			return nil, fmt.Errorf("%w: profile %s: %q", ErrUnsupportedPDFA, name, p.PDFA)
+		}
+		for i, step := range p.PostProcess {
+			if err := step.validate(); err != nil {
+				return nil, fmt.Errorf("profile %s step %d: %w", name, i, err)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// WithProfiles sets the profiles conversion specs can reference.
+func WithProfiles(profiles Profiles) ClientOption {
+	return func(c *Client) {
+		c.profiles = profiles
+	}
+}
+
+// ResolveSpec returns spec with the defaults of its profile applied.
+// Specs without a profile are returned unchanged.
+func (c *Client) ResolveSpec(spec ConversionSpec) (ConversionSpec, error) {
+	if spec.Profile == "" {
+		return spec, nil
+	}
+	p, ok := c.profiles[spec.Profile]
+	if !ok {
+		return spec, fmt.Errorf("%w: unknown profile %q", ErrInvalidSpec, spec.Profile)
+	}
+
+	if spec.Route == "" {
+		spec.Route = p.Route
+	}
+	spec.Fields = mergeDefaults(p.Fields, spec.Fields)
+	if _, ok := spec.Fields[FieldPDFA]; !ok && p.PDFA != "" {
+		spec.Fields = mergeDefaults(spec.Fields, map[string]string{FieldPDFA: p.PDFA})
+	}
+	spec.Headers = mergeDefaults(p.Headers, spec.Headers)
+	spec.Metadata = mergeDefaults(p.Metadata, spec.Metadata)
+	if spec.Webhook == nil && p.Webhook != nil {
+		webhook := *p.Webhook
+		spec.Webhook = &webhook
+	}
+	return spec, nil
+}
+
+// mergeDefaults returns a copy of defaults with values added, replacing defaults by key.
+func mergeDefaults[V any](defaults, values map[string]V) map[string]V {
+	if len(defaults) == 0 && len(values) == 0 {
+		return values
+	}
+	merged := make(map[string]V, len(defaults)+len(values))
+	maps.Copy(merged, defaults)
+	maps.Copy(merged, values)
+	return merged
+}
@@ -0,0 +1,68 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testProfiles = `{
+	"invoice": {
+		"route": "/forms/chromium/convert/html",
+		"fields": {"paperWidth": "8.27", "marginTop": "1"},
+		"headers": {"Gotenberg-Output-Filename": "invoice"},
+		"pdfa": "PDF/A-3b",
+		"metadata": {"Author": "Billing", "Subject": "Invoice"},
+		"webhook": {"url": "http://hook/ok", "errorUrl": "http://hook/err"}
+	}
+}`
+
+func TestProfiles(t *testing.T) {
+	profiles, err := LoadProfiles(strings.NewReader(testProfiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, capture := newCaptureClient(t, WithProfiles(profiles))
+
+	spec := ConversionSpec{
+		Profile:  "invoice",
+		Fields:   map[string]string{FieldMarginTop: "0.5"},
+		Metadata: map[string]any{"Subject": "Invoice 42"},
+		Files:    []FileRef{{Filename: FileIndexHTML, Data: []byte("<html></html>")}},
+	}
+	if _, err := c.FromSpec(context.Background(), spec).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.path != ConvertHTML {
+		t.Errorf("unexpected route %s", capture.path)
+	}
+	if capture.value(FieldPaperWidth) != "8.27" || capture.value(FieldMarginTop) != "0.5" || capture.value(FieldPDFA) != PDFA3b {
+		t.Errorf("unexpected fields: paperWidth %q, marginTop %q, pdfa %q",
+			capture.value(FieldPaperWidth), capture.value(FieldMarginTop), capture.value(FieldPDFA))
+	}
+	if got := capture.value(FieldMetadata); got != `{"Author":"Billing","Subject":"Invoice 42"}` {
+		t.Errorf("unexpected metadata %s", got)
+	}
+	if capture.header.Get(HeaderOutputFilename) != "invoice" || capture.header.Get(HeaderWebhookURL) != "http://hook/ok" {
+		t.Errorf("unexpected headers %v", capture.header)
+	}
+	if profiles["invoice"].Fields[FieldMarginTop] != "1" {
+		t.Error("profile defaults were modified")
+	}
+}
+
+func TestUnknownProfile(t *testing.T) {
+	c := newTestClient(t)
+	spec := ConversionSpec{Profile: "contract", Route: ConvertHTML}
+	if _, err := c.FromSpec(context.Background(), spec).Send(); !errors.Is(err, ErrInvalidSpec) {
+		t.Errorf("expected ErrInvalidSpec, got %v", err)
+	}
+}
+
+func TestLoadProfilesRejectsPDFA(t *testing.T) {
+	if _, err := LoadProfiles(strings.NewReader(`{"report": {"pdfa": "PDF/A-4"}}`)); !errors.Is(err, ErrUnsupportedPDFA) {
+		t.Errorf("expected ErrUnsupportedPDFA, got %v", err)
+	}
+}
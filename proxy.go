@@ -0,0 +1,361 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderProxyCache reports whether Proxy served a conversion from its cache, "HIT" or "MISS".
+const HeaderProxyCache = "X-Gotenberg-Cache"
+
+// HeaderProxyStored is the trailer of the responses of Proxy with WithProxyTee,
+// the key the document was stored as, empty when storing it failed.
+const HeaderProxyStored = "X-Gotenberg-Stored"
+
+// hopHeaders are not forwarded by Proxy.
+var hopHeaders = []string{"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade"}
+
+// Proxy is a reverse proxy in front of Gotenberg, exposing its routes through
+// this application, e.g. to add caching. Requests are forwarded to the base
+// URL of the client.
+type Proxy struct {
+	client *Client
+
+	// cache stores successful conversions by request hash, see WithProxyCache
+	cache       Storage
+	cacheTTL    time.Duration
+	cachePrefix string
+	now         func() time.Time
+
+	// tee stores the documents served, see WithProxyTee
+	tee    Storage
+	teeKey func(r *http.Request) string
+
+	// inline serves documents to the browser viewer, see WithProxyInline
+	inline       bool
+	inlineMaxAge time.Duration
+}
+
+// ProxyOption configures optional Proxy features.
+type ProxyOption func(*Proxy)
+
+// WithProxyCache caches successful conversions in storage under prefix, keyed by
+// a hash of the route, the Gotenberg headers and the form fields and files,
+// and serves repeated conversions from the cache for ttl (forever if zero).
+// Webhook conversions are never cached.
+func WithProxyCache(storage Storage, prefix string, ttl time.Duration) ProxyOption {
+	return func(p *Proxy) {
+		p.cache = storage
+		p.cachePrefix = prefix
+		p.cacheTTL = ttl
+	}
+}
+
+// WithProxyTee stores a copy of the documents of successful conversions in
+// storage, under the key returned by key, while they are streamed to the
+// caller, for "download now and keep a copy" flows without converting twice:
+//
+//	proxy := gotenberg.NewProxy(client, gotenberg.WithProxyTee(storage, func(r *http.Request) string {
+//		return "documents/" + r.Header.Get("X-Document-ID") + ".pdf"
+//	}))
+//
+// Requests for which key returns "" are not stored. The HeaderProxyStored
+// trailer of the response reports the stored key once the upload finished; a
+// failing upload does not interrupt the response. A caller going away once its
+// request was uploaded does not cancel the conversion nor the upload, so the
+// copy is still stored.
+func WithProxyTee(storage Storage, key func(r *http.Request) string) ProxyOption {
+	return func(p *Proxy) {
+		p.tee = storage
+		p.teeKey = key
+	}
+}
+
+// WithProxyInline makes the proxy serve the PDFs and images of conversions
+// with Content-Disposition inline, so they open in the browser viewer instead
+// of being downloaded, cacheable by the browser for maxAge. Zero keeps them
+// from being reused without revalidation.
+func WithProxyInline(maxAge time.Duration) ProxyOption {
+	return func(p *Proxy) {
+		p.inline = true
+		p.inlineMaxAge = maxAge
+	}
+}
+
+// NewProxy creates a Proxy forwarding requests to the Gotenberg of client.
+func NewProxy(client *Client, opts ...ProxyOption) *Proxy {
+	p := &Proxy{
+		client: client,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var teeKey string
+	if p.tee != nil && cacheableConversion(r) {
+		teeKey = p.teeKey(r)
+	}
+	if teeKey != "" {
+		// keep converting and storing the copy for a caller going away
+		r = r.WithContext(context.WithoutCancel(r.Context()))
+	}
+	if p.cache == nil || !cacheableConversion(r) {
+		p.forward(w, r, r.Body, r.ContentLength, teeKey)
+		return
+	}
+
+	body, err := p.client.spoolBody(r.Body, DefaultWebhookMemoryThreshold)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadRequest, "Failed to read request", err)
+		return
+	}
+	defer body.Close()
+
+	key, err := conversionHash(r, body)
+	if _, serr := body.Seek(0, io.SeekStart); serr != nil {
+		WriteError(w, r, serr)
+		return
+	}
+	if err != nil {
+		// Not a form Gotenberg accepts, let Gotenberg answer it
+		p.forward(w, r, body, body.size, teeKey)
+		return
+	}
+	object := path.Join(p.cachePrefix, key)
+	if p.serveCached(w, r, object, teeKey) {
+		return
+	}
+
+	resp, err := p.roundTrip(r, body, body.size)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to forward request", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		writeProxyResponse(w, resp, resp.Body)
+		return
+	}
+
+	result, err := p.client.spoolBody(resp.Body, DefaultWebhookMemoryThreshold)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to read conversion", err)
+		return
+	}
+	defer result.Close()
+	// A failing cache must not fail the conversion
+	_, _ = p.cache.UploadFile(r.Context(), object, result, result.size, resp.Header.Get("Content-Type"))
+	if _, err := result.Seek(0, io.SeekStart); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	resp.Header.Set(HeaderProxyCache, "MISS")
+	p.writeResponse(w, r, resp, result, teeKey)
+}
+
+// forward passes r with body to Gotenberg and streams the response back,
+// storing it as teeKey unless empty.
+func (p *Proxy) forward(w http.ResponseWriter, r *http.Request, body io.Reader, size int64, teeKey string) {
+	resp, err := p.roundTrip(r, body, size)
+	if err != nil {
+		writeErrorCause(w, r, http.StatusBadGateway, "Failed to forward request", err)
+		return
+	}
+	defer resp.Body.Close()
+	p.writeResponse(w, r, resp, resp.Body, teeKey)
+}
+
+// writeResponse writes resp with body, storing successful documents as
+// teeKey unless empty.
+func (p *Proxy) writeResponse(w http.ResponseWriter, r *http.Request, resp *http.Response, body io.Reader, teeKey string) {
+	if p.inline && resp.StatusCode == http.StatusOK {
+		setInline(resp.Header, dispositionFilename(resp.Header.Get("Content-Disposition")), p.inlineMaxAge)
+	}
+	if teeKey == "" || resp.StatusCode != http.StatusOK {
+		writeProxyResponse(w, resp, body)
+		return
+	}
+	writeProxyHeader(w, resp)
+	// Trailers need a chunked response
+	w.Header().Del("Content-Length")
+	w.Header().Set("Trailer", HeaderProxyStored)
+	w.WriteHeader(resp.StatusCode)
+	p.teeBody(w, r, teeKey, body, resp.ContentLength, resp.Header.Get("Content-Type"))
+}
+
+// teeBody writes body to w while uploading it as key, and reports the key in
+// the HeaderProxyStored trailer once stored.
+func (p *Proxy) teeBody(w http.ResponseWriter, r *http.Request, key string, body io.Reader, size int64, contentType string) {
+	pr, pw := io.Pipe()
+	stored := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = p.client.panicked(r.Header.Get(HeaderGotenbergTrace), rec)
+			}
+			// Unblock the writes of an upload that stopped early
+			pr.CloseWithError(err)
+			stored <- err
+		}()
+		_, err = p.tee.UploadFile(r.Context(), key, pr, size, contentType)
+	}()
+	_, err := io.Copy(io.MultiWriter(&lenientWriter{w: w}, &lenientWriter{w: pw}), body)
+	pw.CloseWithError(err)
+	if err := <-stored; err == nil {
+		w.Header().Set(HeaderProxyStored, key)
+	}
+}
+
+// lenientWriter discards the writes after a failed one, so a failing
+// destination does not stop the others of an io.MultiWriter.
+type lenientWriter struct {
+	w      io.Writer
+	failed bool
+}
+
+func (l *lenientWriter) Write(p []byte) (int, error) {
+	if !l.failed {
+		if _, err := l.w.Write(p); err != nil {
+			l.failed = true
+		}
+	}
+	return len(p), nil
+}
+
+// roundTrip sends r with body to Gotenberg.
+func (p *Proxy) roundTrip(r *http.Request, body io.Reader, size int64) (*http.Response, error) {
+	target := p.client.baseURL + r.URL.Path
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	out, err := http.NewRequestWithContext(r.Context(), r.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	out.Header = r.Header.Clone()
+	for _, h := range hopHeaders {
+		out.Header.Del(h)
+	}
+	out.ContentLength = size
+	return p.client.httpClient.Do(out)
+}
+
+// serveCached writes the cached conversion object, if present and fresh.
+func (p *Proxy) serveCached(w http.ResponseWriter, r *http.Request, object, teeKey string) bool {
+	info, err := p.cache.GetFileInfo(r.Context(), object)
+	if err != nil || (p.cacheTTL > 0 && p.now().Sub(info.LastModified) > p.cacheTTL) {
+		return false
+	}
+	content, err := p.cache.DownloadFile(r.Context(), object)
+	if err != nil {
+		return false
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	if name := r.Header.Get(HeaderOutputFilename); name != "" {
+		ext := resultExt(&WebhookResult{ContentType: info.ContentType})
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ext}))
+	}
+	w.Header().Set(HeaderProxyCache, "HIT")
+	if p.inline {
+		setInline(w.Header(), dispositionFilename(w.Header().Get("Content-Disposition")), p.inlineMaxAge)
+	}
+	if teeKey != "" {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Trailer", HeaderProxyStored)
+		w.WriteHeader(http.StatusOK)
+		p.teeBody(w, r, teeKey, content, info.Size, info.ContentType)
+		return true
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, content)
+	return true
+}
+
+func writeProxyResponse(w http.ResponseWriter, resp *http.Response, body io.Reader) {
+	writeProxyHeader(w, resp)
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, body)
+}
+
+// writeProxyHeader copies the end-to-end headers of resp to w.
+func writeProxyHeader(w http.ResponseWriter, resp *http.Response) {
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	for _, h := range hopHeaders {
+		w.Header().Del(h)
+	}
+}
+
+// cacheableConversion reports whether r is a synchronous conversion.
+func cacheableConversion(r *http.Request) bool {
+	return r.Method == http.MethodPost &&
+		strings.HasPrefix(r.URL.Path, "/forms/") &&
+		r.Header.Get(HeaderWebhookURL) == ""
+}
+
+// conversionHash hashes the route, the Gotenberg headers and the form parts of r,
+// independently of the multipart boundary and of the order of the parts.
+func conversionHash(r *http.Request, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", err
+	}
+	if mediaType != "multipart/form-data" {
+		return "", errors.New("gotenberg: not a multipart form")
+	}
+
+	var entries []string
+	for key := range r.Header {
+		if strings.HasPrefix(key, "Gotenberg-") && key != HeaderGotenbergTrace {
+			entries = append(entries, fmt.Sprintf("header\x00%s\x00%s", key, strings.Join(r.Header.Values(key), "\x00")))
+		}
+	}
+
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(h, part); err != nil {
+			return "", err
+		}
+		entries = append(entries, fmt.Sprintf("part\x00%s\x00%s\x00%x", part.FormName(), part.FileName(), h.Sum(nil)))
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	io.WriteString(h, r.URL.Path)
+	for _, e := range entries {
+		io.WriteString(h, "\n"+e)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,257 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func newGotenbergServer(t *testing.T, calls *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set(HeaderGotenbergTrace, "trace")
+		w.Write([]byte("pdf:" + r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func multipartForm(t *testing.T, html string) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField(FieldPaperWidth, "8.27")
+	fw, _ := mw.CreateFormFile(FieldFiles, FileIndexHTML)
+	fw.Write([]byte(html))
+	mw.Close()
+	return &body, mw.FormDataContentType()
+}
+
+func TestProxyCache(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+	proxy := NewProxy(c, WithProxyCache(storage, "cache", time.Hour))
+
+	convert := func(html string) *httptest.ResponseRecorder {
+		body, contentType := multipartForm(t, html)
+		req := httptest.NewRequest(http.MethodPost, ConvertHTML, body)
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set(HeaderOutputFilename, "report")
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := convert("<html></html>")
+	second := convert("<html></html>")
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d, %d", first.Code, second.Code)
+	}
+	if first.Header().Get(HeaderProxyCache) != "MISS" || second.Header().Get(HeaderProxyCache) != "HIT" {
+		t.Errorf("unexpected cache headers %q, %q", first.Header().Get(HeaderProxyCache), second.Header().Get(HeaderProxyCache))
+	}
+	if second.Body.String() != "pdf:"+ConvertHTML || second.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("unexpected cached response %q", second.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 Gotenberg call, got %d", calls)
+	}
+
+	convert("<html>changed</html>")
+	if calls != 2 {
+		t.Errorf("expected a changed form to miss the cache, got %d calls", calls)
+	}
+
+	proxy.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if rec := convert("<html></html>"); rec.Header().Get(HeaderProxyCache) != "MISS" {
+		t.Error("expected expired entry to miss the cache")
+	}
+}
+
+func TestProxyForwardsWithoutCache(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewProxy(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "pdf:/health" || rec.Header().Get(HeaderGotenbergTrace) != "trace" {
+		t.Errorf("unexpected response %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestProxyTee(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+	cache := newMemoryStorage()
+	for name, proxy := range map[string]*Proxy{
+		"streamed": NewProxy(c, WithProxyTee(storage, func(r *http.Request) string { return r.Header.Get("X-Document-ID") })),
+		"cached":   NewProxy(c, WithProxyCache(cache, "cache", 0), WithProxyTee(storage, func(r *http.Request) string { return r.Header.Get("X-Document-ID") })),
+	} {
+		srv := httptest.NewServer(proxy)
+		defer srv.Close()
+		for _, id := range []string{name + "-1.pdf", name + "-2.pdf", ""} {
+			body, contentType := multipartForm(t, "<html></html>")
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+ConvertHTML, body)
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("X-Document-ID", id)
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK || string(got) != "pdf:"+ConvertHTML {
+				t.Fatalf("%s: unexpected response %d %q", name, resp.StatusCode, got)
+			}
+			if stored := resp.Trailer.Get(HeaderProxyStored); stored != id {
+				t.Errorf("%s: unexpected stored trailer %q", name, stored)
+			}
+			if id != "" && string(storage.files[id]) != "pdf:"+ConvertHTML {
+				t.Errorf("%s: unexpected copy %q", name, storage.files[id])
+			}
+		}
+	}
+	if len(storage.files) != 4 {
+		t.Errorf("expected 4 copies, got %d", len(storage.files))
+	}
+}
+
+func TestProxyTeeStorageFailure(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &flakyStorage{memoryStorage: *newMemoryStorage(), failures: 1, err: io.ErrUnexpectedEOF}
+	srv := httptest.NewServer(NewProxy(c, WithProxyTee(storage, func(r *http.Request) string { return "doc.pdf" })))
+	defer srv.Close()
+
+	body, contentType := multipartForm(t, "<html></html>")
+	resp, err := srv.Client().Post(srv.URL+ConvertHTML, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(got) != "pdf:"+ConvertHTML || resp.Trailer.Get(HeaderProxyStored) != "" {
+		t.Errorf("unexpected response %q with trailer %q", got, resp.Trailer.Get(HeaderProxyStored))
+	}
+}
+
+// uploadedStorage reports the outcome of every upload on done.
+type uploadedStorage struct {
+	*memoryStorage
+	done chan error
+}
+
+func (s *uploadedStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	info, err := s.memoryStorage.UploadFile(ctx, objectName, reader, size, contentType)
+	s.done <- err
+	return info, err
+}
+
+func TestProxyTeeSurvivesCallerDisconnect(t *testing.T) {
+	release := make(chan struct{})
+	// larger than the buffer of the proxy's response writer
+	part1 := strings.Repeat("a", 64<<10)
+	gotenberg := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/pdf")
+		io.WriteString(w, part1)
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, "part2")
+	}))
+	defer gotenberg.Close()
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := &uploadedStorage{memoryStorage: newMemoryStorage(), done: make(chan error, 1)}
+	srv := httptest.NewServer(NewProxy(c, WithProxyTee(storage, func(r *http.Request) string { return "doc.pdf" })))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	body, contentType := multipartForm(t, "<html></html>")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+ConvertHTML, body)
+	req.Header.Set("Content-Type", contentType)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadFull(resp.Body, make([]byte, 1024))
+	// the caller goes away before the conversion is complete
+	cancel()
+	resp.Body.Close()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-storage.done:
+		if err != nil || string(storage.files["doc.pdf"]) != part1+"part2" {
+			t.Errorf("copy of %d bytes stored with %v", len(storage.files["doc.pdf"]), err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("copy not stored")
+	}
+}
+
+// panickingStorage panics on upload.
+type panickingStorage struct {
+	memoryStorage
+}
+
+func (s *panickingStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	panic("storage bug")
+}
+
+func TestProxyTeeRecoversPanics(t *testing.T) {
+	var calls int32
+	gotenberg := newGotenbergServer(t, &calls)
+	recovered := make(chan any, 1)
+	c, err := NewClient(gotenberg.Client(), gotenberg.URL, WithPanicHandler(func(trace string, p any, stack []byte) { recovered <- p }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(NewProxy(c, WithProxyTee(&panickingStorage{}, func(r *http.Request) string { return "doc.pdf" })))
+	defer srv.Close()
+
+	body, contentType := multipartForm(t, "<html></html>")
+	resp, err := srv.Client().Post(srv.URL+ConvertHTML, contentType, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(got) != "pdf:"+ConvertHTML || resp.Trailer.Get(HeaderProxyStored) != "" {
+		t.Errorf("unexpected response %q with trailer %q", got, resp.Trailer.Get(HeaderProxyStored))
+	}
+	if p := <-recovered; p != "storage bug" {
+		t.Errorf("unexpected panic %v", p)
+	}
+}
@@ -0,0 +1,144 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		content string
+		level   Level
+		version int
+	}{
+		{"hello", Medium, 1},
+		{strings.Repeat("a", 14), Medium, 1},
+		{strings.Repeat("a", 15), Medium, 2},
+		{strings.Repeat("a", 2953), Low, 40},
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.content, tt.level)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes) failed: %v", len(tt.content), err)
+		}
+		if c.Version != tt.version || c.Size != tt.version*4+17 {
+			t.Errorf("Encode(%d bytes): version %d size %d, want version %d", len(tt.content), c.Version, c.Size, tt.version)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("a", 2954), Low); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestFinderPatterns(t *testing.T) {
+	c, err := Encode("https://example.com", High)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The outer ring and the 3x3 center of each finder are dark, the ring between is light
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		x, y := corner[0], corner[1]
+		if !c.Dark(x, y) || !c.Dark(x+6, y+6) || c.Dark(x+1, y+1) || !c.Dark(x+3, y+3) {
+			t.Errorf("bad finder pattern at %v", corner)
+		}
+	}
+	if !c.Dark(8, c.Size-8) {
+		t.Error("dark module is light")
+	}
+}
+
+// TestGoldenSymbols compares the symbols with those of a reference encoder
+// (github.com/skip2/go-qrcode), in testdata as rows of '#' (dark) and '.'.
+// The specification leaves the mask evaluation open to interpretation, so
+// the symbols are compared under the mask of the reference.
+func TestGoldenSymbols(t *testing.T) {
+	fox := strings.Repeat("the quick brown fox jumps over the lazy dog; ", 4)
+	tests := []struct {
+		golden  string
+		content string
+		level   Level
+	}{
+		{"hello-L", "hello, world", Low},
+		{"hello-M", "hello, world", Medium},
+		{"hello-Q", "hello, world", Quartile},
+		{"hello-H", "hello, world", High},
+		{"url-M", "https://example.com/invoices/42?token=abc", Medium},
+		{"url-H", "https://example.com/invoices/42?token=abc", High},
+		{"fox4-Q", fox, Quartile},                   // version 12, 16 bit length
+		{"fox16-M", strings.Repeat(fox, 4), Medium}, // version 22
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", tt.golden+".txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strings.Fields(string(data))
+		c, err := Encode(tt.content, tt.level)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.golden, err)
+		}
+		if c.Size != len(want) {
+			t.Errorf("%s: size %d, want %d", tt.golden, c.Size, len(want))
+			continue
+		}
+
+		c.applyMask(c.mask)
+		matched := false
+		for mask := 0; mask < 8 && !matched; mask++ {
+			c.applyMask(mask)
+			c.drawFormatBits(mask)
+			matched = symbolRows(c) == strings.Join(want, "\n")
+			c.applyMask(mask)
+		}
+		if !matched {
+			t.Errorf("%s: symbol differs from the reference under every mask", tt.golden)
+		}
+	}
+}
+
+// symbolRows draws c as the rows of the golden files.
+func symbolRows(c *Code) string {
+	rows := make([]string, c.Size)
+	for y := range rows {
+		row := make([]byte, c.Size)
+		for x := range row {
+			row[x] = '.'
+			if c.Dark(x, y) {
+				row[x] = '#'
+			}
+		}
+		rows[y] = string(row)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestReedSolomon(t *testing.T) {
+	// Version 1-M "01234567" in numeric mode, from the specification annex
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	want := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("unexpected ECC % X", got)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	b, err := PNG("hello", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Dx(); got != (21+8)*2 {
+		t.Errorf("unexpected width %d", got)
+	}
+}
@@ -0,0 +1,143 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by Request.Send when the tenant has exhausted its quota.
+var ErrQuotaExceeded = errors.New("gotenberg: quota exceeded")
+
+// QuotaChecker decides whether a conversion may be sent.
+// CheckQuota is called by Request.Send before contacting Gotenberg;
+// returning an error (typically wrapping ErrQuotaExceeded) aborts the request.
+type QuotaChecker interface {
+	CheckQuota(ctx context.Context, tenant, route string) error
+}
+
+// Usage describes the resources consumed by a single conversion.
+type Usage struct {
+	Time        time.Time
+	Tenant      string
+	Route       string
+	InputBytes  int64
+	OutputBytes int64 // -1 if Gotenberg did not report the response size
+	Failed      bool
+}
+
+// UsageReporter receives the usage of every conversion once Gotenberg has responded.
+type UsageReporter interface {
+	ReportUsage(ctx context.Context, usage Usage)
+}
+
+// WithQuota sets the quota checker consulted before every conversion.
+func WithQuota(checker QuotaChecker) ClientOption {
+	return func(c *Client) {
+		c.quota = checker
+	}
+}
+
+// WithUsageReporter sets the reporter receiving the usage of every conversion.
+func WithUsageReporter(reporter UsageReporter) ClientOption {
+	return func(c *Client) {
+		c.usage = reporter
+	}
+}
+
+// QuotaLimits are the per-tenant limits enforced by MemoryQuota.
+// Zero values disable the corresponding limit.
+type QuotaLimits struct {
+	ConversionsPerHour int
+	TotalBytes         int64
+}
+
+// MemoryQuota is an in-process QuotaChecker and UsageReporter that enforces
+// the same QuotaLimits for every tenant.
+// Every checked conversion counts against the hourly limit, including failed ones.
+// Uploaded and generated bytes count against the total bytes limit.
+type MemoryQuota struct {
+	limits QuotaLimits
+
+	mu      sync.Mutex
+	tenants map[string]*tenantUsage
+	now     func() time.Time
+}
+
+type tenantUsage struct {
+	conversions []time.Time
+	bytes       int64
+}
+
+// NewMemoryQuota creates a MemoryQuota with the given limits.
+func NewMemoryQuota(limits QuotaLimits) *MemoryQuota {
+	return &MemoryQuota{
+		limits:  limits,
+		tenants: make(map[string]*tenantUsage),
+		now:     time.Now,
+	}
+}
+
+// CheckQuota implements QuotaChecker.
+func (q *MemoryQuota) CheckQuota(ctx context.Context, tenant, route string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.tenant(tenant)
+	now := q.now()
+
+	// Drop conversions that left the one hour window
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(u.conversions) && !u.conversions[i].After(cutoff) {
+		i++
+	}
+	u.conversions = u.conversions[i:]
+
+	if q.limits.ConversionsPerHour > 0 && len(u.conversions) >= q.limits.ConversionsPerHour {
+		return ErrQuotaExceeded
+	}
+	if q.limits.TotalBytes > 0 && u.bytes >= q.limits.TotalBytes {
+		return ErrQuotaExceeded
+	}
+
+	u.conversions = append(u.conversions, now)
+	return nil
+}
+
+// ReportUsage implements UsageReporter.
+func (q *MemoryQuota) ReportUsage(ctx context.Context, usage Usage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.tenant(usage.Tenant)
+	u.bytes += usage.InputBytes
+	if usage.OutputBytes > 0 {
+		u.bytes += usage.OutputBytes
+	}
+}
+
+// Usage returns the conversions in the current hour and the total bytes used by tenant.
+func (q *MemoryQuota) Usage(tenant string) (conversions int, bytes int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.tenant(tenant)
+	cutoff := q.now().Add(-time.Hour)
+	for _, t := range u.conversions {
+		if t.After(cutoff) {
+			conversions++
+		}
+	}
+	return conversions, u.bytes
+}
+
+func (q *MemoryQuota) tenant(tenant string) *tenantUsage {
+	u, ok := q.tenants[tenant]
+	if !ok {
+		u = &tenantUsage{}
+		q.tenants[tenant] = u
+	}
+	return u
+}
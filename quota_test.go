@@ -0,0 +1,56 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryQuotaConversionsPerHour(t *testing.T) {
+	quota := NewMemoryQuota(QuotaLimits{ConversionsPerHour: 2})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	quota.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := quota.CheckQuota(ctx, "acme", ConvertHTML); err != nil {
+			t.Fatalf("conversion %d rejected: %v", i, err)
+		}
+	}
+	if err := quota.CheckQuota(ctx, "acme", ConvertHTML); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if err := quota.CheckQuota(ctx, "other", ConvertHTML); err != nil {
+		t.Fatalf("other tenant rejected: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if err := quota.CheckQuota(ctx, "acme", ConvertHTML); err != nil {
+		t.Fatalf("conversion after window rejected: %v", err)
+	}
+}
+
+func TestQuotaOnSend(t *testing.T) {
+	quota := NewMemoryQuota(QuotaLimits{TotalBytes: 10})
+	c, err := NewClient(&http.Client{Transport: &mockRoundTripper{}}, "http://localhost",
+		WithQuota(quota), WithUsageReporter(quota))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	html := "<html><body></body></html>"
+	if _, err := c.ConvertHTML(context.Background(), bytes.NewBufferString(html)).Tenant("acme").Send(); err != nil {
+		t.Fatalf("first Send failed: %v", err)
+	}
+	if _, used := quota.Usage("acme"); used != int64(len(html)) {
+		t.Errorf("expected %d bytes used, got %d", len(html), used)
+	}
+
+	_, err = c.ConvertHTML(context.Background(), bytes.NewBufferString(html)).Tenant("acme").Send()
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// headerEcho is set by echoGotenberg to the request it received.
+const headerEcho = "X-Echo"
+
+// echoGotenberg answers every conversion with a description of the request in
+// headerEcho, so concurrent callers can verify they got their own response.
+func echoGotenberg(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var names []string
+	for _, files := range r.MultipartForm.File {
+		for _, fh := range files {
+			f, _ := fh.Open()
+			data, _ := io.ReadAll(f)
+			f.Close()
+			names = append(names, fh.Filename+"="+string(data))
+		}
+	}
+	w.Header().Set(headerEcho, strings.Join([]string{
+		r.URL.Path,
+		r.Header.Get(HeaderGotenbergTrace),
+		r.Header.Get(HeaderWebhookURL),
+		r.Header.Get(HeaderWebhookExtraHTTPHeaders),
+		r.FormValue(FieldURL),
+		strings.Join(names, ","),
+	}, "|"))
+	w.Header().Set(HeaderGotenbergTrace, r.Header.Get(HeaderGotenbergTrace))
+	if r.Header.Get(HeaderWebhookURL) != "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Write([]byte("%PDF"))
+}
+
+// TestClientConcurrentUse shares one client, with every client-level feature
+// enabled, across goroutines mixing routes and webhook settings. A Client is
+// safe for concurrent use; each goroutine builds its own Request.
+func TestClientConcurrentUse(t *testing.T) {
+	const goroutines = 300
+
+	srv := httptest.NewServer(http.HandlerFunc(echoGotenberg))
+	defer srv.Close()
+
+	sink := &recordingSink{}
+	quota := NewMemoryQuota(QuotaLimits{})
+	traces := NewTraceAllowlist(time.Hour)
+	c, err := NewClient(srv.Client(), srv.URL,
+		WithAuditSink(sink),
+		WithQuota(quota),
+		WithUsageReporter(quota),
+		WithTraceAllowlist(traces),
+		WithProfiles(Profiles{"letter": {Fields: map[string]string{FieldPaperWidth: "8.5"}}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, goroutines)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- convertConcurrently(c, i)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	if len(sink.records) != goroutines {
+		t.Errorf("expected %d audit records, got %d", goroutines, len(sink.records))
+	}
+	if conversions, _ := quota.Usage("tenant"); conversions != goroutines {
+		t.Errorf("expected %d conversions in quota, got %d", goroutines, conversions)
+	}
+	stats := c.Stats()
+	if stats.Conversions != goroutines {
+		t.Errorf("expected %d finished conversions, got %d", goroutines, stats.Conversions)
+	}
+	stats.Conversions, stats.ConversionTimings = 0, Timings{}
+	if stats != (Stats{}) {
+		t.Errorf("expected no active requests or buffers, got %+v", stats)
+	}
+	for i := 0; i < goroutines; i += 2 {
+		if known, _ := traces.KnownTrace(context.Background(), fmt.Sprintf("trace-%d", i)); !known {
+			t.Errorf("webhook trace %d not registered", i)
+		}
+	}
+}
+
+// convertConcurrently sends the i-th conversion of TestClientConcurrentUse
+// and checks that the response answers it.
+func convertConcurrently(c *Client, i int) error {
+	ctx := context.Background()
+	content := fmt.Sprintf("document %d", i)
+	var r *Request
+	var route, url, files string
+	switch i % 5 {
+	case 0:
+		r, route, files = c.ConvertHTML(ctx, strings.NewReader(content)), ConvertHTML, FileIndexHTML+"="+content
+	case 1:
+		url = fmt.Sprintf("https://example.com/%d", i)
+		r, route = c.ConvertURL(ctx, url), ConvertURL
+	case 2:
+		r = c.ConvertOffice(ctx, NamedReader{Name: "doc.docx", Reader: strings.NewReader(content)})
+		route, files = ConvertOffice, "doc.docx="+content
+	case 3:
+		r = c.FromSpec(ctx, ConversionSpec{
+			Route:   ConvertHTML,
+			Profile: "letter",
+			Files:   []FileRef{{Filename: FileIndexHTML, Data: []byte(content)}},
+		})
+		route, files = ConvertHTML, FileIndexHTML+"="+content
+	case 4:
+		r, route, files = c.ScreenshotHTML(ctx, strings.NewReader(content)), ScreenshotHTML, FileIndexHTML+"="+content
+	}
+
+	trace := fmt.Sprintf("trace-%d", i)
+	r.Tenant("tenant").Trace(trace)
+	var webhook, webhookHeaders string
+	if i%2 == 0 {
+		webhook = fmt.Sprintf("http://hooks.local/%d", i)
+		webhookHeaders = fmt.Sprintf(`{"X-Job":"%d"}`, i)
+		r.WebhookURL(webhook, http.MethodPost).
+			WebhookErrorURL(webhook+"/error", http.MethodPost).
+			WebhookHeader("X-Job", fmt.Sprint(i))
+	}
+
+	resp, err := r.Send()
+	if err != nil {
+		return fmt.Errorf("conversion %d: %w", i, err)
+	}
+	defer resp.Body.Close()
+
+	want := strings.Join([]string{route, trace, webhook, webhookHeaders, url, files}, "|")
+	if got := resp.Header.Get(headerEcho); got != want {
+		return fmt.Errorf("conversion %d got the response of %q, want %q", i, got, want)
+	}
+	if resp.GotenbergTrace != trace || resp.NoContent != (webhook != "") {
+		return fmt.Errorf("conversion %d: unexpected response trace %q, no content %v", i, resp.GotenbergTrace, resp.NoContent)
+	}
+	return nil
+}
+
+// TestOutboxConcurrentSubmit submits and completes jobs of one Outbox from
+// many goroutines.
+func TestOutboxConcurrentSubmit(t *testing.T) {
+	const goroutines = 200
+
+	srv := httptest.NewServer(http.HandlerFunc(echoGotenberg))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outbox := NewOutbox(c, NewMemoryJobStore(), testWebhook)
+	results := outbox.ResultHandler(func(ctx context.Context, job Job, result *WebhookResult) error {
+		_, err := io.Copy(io.Discard, result.Body)
+		return err
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job, err := outbox.Submit(context.Background(), testSpec())
+			if err != nil {
+				t.Errorf("Submit failed: %v", err)
+				return
+			}
+			outbox.Pending(context.Background())
+
+			req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("%PDF"))
+			req.Header.Set("Content-Type", "application/pdf")
+			req.Header.Set(HeaderGotenbergTrace, job.ID)
+			rec := httptest.NewRecorder()
+			results.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("callback of %s answered %d: %s", job.ID, rec.Code, rec.Body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if pending, _ := outbox.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("expected no pending jobs, got %d", len(pending))
+	}
+}
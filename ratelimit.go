@@ -0,0 +1,155 @@
+package gotenberg
+
+import (
+	"container/list"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HeaderAPIKey is the request header identifying API clients by default, see RateLimiter.
+const HeaderAPIKey = "X-API-Key"
+
+// maxKeyBuckets bounds the per-key buckets kept, the least recently used
+// bucket is evicted for a new key beyond it.
+const maxKeyBuckets = 10000
+
+// RateLimits configures a RateLimiter. Rates are requests per second, bursts
+// the number of requests allowed at once. A zero rate disables the limit.
+type RateLimits struct {
+	Rate  float64
+	Burst int
+
+	// KeyRate and KeyBurst limit every API key separately.
+	KeyRate  float64
+	KeyBurst int
+}
+
+// RateLimiter is a token bucket rate limiting middleware for the HTTP handlers
+// of this package (MinioAPI, Proxy, Outbox callbacks), limiting all requests
+// and the requests of every API key. Rejected requests are answered with 429.
+type RateLimiter struct {
+	limits RateLimits
+	now    func() time.Time
+
+	// KeyFunc returns the API key of a request, the HeaderAPIKey header if nil.
+	// Requests without a key are only subject to the global limit.
+	KeyFunc func(r *http.Request) string
+
+	mu     sync.Mutex
+	global *tokenBucket
+	keys   map[string]*list.Element // of *keyEntry, in lru
+	lru    *list.List               // most recently used first
+}
+
+// keyEntry is the bucket of an API key in the lru list of a RateLimiter.
+type keyEntry struct {
+	key    string
+	bucket *tokenBucket
+}
+
+// NewRateLimiter creates a RateLimiter enforcing limits.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		limits: limits,
+		now:    time.Now,
+		keys:   make(map[string]*list.Element),
+		lru:    list.New(),
+	}
+}
+
+// Middleware wraps next with the rate limits.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := l.allow(l.key(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeErrorResponse(w, r, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *RateLimiter) key(r *http.Request) string {
+	if l.KeyFunc != nil {
+		return l.KeyFunc(r)
+	}
+	return r.Header.Get(HeaderAPIKey)
+}
+
+// allow takes a token from the key bucket and the global bucket. It returns the
+// time until a token is available when the request is rejected.
+func (l *RateLimiter) allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+
+	var keyBucket *tokenBucket
+	if key != "" && l.limits.KeyRate > 0 {
+		keyBucket = l.keyBucket(key, now)
+		if wait := keyBucket.wait(now); wait > 0 {
+			return wait, false
+		}
+	}
+
+	if l.limits.Rate > 0 {
+		if l.global == nil {
+			l.global = newTokenBucket(l.limits.Rate, l.limits.Burst, now)
+		}
+		if wait := l.global.wait(now); wait > 0 {
+			return wait, false
+		}
+		l.global.tokens--
+	}
+	if keyBucket != nil {
+		keyBucket.tokens--
+	}
+	return 0, true
+}
+
+// keyBucket returns the bucket of key, creating it and evicting the least
+// recently used bucket beyond maxKeyBuckets.
+func (l *RateLimiter) keyBucket(key string, now time.Time) *tokenBucket {
+	if e, ok := l.keys[key]; ok {
+		l.lru.MoveToFront(e)
+		return e.Value.(*keyEntry).bucket
+	}
+	if l.lru.Len() >= maxKeyBuckets {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.keys, oldest.Value.(*keyEntry).key)
+	}
+	b := newTokenBucket(l.limits.KeyRate, l.limits.KeyBurst, now)
+	l.keys[key] = l.lru.PushFront(&keyEntry{key: key, bucket: b})
+	return b
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait refills the bucket and returns the time until a token is available.
+func (b *tokenBucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
@@ -0,0 +1,72 @@
+package gotenberg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{Rate: 10, Burst: 3, KeyRate: 1, KeyBurst: 2})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	call := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/convert", nil)
+		if key != "" {
+			req.Header.Set(HeaderAPIKey, key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// per key burst
+	if call("a").Code != http.StatusOK || call("a").Code != http.StatusOK {
+		t.Fatal("expected key burst to pass")
+	}
+	rec := call("a")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected key limit, got %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	// global burst of 3 is exhausted by the third accepted request
+	if call("b").Code != http.StatusOK {
+		t.Error("expected other key to pass")
+	}
+	if call("").Code != http.StatusTooManyRequests {
+		t.Error("expected global limit")
+	}
+
+	now = now.Add(time.Second)
+	if call("a").Code != http.StatusOK {
+		t.Error("expected tokens to be refilled")
+	}
+}
+
+func TestRateLimiterEvictsLeastRecentlyUsedKeys(t *testing.T) {
+	limiter := NewRateLimiter(RateLimits{KeyRate: 1, KeyBurst: 1})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	if _, ok := limiter.allow("limited"); !ok {
+		t.Fatal("expected the first request to pass")
+	}
+	for i := 0; i < 2*maxKeyBuckets; i++ {
+		if i%100 == 0 {
+			if _, ok := limiter.allow("limited"); ok {
+				t.Fatalf("expected the recently used key to stay limited after %d keys", i)
+			}
+		}
+		limiter.allow(strconv.Itoa(i))
+	}
+	if n := len(limiter.keys); n != maxKeyBuckets || limiter.lru.Len() != n {
+		t.Errorf("expected %d buckets, got %d", maxKeyBuckets, n)
+	}
+	if _, ok := limiter.keys["0"]; ok {
+		t.Error("expected the least recently used key to be evicted")
+	}
+}
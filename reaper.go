@@ -0,0 +1,133 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reaper finds outbox jobs whose result did not arrive in time, e.g. because
+// Gotenberg restarted or the webhook was unreachable, and submits them again
+// or marks them failed.
+type Reaper struct {
+	Outbox *Outbox
+
+	// Timeout is the time after the last submission a pending job is stuck.
+	Timeout time.Duration
+
+	// MaxAttempts is the number of submissions after which a stuck job is
+	// marked failed instead of being submitted again, 3 if zero.
+	MaxAttempts int
+
+	// OnResubmit is called for every stuck job submitted again, with the
+	// submission error if any. Optional.
+	OnResubmit func(job Job, err error)
+
+	// OnFailed is called for every stuck job marked failed. Optional.
+	OnFailed func(job Job)
+}
+
+// Reap handles all stuck jobs once.
+func (r *Reaper) Reap(ctx context.Context) error {
+	pending, err := r.Outbox.Pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := r.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 3
+	}
+	cutoff := r.Outbox.now().Add(-r.Timeout)
+
+	for _, job := range pending {
+		if job.SubmittedAt.After(cutoff) {
+			continue
+		}
+		if err := r.reap(ctx, job, maxAttempts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reap submits a stuck job again or marks it failed. A panic, e.g. of
+// OnResubmit, is reported to the client's PanicHandler and fails the job.
+func (r *Reaper) reap(ctx context.Context, job Job, maxAttempts int) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			perr := r.Outbox.client.panicked(job.ID, p)
+			if err = r.Outbox.finish(ctx, job.ID, JobFailed, perr.Error(), nil); staleJob(err) {
+				err = nil
+			}
+		}
+	}()
+
+	if job.Attempts >= maxAttempts {
+		message := fmt.Sprintf("gotenberg: no result after %d attempts", job.Attempts)
+		attempts := job.Attempts
+		job, err = r.Outbox.store.Update(ctx, job.ID, func(stored Job) (Job, error) {
+			if err := checkAttempt(stored, attempts); err != nil {
+				return stored, err
+			}
+			stored.Status = JobFailed
+			stored.Error = message
+			stored.CompletedAt = r.Outbox.now()
+			return stored, nil
+		})
+		if staleJob(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if r.OnFailed != nil {
+			r.OnFailed(job)
+		}
+		return nil
+	}
+
+	job, err = r.Outbox.send(ctx, job)
+	if staleJob(err) {
+		// finished or submitted again since Pending was read
+		return nil
+	}
+	if r.OnResubmit != nil {
+		r.OnResubmit(job, err)
+	}
+	return nil
+}
+
+// staleJob reports whether err is a job read by Reap having changed since.
+func staleJob(err error) bool {
+	return errors.Is(err, ErrJobFinished) || errors.Is(err, ErrJobChanged)
+}
+
+// Run calls Reap every interval until ctx is done. Reap errors are
+// passed to onError, if not nil, including the panics of Reap, which are
+// also reported to the client's PanicHandler.
+func (r *Reaper) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.reapAll(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// reapAll calls Reap, recovering its panics.
+func (r *Reaper) reapAll(ctx context.Context) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = r.Outbox.client.panicked("", p)
+		}
+	}()
+	return r.Reap(ctx)
+}
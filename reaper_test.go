@@ -0,0 +1,144 @@
+package gotenberg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReaper(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook)
+	now := time.Now()
+	outbox.now = func() time.Time { return now }
+
+	stuck, _ := outbox.Submit(ctx, testSpec())
+	now = now.Add(10 * time.Minute)
+	recent, _ := outbox.Submit(ctx, testSpec())
+
+	var resubmitted, failed []string
+	reaper := &Reaper{
+		Outbox:      outbox,
+		Timeout:     5 * time.Minute,
+		MaxAttempts: 2,
+		OnResubmit:  func(job Job, err error) { resubmitted = append(resubmitted, job.ID) },
+		OnFailed:    func(job Job) { failed = append(failed, job.ID) },
+	}
+
+	if err := reaper.Reap(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(resubmitted) != 1 || resubmitted[0] != stuck.ID || len(failed) != 0 {
+		t.Fatalf("unexpected reap: resubmitted %v, failed %v", resubmitted, failed)
+	}
+	if job, _ := store.Get(ctx, stuck.ID); job.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", job.Attempts)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if err := reaper.Reap(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 1 || failed[0] != stuck.ID {
+		t.Errorf("expected stuck job to fail, got %v", failed)
+	}
+	if job, _ := store.Get(ctx, stuck.ID); job.Status != JobFailed {
+		t.Errorf("expected failed job, got %s", job.Status)
+	}
+	if job, _ := store.Get(ctx, recent.ID); job.Status != JobPending || job.Attempts != 2 {
+		t.Errorf("unexpected recent job %+v", job)
+	}
+}
+
+func TestReaperSkipsChangedJobs(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	srv := newGotenbergServer(t, &calls)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+	completed, _ := outbox.Submit(ctx, testSpec())
+	resubmitted, _ := outbox.Submit(ctx, testSpec())
+	snapshot, _ := outbox.Pending(ctx)
+
+	// between the snapshot and the reap, a callback completes one job and
+	// another instance submits the other again
+	callback := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+	callback.Header.Set(HeaderGotenbergTrace, completed.ID)
+	outbox.ResultHandler(func(context.Context, Job, *WebhookResult) error { return nil }).
+		ServeHTTP(httptest.NewRecorder(), callback)
+	if _, err := outbox.Resubmit(ctx, resubmitted.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	reaper := &Reaper{
+		Outbox:     outbox,
+		OnResubmit: func(job Job, err error) { t.Errorf("job %s submitted again: %v", job.ID, err) },
+	}
+	for _, job := range snapshot {
+		if err := reaper.reap(ctx, job, 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job, _ := store.Get(ctx, completed.ID); job.Status != JobCompleted {
+		t.Errorf("completed job overwritten: %+v", job)
+	}
+	if job, _ := store.Get(ctx, resubmitted.ID); job.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", job.Attempts)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 submissions, got %d", calls)
+	}
+}
+
+func TestReaperConcurrentReaps(t *testing.T) {
+	ctx := context.Background()
+	var calls int32
+	srv := newGotenbergServer(t, &calls)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(c, store, testWebhook)
+	job, _ := outbox.Submit(ctx, testSpec())
+	reaper := &Reaper{Outbox: outbox}
+
+	// several instances reap the same snapshot while the result arrives
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := reaper.reap(ctx, job, 3); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		callback := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+		callback.Header.Set(HeaderGotenbergTrace, job.ID)
+		outbox.ResultHandler(func(context.Context, Job, *WebhookResult) error { return nil }).
+			ServeHTTP(httptest.NewRecorder(), callback)
+	}()
+	wg.Wait()
+
+	saved, _ := store.Get(ctx, job.ID)
+	if saved.Status != JobCompleted || saved.Attempts > 2 {
+		t.Errorf("unexpected job %+v", saved)
+	}
+	if n := atomic.LoadInt32(&calls); n > 2 {
+		t.Errorf("job submitted %d times", n)
+	}
+}
@@ -0,0 +1,185 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"regexp"
+	"time"
+)
+
+// ErrIntegrity is returned by ConvertAndStore with VerifyStored when the
+// stored object differs from the converted document, e.g. after a truncated
+// upload.
+var ErrIntegrity = errors.New("gotenberg: stored object does not match the converted document")
+
+// ErrWebhookMode is returned by the helpers that need the converted document
+// for requests in webhook mode, whose document is delivered to the webhook.
+var ErrWebhookMode = errors.New("gotenberg: webhook conversions deliver no document")
+
+// Result describes a finished conversion, in the same shape for all the
+// high-level helpers such as ConvertAndStore.
+type Result struct {
+	// Trace is the Gotenberg trace of the conversion.
+	Trace string `json:"trace,omitempty"`
+	// Filename is the name of the document, from its Content-Disposition.
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	// Size is the size of the document in bytes.
+	Size int64 `json:"size"`
+	// Checksum is the hex encoded SHA-256 of the document.
+	Checksum string `json:"checksum,omitempty"`
+	// PageCount is the number of pages of PDF documents, counted from their
+	// page objects, or zero when unknown, e.g. for images or for PDFs with
+	// compressed object streams.
+	PageCount int `json:"pageCount,omitempty"`
+	// Duration is the time from sending the request to the end of the
+	// document.
+	Duration time.Duration `json:"duration,omitempty"`
+	// Timings break down the latency of the conversion. The document is
+	// streamed to storage by ConvertAndStore, so Download includes storing it.
+	Timings Timings `json:"timings"`
+	// StorageKey is the object the document is stored as, if stored.
+	StorageKey string `json:"storageKey,omitempty"`
+}
+
+// ConvertAndStore sends the request and uploads the converted document to
+// storage as key, streaming it without buffering:
+//
+//	result, err := client.ConvertHTML(ctx, html).
+//		ConvertAndStore(storage, "invoices/42.pdf")
+//
+// The upload is labelled with the tenant of the request, see ContextWithTenant.
+// It returns the error of Response.Err for failed conversions and
+// ErrWebhookMode for requests in webhook mode, and with VerifyStored an error
+// wrapping ErrIntegrity when the stored object differs from the document.
+func (r *Request) ConvertAndStore(storage Storage, key string) (Result, error) {
+	if r.webhookURL != "" {
+		return Result{}, ErrWebhookMode
+	}
+	start := time.Now()
+	resp, err := r.Send()
+	if err != nil {
+		return Result{}, err
+	}
+	if err := resp.Err(); err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	result := resp.result()
+	counter := &documentCounter{pdf: isPDF(result.ContentType), hash: sha256.New()}
+	ctx := ContextWithTenant(r.ctx, r.tenant)
+	if _, err := storage.UploadFile(ctx, key, io.TeeReader(resp.Body, counter), resp.ContentLength, result.ContentType); err != nil {
+		return Result{}, fmt.Errorf("gotenberg: store %s: %w", key, err)
+	}
+	result.Size = counter.size
+	result.PageCount = counter.pages
+	result.Checksum = hex.EncodeToString(counter.hash.Sum(nil))
+	if r.verifyStored {
+		if err := verifyStored(ctx, storage, key, result); err != nil {
+			// Do not leave a corrupt document behind
+			storage.DeleteFile(ctx, key)
+			return Result{}, err
+		}
+	}
+	result.Timings = resp.Timings()
+	result.Duration = time.Since(start)
+	result.StorageKey = key
+	return result, nil
+}
+
+// VerifyStored makes ConvertAndStore check that the stored object has the
+// size and the SHA-256 checksum of the converted document, guarding against
+// truncated uploads. The object is read back from storage to be checked and
+// deleted when it differs.
+func (r *Request) VerifyStored() *Request {
+	r.verifyStored = true
+	return r
+}
+
+// verifyStored checks the stored object key against result.
+func verifyStored(ctx context.Context, storage Storage, key string, result Result) error {
+	info, err := storage.GetFileInfo(ctx, key)
+	if err != nil {
+		return fmt.Errorf("gotenberg: verify %s: %w", key, err)
+	}
+	if info.Size != result.Size {
+		return fmt.Errorf("%w: %s has %d bytes, the document %d", ErrIntegrity, key, info.Size, result.Size)
+	}
+	content, err := storage.DownloadFile(ctx, key)
+	if err != nil {
+		return fmt.Errorf("gotenberg: verify %s: %w", key, err)
+	}
+	defer content.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return fmt.Errorf("gotenberg: verify %s: %w", key, err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != result.Checksum {
+		return fmt.Errorf("%w: %s has checksum %s, the document %s", ErrIntegrity, key, sum, result.Checksum)
+	}
+	return nil
+}
+
+// result returns the Result fields known from the response headers.
+func (r *Response) result() Result {
+	return Result{
+		Trace:       r.GotenbergTrace,
+		Filename:    r.filename(),
+		ContentType: r.Header.Get("Content-Type"),
+		Size:        r.ContentLength,
+	}
+}
+
+func isPDF(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/pdf"
+}
+
+// pageObject matches the type of a page object, but not of the page tree
+// nodes (/Type /Pages). The byte following the name is part of the match.
+var pageObject = regexp.MustCompile(`/Type\s{0,8}/Page[^s]`)
+
+// documentCounter counts the bytes, and the pages of PDFs, of a streamed
+// document and hashes it.
+type documentCounter struct {
+	pdf   bool
+	size  int64
+	pages int
+	hash  hash.Hash
+
+	// tail holds the last bytes of the previous write, for page objects
+	// split between writes
+	tail []byte
+}
+
+// maxPageObject is longer than the longest match of pageObject.
+const maxPageObject = 32
+
+func (c *documentCounter) Write(p []byte) (int, error) {
+	c.size += int64(len(p))
+	if c.hash != nil {
+		c.hash.Write(p)
+	}
+	if !c.pdf {
+		return len(p), nil
+	}
+	buf := append(c.tail, p...)
+	for _, m := range pageObject.FindAllIndex(buf, -1) {
+		// Matches ending in the tail were counted by the previous write
+		if m[1] > len(c.tail) {
+			c.pages++
+		}
+	}
+	if len(buf) > maxPageObject {
+		buf = buf[len(buf)-maxPageObject:]
+	}
+	c.tail = append(c.tail[:0:0], buf...)
+	return len(p), nil
+}
@@ -0,0 +1,129 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const twoPagePDF = "%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n" +
+	"2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj\n" +
+	"3 0 obj << /Type /Page /Parent 2 0 R >> endobj\n" +
+	"4 0 obj <</Type/Page/Parent 2 0 R>> endobj\n%%EOF\n"
+
+func TestConvertAndStore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="invoice.pdf"`)
+		w.Header().Set(HeaderGotenbergTrace, "trace-42")
+		w.Write([]byte(twoPagePDF))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+
+	result, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		ConvertAndStore(storage, "invoices/42.pdf")
+	if err != nil {
+		t.Fatalf("ConvertAndStore failed: %v", err)
+	}
+	if result.Trace != "trace-42" || result.Filename != "invoice.pdf" || result.ContentType != "application/pdf" ||
+		result.Size != int64(len(twoPagePDF)) || result.PageCount != 2 || result.StorageKey != "invoices/42.pdf" || result.Duration <= 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if string(storage.files["invoices/42.pdf"]) != twoPagePDF || storage.types["invoices/42.pdf"] != "application/pdf" {
+		t.Errorf("unexpected stored object %q", storage.files["invoices/42.pdf"])
+	}
+
+	_, err = c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WebhookURL("http://hook/result", "POST").
+		WebhookErrorURL("http://hook/error", "POST").
+		ConvertAndStore(storage, "invoices/43.pdf")
+	if !errors.Is(err, ErrWebhookMode) {
+		t.Errorf("expected ErrWebhookMode, got %v", err)
+	}
+}
+
+func TestDocumentCounterSplitWrites(t *testing.T) {
+	// every split of the document between two writes counts the same pages
+	for i := 0; i <= len(twoPagePDF); i++ {
+		c := &documentCounter{pdf: true}
+		c.Write([]byte(twoPagePDF[:i]))
+		c.Write([]byte(twoPagePDF[i:]))
+		if c.pages != 2 || c.size != int64(len(twoPagePDF)) {
+			t.Fatalf("split at %d: counted %d pages, %d bytes", i, c.pages, c.size)
+		}
+	}
+}
+
+// truncatingStorage stores the first half of every upload
+type truncatingStorage struct {
+	*memoryStorage
+}
+
+func (s truncatingStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	return s.memoryStorage.UploadFile(ctx, objectName, bytes.NewReader(data[:len(data)/2]), -1, contentType)
+}
+
+// corruptingStorage flips the first byte of every upload
+type corruptingStorage struct {
+	*memoryStorage
+}
+
+func (s corruptingStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	data[0] ^= 0xff
+	return s.memoryStorage.UploadFile(ctx, objectName, bytes.NewReader(data), size, contentType)
+}
+
+func TestConvertAndStoreVerifyStored(t *testing.T) {
+	srv := newDiscardServer(t)
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	convert := func() *Request {
+		return c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).VerifyStored()
+	}
+
+	storage := newMemoryStorage()
+	result, err := convert().ConvertAndStore(storage, "ok.pdf")
+	if err != nil {
+		t.Fatalf("ConvertAndStore failed: %v", err)
+	}
+	sum := sha256.Sum256([]byte("%PDF"))
+	if result.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("unexpected checksum %s", result.Checksum)
+	}
+
+	for name, storage := range map[string]Storage{
+		"truncated": truncatingStorage{newMemoryStorage()},
+		"corrupted": corruptingStorage{newMemoryStorage()},
+	} {
+		if _, err := convert().ConvertAndStore(storage, "bad.pdf"); !errors.Is(err, ErrIntegrity) {
+			t.Errorf("%s: expected ErrIntegrity, got %v", name, err)
+		}
+		if _, err := storage.GetFileInfo(context.Background(), "bad.pdf"); err == nil {
+			t.Errorf("%s: corrupt object was not deleted", name)
+		}
+	}
+}
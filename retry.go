@@ -0,0 +1,184 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// RetryPolicy decides whether and after which wait a failed operation is
+// retried.
+type RetryPolicy interface {
+	// Backoff returns the wait before retry number attempt, starting at 1,
+	// after err, and false to give up.
+	Backoff(attempt int, err error) (time.Duration, bool)
+}
+
+// ExponentialBackoff is a RetryPolicy doubling the wait after every attempt.
+type ExponentialBackoff struct {
+	// Retries is the number of retries after the first attempt.
+	Retries int
+	// Initial is the wait before the first retry.
+	Initial time.Duration
+	// Max caps the wait. Zero means no cap.
+	Max time.Duration
+	// Jitter randomizes every wait between half and all of it, so clients
+	// failing together do not retry together.
+	Jitter bool
+	// Retryable reports whether err is transient, TransientStorageError if nil.
+	Retryable func(err error) bool
+}
+
+// DefaultStorageRetry is the RetryPolicy of RetryingStorage when none is set.
+var DefaultStorageRetry RetryPolicy = ExponentialBackoff{Retries: 3, Initial: 200 * time.Millisecond, Max: 5 * time.Second, Jitter: true}
+
+// Backoff implements RetryPolicy.
+func (b ExponentialBackoff) Backoff(attempt int, err error) (time.Duration, bool) {
+	retryable := b.Retryable
+	if retryable == nil {
+		retryable = TransientStorageError
+	}
+	if attempt > b.Retries || !retryable(err) {
+		return 0, false
+	}
+	wait := b.Initial << (attempt - 1)
+	if b.Max > 0 && (wait > b.Max || wait <= 0) {
+		wait = b.Max
+	}
+	if b.Jitter && wait > 1 {
+		wait = wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+	}
+	return wait, true
+}
+
+// TransientStorageError reports whether err may go away on retry: network
+// errors and the server errors of S3, but not cancellations, missing objects
+// or rejected requests.
+func TransientStorageError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrInvalidBucketName) {
+		return false
+	}
+	var response minio.ErrorResponse
+	if errors.As(err, &response) && response.StatusCode != 0 {
+		return response.StatusCode >= http.StatusInternalServerError ||
+			response.StatusCode == http.StatusRequestTimeout || response.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// RetryingStorage is a Storage retrying the failed operations of Storage
+// following Policy, so transient storage errors do not lose documents
+// already converted:
+//
+//	storage := &gotenberg.RetryingStorage{Storage: minioClient}
+//
+// Uploads of readers that cannot seek are spooled, in memory up to
+// DefaultWebhookMemoryThreshold and to a temporary file above, so they can
+// be sent again.
+type RetryingStorage struct {
+	Storage Storage
+	// Policy is DefaultStorageRetry if nil.
+	Policy RetryPolicy
+}
+
+var (
+	_ Storage = (*RetryingStorage)(nil)
+	_ Lister  = (*RetryingStorage)(nil)
+)
+
+// retry runs op until it succeeds, the policy gives up or ctx is done.
+func (s *RetryingStorage) retry(ctx context.Context, op func() error) error {
+	policy := s.Policy
+	if policy == nil {
+		policy = DefaultStorageRetry
+	}
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		wait, ok := policy.Backoff(attempt, err)
+		if !ok {
+			if attempt > 1 {
+				return fmt.Errorf("gotenberg: storage failed after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("gotenberg: storage retry canceled: %w", errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+	}
+}
+
+// UploadFile implements Storage, rewinding the reader for every attempt.
+func (s *RetryingStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		spooled, err := spoolBody(reader, DefaultWebhookMemoryThreshold)
+		if err != nil {
+			return nil, err
+		}
+		defer spooled.Close()
+		seeker, size = spooled, spooled.size
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+
+	var info *minio.UploadInfo
+	err = s.retry(ctx, func() error {
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		var err error
+		info, err = s.Storage.UploadFile(ctx, objectName, seeker, size, contentType)
+		return err
+	})
+	return info, err
+}
+
+// DownloadFile implements Storage. Only opening the object is retried.
+func (s *RetryingStorage) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	var content io.ReadCloser
+	err := s.retry(ctx, func() error {
+		var err error
+		content, err = s.Storage.DownloadFile(ctx, objectName)
+		return err
+	})
+	return content, err
+}
+
+// GetFileInfo implements Storage.
+func (s *RetryingStorage) GetFileInfo(ctx context.Context, objectName string) (minio.ObjectInfo, error) {
+	var info minio.ObjectInfo
+	err := s.retry(ctx, func() error {
+		var err error
+		info, err = s.Storage.GetFileInfo(ctx, objectName)
+		return err
+	})
+	return info, err
+}
+
+// DeleteFile implements Storage.
+func (s *RetryingStorage) DeleteFile(ctx context.Context, objectName string) error {
+	return s.retry(ctx, func() error {
+		return s.Storage.DeleteFile(ctx, objectName)
+	})
+}
+
+// ListFiles implements Lister for storages implementing it, without retries.
+func (s *RetryingStorage) ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo {
+	return listFiles(ctx, s.Storage, prefix)
+}
@@ -0,0 +1,104 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// flakyStorage fails the first uploads with err after reading part of the body.
+type flakyStorage struct {
+	memoryStorage
+	failures int
+	err      error
+	attempts int
+}
+
+func (s *flakyStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	s.attempts++
+	if s.attempts <= s.failures {
+		io.CopyN(io.Discard, reader, 3)
+		return nil, s.err
+	}
+	return s.memoryStorage.UploadFile(ctx, objectName, reader, size, contentType)
+}
+
+func TestRetryingStorageUpload(t *testing.T) {
+	serverError := minio.ErrorResponse{Code: "InternalError", StatusCode: 500}
+	for name, reader := range map[string]func() io.Reader{
+		"seeker": func() io.Reader { return strings.NewReader("%PDF-1.7 document") },
+		"stream": func() io.Reader { return io.MultiReader(strings.NewReader("%PDF-1.7 "), strings.NewReader("document")) },
+	} {
+		backend := &flakyStorage{memoryStorage: *newMemoryStorage(), failures: 2, err: serverError}
+		storage := &RetryingStorage{Storage: backend, Policy: ExponentialBackoff{Retries: 2, Initial: time.Millisecond}}
+		if _, err := storage.UploadFile(context.Background(), "doc.pdf", reader(), -1, "application/pdf"); err != nil {
+			t.Fatalf("%s: upload failed: %v", name, err)
+		}
+		if backend.attempts != 3 || string(backend.files["doc.pdf"]) != "%PDF-1.7 document" {
+			t.Errorf("%s: %d attempts stored %q", name, backend.attempts, backend.files["doc.pdf"])
+		}
+	}
+
+	backend := &flakyStorage{memoryStorage: *newMemoryStorage(), failures: 5, err: serverError}
+	storage := &RetryingStorage{Storage: backend, Policy: ExponentialBackoff{Retries: 2, Initial: time.Millisecond}}
+	_, err := storage.UploadFile(context.Background(), "doc.pdf", strings.NewReader("pdf"), 3, "application/pdf")
+	if !errors.As(err, &minio.ErrorResponse{}) || backend.attempts != 3 {
+		t.Errorf("expected the storage error after 3 attempts, got %v after %d", err, backend.attempts)
+	}
+}
+
+func TestRetryingStoragePermanentErrors(t *testing.T) {
+	backend := &flakyStorage{memoryStorage: *newMemoryStorage(), failures: 1, err: minio.ErrorResponse{Code: "AccessDenied", StatusCode: 403}}
+	storage := &RetryingStorage{Storage: backend}
+	if _, err := storage.UploadFile(context.Background(), "doc.pdf", strings.NewReader("pdf"), 3, "application/pdf"); err == nil || backend.attempts != 1 {
+		t.Errorf("permanent error retried: %v after %d attempts", err, backend.attempts)
+	}
+	if _, err := storage.GetFileInfo(context.Background(), "missing.pdf"); !errors.Is(err, errNotFound) {
+		t.Errorf("expected errNotFound, got %v", err)
+	}
+}
+
+func TestRetryingStorageCancel(t *testing.T) {
+	backend := &flakyStorage{memoryStorage: *newMemoryStorage(), failures: 5, err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	storage := &RetryingStorage{Storage: backend, Policy: ExponentialBackoff{Retries: 5, Initial: time.Hour}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := storage.UploadFile(ctx, "doc.pdf", strings.NewReader("pdf"), 3, "application/pdf")
+	if !errors.Is(err, context.DeadlineExceeded) || backend.attempts != 1 {
+		t.Errorf("expected context.DeadlineExceeded after 1 attempt, got %v after %d", err, backend.attempts)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff{Retries: 4, Initial: 100 * time.Millisecond, Max: 300 * time.Millisecond}
+	var waits []time.Duration
+	for attempt := 1; ; attempt++ {
+		wait, ok := b.Backoff(attempt, io.ErrUnexpectedEOF)
+		if !ok {
+			break
+		}
+		waits = append(waits, wait)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	if len(waits) != len(want) {
+		t.Fatalf("unexpected waits %v", waits)
+	}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("unexpected waits %v", waits)
+		}
+	}
+
+	b.Jitter = true
+	for i := 0; i < 100; i++ {
+		if wait, _ := b.Backoff(2, io.ErrUnexpectedEOF); wait < 100*time.Millisecond || wait > 200*time.Millisecond {
+			t.Fatalf("jittered wait %s out of range", wait)
+		}
+	}
+}
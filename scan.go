@@ -0,0 +1,142 @@
+package gotenberg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrMalwareDetected is returned by a Scanner for documents found infected.
+// MinioAPI answers it with 422.
+var ErrMalwareDetected = errors.New("gotenberg: malware detected")
+
+// Scanner scans uploaded documents for malware before MinioAPI stores them or
+// forwards them to Gotenberg, see WithScanner.
+type Scanner interface {
+	// Scan reads the document filename from r. It returns an error wrapping
+	// ErrMalwareDetected for infected documents, other errors for failures of
+	// the scan itself.
+	Scan(ctx context.Context, filename string, r io.Reader) error
+}
+
+// ScannerFunc is a Scanner of a function.
+type ScannerFunc func(ctx context.Context, filename string, r io.Reader) error
+
+// Scan implements Scanner.
+func (f ScannerFunc) Scan(ctx context.Context, filename string, r io.Reader) error {
+	return f(ctx, filename, r)
+}
+
+// WithScanner scans the files of the upload and conversion endpoints with
+// scanner, answering infected files with 422 and failures of the scanner with
+// 502, so nothing is stored or converted unscanned.
+func WithScanner(scanner Scanner) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.scanner = scanner
+	}
+}
+
+// scanUpload scans file, named filename, with scanner if not nil, and rewinds it.
+func scanUpload(ctx context.Context, scanner Scanner, filename string, file io.ReadSeeker) error {
+	if scanner == nil {
+		return nil
+	}
+	if err := scanner.Scan(ctx, filename, file); err != nil {
+		return err
+	}
+	_, err := file.Seek(0, io.SeekStart)
+	return err
+}
+
+// ClamAV is a Scanner streaming documents to a clamd daemon with the INSTREAM
+// command:
+//
+//	api := gotenberg.NewMinioAPI(minioClient, gotenberg.WithScanner(&gotenberg.ClamAV{Address: "clamav:3310"}))
+//
+// Documents larger than the StreamMaxLength of clamd fail to scan.
+type ClamAV struct {
+	// Network is "tcp" if empty, "unix" for a local socket.
+	Network string
+	Address string
+	// Timeout bounds a scan, unless ctx has an earlier deadline. Zero means no timeout.
+	Timeout time.Duration
+}
+
+var _ Scanner = (*ClamAV)(nil)
+
+// clamChunkSize is the size of the chunks the document is streamed in.
+const clamChunkSize = 32 << 10
+
+// Scan implements Scanner.
+func (c *ClamAV) Scan(ctx context.Context, filename string, r io.Reader) error {
+	network := c.Network
+	if network == "" {
+		network = "tcp"
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, c.Address)
+	if err != nil {
+		return fmt.Errorf("gotenberg: clamav: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if err := clamStream(conn, r); err != nil {
+		return fmt.Errorf("gotenberg: clamav: %w", errors.Join(ctx.Err(), err))
+	}
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("gotenberg: clamav: %w", errors.Join(ctx.Err(), err))
+	}
+	reply = strings.TrimSpace(strings.TrimSuffix(reply, "\x00"))
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return nil
+	case strings.HasSuffix(result, " FOUND"):
+		return fmt.Errorf("%w: %s: %s", ErrMalwareDetected, filename, strings.TrimSuffix(result, " FOUND"))
+	}
+	return fmt.Errorf("gotenberg: clamav: %s", reply)
+}
+
+// clamStream sends r with the INSTREAM command, in length prefixed chunks
+// ended by an empty one.
+func clamStream(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+	buf := make([]byte, 4+clamChunkSize)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, err := w.Write(buf[:4+n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(bytes.Repeat([]byte{0}, 4))
+	return err
+}
@@ -0,0 +1,131 @@
+package gotenberg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// eicar is the standard antivirus test file.
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// newFakeClamd serves the INSTREAM command, reporting streams containing
+// eicar as infected, and returns its address.
+func newFakeClamd(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if command, err := r.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+					io.WriteString(conn, "UNKNOWN COMMAND\x00")
+					return
+				}
+				var stream bytes.Buffer
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(&stream, r, int64(size)); err != nil {
+						return
+					}
+				}
+				if strings.Contains(stream.String(), eicar) {
+					io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+					return
+				}
+				io.WriteString(conn, "stream: OK\x00")
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestClamAV(t *testing.T) {
+	scanner := &ClamAV{Address: newFakeClamd(t)}
+
+	clean := strings.Repeat("<p>clean</p>", 10000)
+	if err := scanner.Scan(context.Background(), "index.html", strings.NewReader(clean)); err != nil {
+		t.Errorf("clean document: %v", err)
+	}
+
+	err := scanner.Scan(context.Background(), "index.html", strings.NewReader(clean+eicar))
+	if !errors.Is(err, ErrMalwareDetected) || !strings.Contains(err.Error(), "Eicar-Test-Signature") {
+		t.Errorf("infected document error = %v, want ErrMalwareDetected with the signature", err)
+	}
+
+	down := &ClamAV{Address: "127.0.0.1:1"}
+	if err := down.Scan(context.Background(), "index.html", strings.NewReader(clean)); err == nil || errors.Is(err, ErrMalwareDetected) {
+		t.Errorf("unreachable daemon error = %v, want a scan failure", err)
+	}
+}
+
+func TestMinioAPIScansUploads(t *testing.T) {
+	var scanned []string
+	scanner := ScannerFunc(func(ctx context.Context, filename string, r io.Reader) error {
+		content, _ := io.ReadAll(r)
+		scanned = append(scanned, filename)
+		if bytes.Contains(content, []byte(eicar)) {
+			return ErrMalwareDetected
+		}
+		return nil
+	})
+	cli, capture := newCaptureClient(t)
+	api := NewMinioAPI(nil, WithGotenberg(cli), WithScanner(scanner))
+
+	post := func(handler http.HandlerFunc, path string, files map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for field, filename := range map[string]string{"file": "index.html", "assets": "logo.txt"} {
+			if content, ok := files[field]; ok {
+				part, _ := mw.CreateFormFile(field, filename)
+				io.WriteString(part, content)
+			}
+		}
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := post(api.HandleUpload, "/api/upload", map[string]string{"file": "<html>" + eicar + "</html>"})
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"malware_detected"`) {
+		t.Errorf("infected upload = %d %s, want 422", rec.Code, rec.Body.String())
+	}
+
+	scanned = nil
+	rec = post(api.HandleConvert, "/api/convert", map[string]string{"file": "<html></html>", "assets": eicar})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("infected asset = %d %s, want 422", rec.Code, rec.Body.String())
+	}
+	if len(scanned) != 2 || scanned[0] != "index.html" || scanned[1] != "logo.txt" {
+		t.Errorf("scanned = %v, want the document and its asset", scanned)
+	}
+	if capture.path != "" {
+		t.Errorf("infected conversion was forwarded to %s", capture.path)
+	}
+}
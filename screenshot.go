@@ -0,0 +1,94 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ScreenshotHTML creates a request to capture a screenshot of HTML content.
+// The response body contains the image instead of a PDF document.
+func (c *Client) ScreenshotHTML(ctx context.Context, html io.Reader) *Request {
+	return c.newRequest(ctx, ScreenshotHTML).File(FieldFiles, FileIndexHTML, html)
+}
+
+// ScreenshotURL creates a request to capture a screenshot of the web page at url.
+// The response body contains the image instead of a PDF document.
+func (c *Client) ScreenshotURL(ctx context.Context, url string) *Request {
+	return c.newRequest(ctx, ScreenshotURL).Param(FieldURL, url)
+}
+
+// ScreenshotMarkdown creates a request to capture a screenshot of markdown
+// files rendered by index, as with ConvertMarkdown.
+// The response body contains the image instead of a PDF document.
+func (c *Client) ScreenshotMarkdown(ctx context.Context, index io.Reader, markdownFiles ...NamedReader) *Request {
+	r := c.newRequest(ctx, ScreenshotMarkdown).File(FieldFiles, FileIndexHTML, index)
+	return r.markdownFiles(markdownFiles)
+}
+
+// ScreenshotWidth sets the width of the screenshot viewport in pixels.
+func (r *Request) ScreenshotWidth(width int) *Request {
+	return r.screenshotPixels(FieldScreenshotWidth, width)
+}
+
+// ScreenshotHeight sets the height of the screenshot viewport in pixels.
+func (r *Request) ScreenshotHeight(height int) *Request {
+	return r.screenshotPixels(FieldScreenshotHeight, height)
+}
+
+func (r *Request) screenshotPixels(fieldName string, pixels int) *Request {
+	if pixels <= 0 {
+		r.setErr(fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidField, fieldName, pixels))
+		return r
+	}
+	return r.Param(fieldName, strconv.Itoa(pixels))
+}
+
+// ScreenshotFormat sets the image format of the screenshot, ImageFormatPNG
+// (Gotenberg's default), ImageFormatJPEG or ImageFormatWebP.
+func (r *Request) ScreenshotFormat(format string) *Request {
+	switch format {
+	case ImageFormatPNG, ImageFormatJPEG, ImageFormatWebP:
+	default:
+		r.setErr(fmt.Errorf("%w: unsupported screenshot format %q", ErrInvalidField, format))
+		return r
+	}
+	r.screenshotFormat = format
+	return r.Param(FieldScreenshotFormat, format)
+}
+
+// ScreenshotClip clips the screenshot to the viewport set by ScreenshotWidth
+// and ScreenshotHeight instead of capturing the whole page.
+func (r *Request) ScreenshotClip(clip bool) *Request {
+	return r.Bool(FieldScreenshotClip, clip)
+}
+
+// ScreenshotQuality sets the compression quality of ImageFormatJPEG
+// screenshots, from 0 to 100. Other formats fail with ErrInvalidField.
+func (r *Request) ScreenshotQuality(quality int) *Request {
+	if quality < 0 || quality > 100 {
+		r.setErr(fmt.Errorf("%w: screenshot quality must be between 0 and 100, got %d", ErrInvalidField, quality))
+		return r
+	}
+	r.screenshotQuality = true
+	return r.Param(FieldScreenshotQuality, strconv.Itoa(quality))
+}
+
+// ScreenshotOptimizeForSpeed makes the encoding of the screenshot faster at
+// the cost of a larger image.
+func (r *Request) ScreenshotOptimizeForSpeed(optimize bool) *Request {
+	return r.Bool(FieldScreenshotOptimizeForSpeed, optimize)
+}
+
+// validateScreenshot checks the screenshot options against each other.
+func (r *Request) validateScreenshot() error {
+	if r.screenshotQuality && r.screenshotFormat != ImageFormatJPEG {
+		format := r.screenshotFormat
+		if format == "" {
+			format = ImageFormatPNG
+		}
+		return fmt.Errorf("%w: ScreenshotQuality requires format %s, not %s", ErrInvalidField, ImageFormatJPEG, format)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScreenshotRoutes(t *testing.T) {
+	ctx := context.Background()
+	c, capture := newCaptureClient(t)
+
+	tests := []struct {
+		name  string
+		req   *Request
+		route string
+	}{
+		{"html", c.ScreenshotHTML(ctx, strings.NewReader("<html></html>")), ScreenshotHTML},
+		{"url", c.ScreenshotURL(ctx, "https://example.com"), ScreenshotURL},
+		{"markdown", c.ScreenshotMarkdown(ctx, strings.NewReader(`{{ toHTML "a.md" }}`),
+			NamedReader{Name: "a.md", Reader: strings.NewReader("# A")}), ScreenshotMarkdown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.
+				ScreenshotWidth(1280).
+				ScreenshotHeight(720).
+				ScreenshotFormat(ImageFormatJPEG).
+				ScreenshotQuality(80).
+				ScreenshotClip(true).
+				ScreenshotOptimizeForSpeed(true).
+				Send()
+			if err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+			if capture.path != tt.route {
+				t.Errorf("unexpected route %s", capture.path)
+			}
+			if capture.value(FieldScreenshotWidth) != "1280" || capture.value(FieldScreenshotHeight) != "720" {
+				t.Errorf("unexpected size %sx%s", capture.value(FieldScreenshotWidth), capture.value(FieldScreenshotHeight))
+			}
+			if got := capture.value(FieldScreenshotFormat); got != ImageFormatJPEG {
+				t.Errorf("unexpected format %q", got)
+			}
+			if capture.value(FieldScreenshotQuality) != "80" || capture.value(FieldScreenshotClip) != "true" ||
+				capture.value(FieldScreenshotOptimizeForSpeed) != "true" {
+				t.Errorf("unexpected options quality=%q clip=%q optimizeForSpeed=%q", capture.value(FieldScreenshotQuality),
+					capture.value(FieldScreenshotClip), capture.value(FieldScreenshotOptimizeForSpeed))
+			}
+		})
+	}
+	if capture.value(FieldURL) != "" || capture.files["a.md"] != "# A" {
+		t.Errorf("unexpected markdown request %v", capture.files)
+	}
+}
+
+func TestScreenshotInvalidFields(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	for _, r := range []*Request{
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotFormat("gif"),
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotWidth(0),
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotHeight(-1),
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotQuality(101),
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotQuality(80),
+		c.ScreenshotURL(context.Background(), "https://example.com").ScreenshotQuality(80).ScreenshotFormat(ImageFormatWebP),
+	} {
+		if _, err := r.Send(); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("expected ErrInvalidField, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// DefaultDrainPollInterval is how often Drainer.Wait checks for pending work.
+const DefaultDrainPollInterval = 500 * time.Millisecond
+
+// ErrDraining is reported by Drainer.Check while the service drains.
+var ErrDraining = errors.New("server: draining, not accepting new conversions")
+
+// Drainer takes the service out of rotation before it exits: once started, the
+// conversion handlers wrapped by Middleware answer new requests with 503 while
+// the requests in flight and the conversions awaiting their webhook complete.
+// Webhook callback handlers must not be wrapped, so pending results still arrive.
+//
+// A drain is started with Start, by a request to the Drainer itself (it is
+// the handler of the drain endpoint, see Config.DrainAuth), or by Server on
+// SIGTERM.
+type Drainer struct {
+	// Pending returns the number of conversions awaiting their webhook,
+	// e.g. OutboxPending. Only requests in flight are awaited if nil.
+	Pending func(ctx context.Context) (int, error)
+
+	// PollInterval is DefaultDrainPollInterval if zero.
+	PollInterval time.Duration
+
+	mu        sync.Mutex
+	inflight  int
+	draining  bool
+	requested chan struct{}
+}
+
+// OutboxPending returns a Drainer.Pending counting the pending jobs of outbox.
+func OutboxPending(outbox *gotenberg.Outbox) func(ctx context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		jobs, err := outbox.Pending(ctx)
+		return len(jobs), err
+	}
+}
+
+// Start stops accepting new conversions. It is safe to call more than once.
+func (d *Drainer) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.draining {
+		d.draining = true
+		close(d.requestedLocked())
+	}
+}
+
+// Draining reports whether Start was called.
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// Requested is closed when the drain starts.
+func (d *Drainer) Requested() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.requestedLocked()
+}
+
+func (d *Drainer) requestedLocked() chan struct{} {
+	if d.requested == nil {
+		d.requested = make(chan struct{})
+	}
+	return d.requested
+}
+
+// Check is a Health check failing while the service drains, so that load
+// balancers stop routing to it.
+func (d *Drainer) Check(ctx context.Context) error {
+	if d.Draining() {
+		return ErrDraining
+	}
+	return nil
+}
+
+// Middleware tracks the requests in flight of next and answers new requests
+// with 503 once the drain started.
+func (d *Drainer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			w.Header().Set("Connection", "close")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(gotenberg.ErrorResponse{
+				Code:  gotenberg.CodeUnavailable,
+				Error: ErrDraining.Error(),
+				Trace: r.Header.Get(gotenberg.HeaderRequestID),
+			})
+			return
+		}
+		d.inflight++
+		d.mu.Unlock()
+
+		defer func() {
+			d.mu.Lock()
+			d.inflight--
+			d.mu.Unlock()
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ServeHTTP is the drain endpoint: a POST starts the drain and is answered with 202.
+func (d *Drainer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	d.Start()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Wait waits until no request is in flight and no conversion is pending, or
+// until ctx is done. It does not start the drain.
+func (d *Drainer) Wait(ctx context.Context) error {
+	interval := d.PollInterval
+	if interval == 0 {
+		interval = DefaultDrainPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		idle, err := d.idle(ctx)
+		if err != nil {
+			return err
+		}
+		if idle {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Drainer) idle(ctx context.Context) (bool, error) {
+	d.mu.Lock()
+	inflight := d.inflight
+	d.mu.Unlock()
+	if inflight > 0 {
+		return false, nil
+	}
+	if d.Pending == nil {
+		return true, nil
+	}
+	pending, err := d.Pending(ctx)
+	if err != nil {
+		return false, err
+	}
+	return pending == 0, nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDrainerMiddleware(t *testing.T) {
+	d := &Drainer{}
+	handler := d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/convert", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected request to be served, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, DefaultDrainPath, nil))
+	if rec.Code != http.StatusAccepted || !d.Draining() {
+		t.Fatalf("expected drain to start, got %d", rec.Code)
+	}
+	if d.Check(context.Background()) != ErrDraining {
+		t.Error("expected health check to fail while draining")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/convert", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", rec.Code)
+	}
+}
+
+func TestServerDrain(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pending atomic.Int32
+	pending.Store(1)
+	d := &Drainer{
+		Pending:      func(ctx context.Context) (int, error) { return int(pending.Load()), nil },
+		PollInterval: 10 * time.Millisecond,
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/api/convert", d.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	Config{Drainer: d, DrainAuth: RequireBearerToken("secret")}.Register(mux)
+
+	srv := &Server{Handler: mux, Drainer: d}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(context.Background(), ln) }()
+
+	base := "http://" + ln.Addr().String()
+	resp, err := http.Post(base+DefaultDrainPath, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || d.Draining() {
+		t.Fatalf("expected unauthenticated drain to be refused, got %d", resp.StatusCode)
+	}
+	req, _ := http.NewRequest(http.MethodPost, base+DefaultDrainPath, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	select {
+	case err := <-done:
+		t.Fatalf("server exited with a pending webhook: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	resp, err = http.Post(base+"/api/convert", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", resp.StatusCode)
+	}
+
+	pending.Store(0)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected clean exit, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit after the drain")
+	}
+}
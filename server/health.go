@@ -0,0 +1,130 @@
+// Package server provides the operational endpoints of a conversion service
+// built from the handlers of the gotenberg package.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+// DefaultHealthTimeout bounds every check of a Health handler.
+const DefaultHealthTimeout = 5 * time.Second
+
+// healthProbe is the object and job looked up to check storages and job stores.
+const healthProbe = ".healthz"
+
+// Check reports whether a dependency of the service is ready.
+type Check func(ctx context.Context) error
+
+// Health is the readiness endpoint of the service, usually mounted at /healthz.
+// It runs all checks concurrently and answers 200 when all pass and 503 otherwise,
+// with the result of every check:
+//
+//	{"status": "down", "checks": {"gotenberg": {"status": "up"}, "storage": {"status": "down", "error": "..."}}}
+type Health struct {
+	// Timeout bounds every check, DefaultHealthTimeout if zero.
+	Timeout time.Duration
+
+	names  []string
+	checks []Check
+}
+
+// HealthReport is the response of a Health handler.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// CheckResult is the result of a single check.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Add registers check under name.
+func (h *Health) Add(name string, check Check) *Health {
+	h.names = append(h.names, name)
+	h.checks = append(h.checks, check)
+	return h
+}
+
+// Report runs all checks.
+func (h *Health) Report(ctx context.Context) HealthReport {
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = DefaultHealthTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	report := HealthReport{Status: "up", Checks: make(map[string]CheckResult, len(h.checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := CheckResult{Status: "up"}
+			if err := check(ctx); err != nil {
+				result = CheckResult{Status: "down", Error: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != "up" {
+				report.Status = "down"
+			}
+		}(h.names[i], check)
+	}
+	wg.Wait()
+	return report
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := h.Report(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != "up" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// GotenbergCheck checks the /health route of the Gotenberg of client.
+func GotenbergCheck(client *gotenberg.Client) Check {
+	return client.Health
+}
+
+// StorageCheck checks that storage is reachable by looking up a probe object.
+// A missing object counts as reachable.
+func StorageCheck(storage gotenberg.Storage) Check {
+	return func(ctx context.Context) error {
+		_, err := storage.GetFileInfo(ctx, healthProbe)
+		var resp minio.ErrorResponse
+		if errors.As(err, &resp) && resp.Code == "NoSuchKey" {
+			return nil
+		}
+		return err
+	}
+}
+
+// JobStoreCheck checks that store can be read by looking up a probe job.
+func JobStoreCheck(store gotenberg.JobStore) Check {
+	return func(ctx context.Context) error {
+		_, err := store.Get(ctx, healthProbe)
+		if errors.Is(err, gotenberg.ErrJobNotFound) {
+			return nil
+		}
+		return err
+	}
+}
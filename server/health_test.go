@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+func TestHealth(t *testing.T) {
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != gotenberg.HealthCheck {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"status":"down"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"up"}`))
+	}))
+	defer srv.Close()
+	client, err := gotenberg.NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	health := (&Health{}).
+		Add("gotenberg", GotenbergCheck(client)).
+		Add("jobs", JobStoreCheck(gotenberg.NewMemoryJobStore()))
+
+	get := func() (int, HealthReport) {
+		rec := httptest.NewRecorder()
+		health.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var report HealthReport
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, report
+	}
+
+	if code, report := get(); code != http.StatusOK || report.Status != "up" || report.Checks["jobs"].Status != "up" {
+		t.Errorf("unexpected report %d %+v", code, report)
+	}
+
+	up = false
+	health.Add("storage", func(ctx context.Context) error { return errors.New("unreachable") })
+	code, report := get()
+	if code != http.StatusServiceUnavailable || report.Status != "down" {
+		t.Errorf("unexpected report %d %+v", code, report)
+	}
+	if report.Checks["gotenberg"].Status != "down" || report.Checks["storage"].Error != "unreachable" {
+		t.Errorf("unexpected checks %+v", report.Checks)
+	}
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultHealthPath is the path the Health handler of a Config is served at.
+const DefaultHealthPath = "/healthz"
+
+// DefaultDrainPath is the path the Drainer of a Config is served at.
+const DefaultDrainPath = "/drain"
+
+// Config selects the operational endpoints registered next to the conversion
+// handlers of the service. Profiling and metrics are off by default, they
+// expose internals and belong on an internal listener or behind authentication.
+// The drain endpoint lets anyone reaching it take the service down, and is
+// only registered with DrainAuth.
+type Config struct {
+	// Health is served at HealthPath if not nil.
+	Health *Health
+	// HealthPath is DefaultHealthPath if empty.
+	HealthPath string
+
+	// Drainer is served at DrainPath, wrapped by DrainAuth, if both are not
+	// nil. A POST starts the drain.
+	Drainer *Drainer
+	// DrainPath is DefaultDrainPath if empty.
+	DrainPath string
+	// DrainAuth authenticates the requests to the drain endpoint, e.g.
+	// RequireBearerToken. On a mux served by an internal listener only, it
+	// may return the handler unchanged.
+	DrainAuth func(http.Handler) http.Handler
+
+	// Profiling registers the net/http/pprof handlers below /debug/pprof/.
+	Profiling bool
+
+	// Metrics registers the expvar handler at /debug/vars, publishing the
+	// runtime memory statistics and the variables of the application.
+	Metrics bool
+}
+
+// Register registers the endpoints enabled by c on mux.
+func (c Config) Register(mux *http.ServeMux) {
+	if c.Health != nil {
+		path := c.HealthPath
+		if path == "" {
+			path = DefaultHealthPath
+		}
+		mux.Handle(path, c.Health)
+	}
+	if c.Drainer != nil && c.DrainAuth != nil {
+		path := c.DrainPath
+		if path == "" {
+			path = DefaultDrainPath
+		}
+		mux.Handle(path, c.DrainAuth(c.Drainer))
+	}
+	if c.Profiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	if c.Metrics {
+		mux.Handle("/debug/vars", expvar.Handler())
+	}
+}
+
+// RequireBearerToken returns a Config.DrainAuth answering requests without
+// the header "Authorization: Bearer <token>" with 401.
+func RequireBearerToken(token string) func(http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DefaultDrainTimeout is the time a Server waits for the drain to complete.
+const DefaultDrainTimeout = 30 * time.Second
+
+// DefaultShutdownTimeout is the time open connections get to close after the drain.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Server serves the conversion service and exits cleanly: on SIGTERM, or when
+// the drain is started through the Drainer, it stops accepting conversions,
+// waits for the requests in flight and the pending webhooks up to DrainTimeout,
+// then shuts the listener down.
+type Server struct {
+	// Addr is the TCP address to listen on, ":http" or ":https" if empty.
+	Addr string
+
+	// Handler serves the service, with its conversion routes wrapped by
+	// Drainer.Middleware.
+	Handler http.Handler
+
+	// TLSConfig enables HTTPS. It must provide the certificate through
+	// Certificates or GetCertificate.
+	TLSConfig *tls.Config
+
+	// Drainer is drained before shutdown if not nil.
+	Drainer *Drainer
+
+	// DrainTimeout is DefaultDrainTimeout if zero.
+	DrainTimeout time.Duration
+
+	// ShutdownTimeout is DefaultShutdownTimeout if zero.
+	ShutdownTimeout time.Duration
+}
+
+// Run serves until the process receives SIGINT or SIGTERM or the drain is started.
+func (s *Server) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.ListenAndServe(ctx)
+}
+
+// ListenAndServe listens on Addr and serves until ctx is done or the drain is started.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	addr := s.Addr
+	if addr == "" {
+		addr = ":http"
+		if s.TLSConfig != nil {
+			addr = ":https"
+		}
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve serves on ln until ctx is done or the drain is started. It returns nil
+// after a complete drain and graceful shutdown, and the drain error if the
+// drain did not complete in time; the server is shut down in both cases.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler,
+		TLSConfig:         s.TLSConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		if s.TLSConfig != nil {
+			errCh <- srv.ServeTLS(ln, "", "")
+		} else {
+			errCh <- srv.Serve(ln)
+		}
+	}()
+
+	var requested <-chan struct{}
+	if s.Drainer != nil {
+		requested = s.Drainer.Requested()
+	}
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	case <-requested:
+	}
+
+	var drainErr error
+	if s.Drainer != nil {
+		s.Drainer.Start()
+		timeout := s.DrainTimeout
+		if timeout == 0 {
+			timeout = DefaultDrainTimeout
+		}
+		drainCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		if err := s.Drainer.Wait(drainCtx); err != nil {
+			drainErr = fmt.Errorf("server: drain: %w", err)
+		}
+		cancel()
+	}
+
+	timeout := s.ShutdownTimeout
+	if timeout == 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return errors.Join(drainErr, err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return errors.Join(drainErr, err)
+	}
+	return drainErr
+}
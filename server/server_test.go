@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfigRegister(t *testing.T) {
+	get := func(cfg Config, path string) int {
+		mux := http.NewServeMux()
+		cfg.Register(mux)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	if code := get(Config{}, "/debug/pprof/"); code != http.StatusNotFound {
+		t.Errorf("expected pprof to be disabled by default, got %d", code)
+	}
+	if code := get(Config{}, "/debug/vars"); code != http.StatusNotFound {
+		t.Errorf("expected metrics to be disabled by default, got %d", code)
+	}
+	if code := get(Config{Profiling: true}, "/debug/pprof/"); code != http.StatusOK {
+		t.Errorf("expected pprof index, got %d", code)
+	}
+	if code := get(Config{Metrics: true}, "/debug/vars"); code != http.StatusOK {
+		t.Errorf("expected expvar handler, got %d", code)
+	}
+	if code := get(Config{Health: &Health{}, HealthPath: "/ready"}, "/ready"); code != http.StatusOK {
+		t.Errorf("expected health handler, got %d", code)
+	}
+	if code := get(Config{Drainer: &Drainer{}}, DefaultDrainPath); code != http.StatusNotFound {
+		t.Errorf("expected drain endpoint to require DrainAuth, got %d", code)
+	}
+	if code := get(Config{Drainer: &Drainer{}, DrainAuth: RequireBearerToken("secret")}, DefaultDrainPath); code != http.StatusUnauthorized {
+		t.Errorf("expected unauthenticated drain to be refused, got %d", code)
+	}
+}
@@ -0,0 +1,183 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrInvalidSpec is returned for conversion specs that cannot be executed.
+	ErrInvalidSpec = errors.New("gotenberg: invalid conversion spec")
+
+	// ErrNoStorage is returned when a spec references storage objects and the
+	// client has no storage configured, see WithStorage.
+	ErrNoStorage = errors.New("gotenberg: no storage configured")
+)
+
+// ConversionSpec is a serializable description of a conversion request.
+// Specs can be marshalled to JSON, stored in a queue or database and
+// executed later with Client.FromSpec, which makes durable job queues
+// possible on top of this package.
+type ConversionSpec struct {
+	// Route is the Gotenberg route, e.g. ConvertHTML.
+	Route  string `json:"route"`
+	Tenant string `json:"tenant,omitempty"`
+
+	// Profile names the Profile providing the defaults of the spec, see WithProfiles.
+	Profile string `json:"profile,omitempty"`
+
+	// Fields are the form fields, e.g. FieldPaperWidth.
+	Fields map[string]string `json:"fields,omitempty"`
+	// Headers are additional request headers, e.g. HeaderOutputFilename.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Metadata are PDF metadata entries, see Request.Metadata.
+	Metadata map[string]any `json:"metadata,omitempty"`
+
+	Files   []FileRef    `json:"files,omitempty"`
+	Webhook *WebhookSpec `json:"webhook,omitempty"`
+}
+
+// FileRef references a file of a conversion spec. Exactly one of Data, Path,
+// Object and URL must be set.
+type FileRef struct {
+	// Key is the form field of the file, FieldFiles if empty.
+	Key      string `json:"key,omitempty"`
+	Filename string `json:"filename,omitempty"`
+
+	// Data is the inline file content.
+	Data []byte `json:"data,omitempty"`
+	// Path is a local file, read when the spec is executed.
+	Path string `json:"path,omitempty"`
+	// Object is an object of the client's storage, see WithStorage.
+	Object string `json:"object,omitempty"`
+	// URL is downloaded by Gotenberg itself, sending Headers.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// WebhookSpec is the webhook configuration of a conversion spec.
+type WebhookSpec struct {
+	URL         string            `json:"url"`
+	Method      string            `json:"method,omitempty"`
+	ErrorURL    string            `json:"errorUrl"`
+	ErrorMethod string            `json:"errorMethod,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// Validate reports whether the spec can be executed.
+func (s *ConversionSpec) Validate() error {
+	if s.Route == "" {
+		return fmt.Errorf("%w: missing route", ErrInvalidSpec)
+	}
+	for i, f := range s.Files {
+		sources := 0
+		for _, set := range []bool{f.Data != nil, f.Path != "", f.Object != "", f.URL != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("%w: file %d must have exactly one of data, path, object and url", ErrInvalidSpec, i)
+		}
+		if f.URL == "" && f.Filename == "" {
+			return fmt.Errorf("%w: file %d has no filename", ErrInvalidSpec, i)
+		}
+	}
+	if s.Webhook != nil && (s.Webhook.URL == "" || s.Webhook.ErrorURL == "") {
+		return fmt.Errorf("%w: webhook requires url and errorUrl", ErrInvalidSpec)
+	}
+	return nil
+}
+
+// WithStorage sets the storage resolving the object references of conversion specs.
+func WithStorage(storage Storage) ClientOption {
+	return func(c *Client) {
+		c.storage = storage
+	}
+}
+
+// FromSpec creates the request described by spec, with the defaults of its
+// profile applied. Files referenced by path or storage object are opened right
+// away and closed once the request is sent. Spec errors are returned by Send.
+func (c *Client) FromSpec(ctx context.Context, spec ConversionSpec) *Request {
+	spec, err := c.ResolveSpec(spec)
+	r := c.newRequest(ctx, spec.Route)
+	if err == nil {
+		err = spec.Validate()
+	}
+	if err != nil {
+		r.setErr(err)
+		return r
+	}
+
+	r.Tenant(spec.Tenant)
+	for key, value := range spec.Headers {
+		r.Header(key, value)
+	}
+	for key, value := range spec.Fields {
+		r.Param(key, value)
+	}
+	for key, value := range spec.Metadata {
+		r.Metadata(key, value)
+	}
+	for _, f := range spec.Files {
+		r.fileRef(f)
+	}
+	if p, ok := c.profiles[spec.Profile]; ok && p.Inputs != nil {
+		r.ValidateInputs(*p.Inputs)
+	}
+
+	if wh := spec.Webhook; wh != nil {
+		r.WebhookURL(wh.URL, methodOrPost(wh.Method)).
+			WebhookErrorURL(wh.ErrorURL, methodOrPost(wh.ErrorMethod))
+		for key, value := range wh.Headers {
+			r.WebhookHeader(key, value)
+		}
+	}
+	return r
+}
+
+// fileRef attaches the file referenced by f.
+func (r *Request) fileRef(f FileRef) {
+	key := f.Key
+	if key == "" {
+		key = FieldFiles
+	}
+
+	switch {
+	case f.URL != "":
+		r.DownloadFrom(f.URL, f.Headers)
+	case f.Data != nil:
+		r.File(key, f.Filename, bytes.NewReader(f.Data))
+	case f.Path != "":
+		file, err := os.Open(f.Path)
+		if err != nil {
+			r.setErr(err)
+			return
+		}
+		r.closers = append(r.closers, file)
+		r.File(key, f.Filename, file)
+	case f.Object != "":
+		if r.client.storage == nil {
+			r.setErr(fmt.Errorf("%w for object %s", ErrNoStorage, f.Object))
+			return
+		}
+		object, err := r.client.storage.DownloadFile(ContextWithTenant(r.ctx, r.tenant), f.Object)
+		if err != nil {
+			r.setErr(err)
+			return
+		}
+		r.closers = append(r.closers, object)
+		r.File(key, f.Filename, object)
+	}
+}
+
+func methodOrPost(method string) string {
+	if method == "" {
+		return "POST"
+	}
+	return method
+}
@@ -0,0 +1,113 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConversionSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ConversionSpec
+	}{
+		{"missing route", ConversionSpec{}},
+		{"no source", ConversionSpec{Route: ConvertHTML, Files: []FileRef{{Filename: "a.html"}}}},
+		{"two sources", ConversionSpec{Route: ConvertHTML, Files: []FileRef{{Filename: "a.html", Data: []byte("x"), Path: "a.html"}}}},
+		{"no filename", ConversionSpec{Route: ConvertHTML, Files: []FileRef{{Data: []byte("x")}}}},
+		{"webhook without error url", ConversionSpec{Route: ConvertHTML, Webhook: &WebhookSpec{URL: "http://hook"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate(); !errors.Is(err, ErrInvalidSpec) {
+				t.Errorf("expected ErrInvalidSpec, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFromSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "style.css")
+	if err := os.WriteFile(path, []byte("body{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	storage := newMemoryStorage()
+	storage.UploadFile(context.Background(), "jobs/logo.png", bytes.NewReader([]byte("png")), 3, "image/png")
+
+	spec := ConversionSpec{
+		Route:   ConvertHTML,
+		Fields:  map[string]string{FieldPaperWidth: "8.27"},
+		Headers: map[string]string{HeaderOutputFilename: "report"},
+		Files: []FileRef{
+			{Filename: FileIndexHTML, Data: []byte("<html></html>")},
+			{Filename: "style.css", Path: path},
+			{Filename: "logo.png", Object: "jobs/logo.png"},
+			{URL: "http://assets/font.woff2", Headers: map[string]string{"Authorization": "Bearer x"}},
+		},
+		Webhook: &WebhookSpec{URL: "http://hook/ok", ErrorURL: "http://hook/err"},
+	}
+
+	// specs survive a JSON round trip
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ConversionSpec
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	c, capture := newCaptureClient(t, WithStorage(storage))
+	if _, err := c.FromSpec(context.Background(), decoded).Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if capture.files[FileIndexHTML] != "<html></html>" || capture.files["style.css"] != "body{}" || capture.files["logo.png"] != "png" {
+		t.Errorf("unexpected files: %v", capture.files)
+	}
+	if got := capture.value(FieldPaperWidth); got != "8.27" {
+		t.Errorf("unexpected paper width %q", got)
+	}
+	if got := capture.header.Get(HeaderOutputFilename); got != "report" {
+		t.Errorf("unexpected output filename %q", got)
+	}
+	if got := capture.header.Get(HeaderWebhookURL); got != "http://hook/ok" {
+		t.Errorf("unexpected webhook url %q", got)
+	}
+	want := `[{"url":"http://assets/font.woff2","extraHttpHeaders":{"Authorization":"Bearer x"}}]`
+	if got := capture.value(FieldDownloadFrom); got != want {
+		t.Errorf("unexpected downloadFrom %s", got)
+	}
+}
+
+func TestFromSpecWithoutStorage(t *testing.T) {
+	c := newTestClient(t)
+	spec := ConversionSpec{
+		Route: ConvertHTML,
+		Files: []FileRef{{Filename: FileIndexHTML, Object: "jobs/index.html"}},
+	}
+	if _, err := c.FromSpec(context.Background(), spec).Send(); !errors.Is(err, ErrNoStorage) {
+		t.Errorf("expected ErrNoStorage, got %v", err)
+	}
+}
+
+func TestFromSpecWebhookHeaders(t *testing.T) {
+	c := newTestClient(t)
+	spec := ConversionSpec{
+		Route:   ConvertURL,
+		Fields:  map[string]string{FieldURL: "https://example.com"},
+		Headers: map[string]string{"gotenberg-webhook-url": "http://hook/ok"},
+	}
+	if _, err := c.FromSpec(context.Background(), spec).Send(); !errors.Is(err, ErrWebhookErrorURLMissing) {
+		t.Errorf("expected ErrWebhookErrorURLMissing, got %v", err)
+	}
+
+	spec.Headers[HeaderWebhookErrorURL] = "http://hook/err"
+	if _, err := c.FromSpec(context.Background(), spec).ConvertAndStore(newMemoryStorage(), "doc.pdf"); !errors.Is(err, ErrWebhookMode) {
+		t.Errorf("expected ErrWebhookMode, got %v", err)
+	}
+}
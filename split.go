@@ -0,0 +1,148 @@
+package gotenberg
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strconv"
+)
+
+// Split modes of the split route.
+const (
+	// SplitModeIntervals splits every splitSpan pages.
+	SplitModeIntervals = "intervals"
+	// SplitModePages extracts the page ranges of splitSpan, e.g. "1-3,5".
+	SplitModePages = "pages"
+)
+
+// SplitPDF creates a request splitting pdf into several PDFs with Gotenberg's
+// pdfengines split route, configured with Split:
+//
+//	resp, err := client.SplitPDF(ctx, pdf).
+//		Split(gotenberg.SplitModeIntervals, "2").
+//		Send()
+//	...
+//	err = resp.EachDocument(func(name string, pdf io.Reader) error { ... })
+//
+// Gotenberg answers with a zip of the PDFs, or with a single PDF when there is
+// only one, see Response.EachDocument.
+func (c *Client) SplitPDF(ctx context.Context, pdf io.Reader) *Request {
+	return c.newRequest(ctx, SplitPDF).File(FieldFiles, "document.pdf", pdf)
+}
+
+// Split sets how the PDF is split: span is the number of pages per document
+// for SplitModeIntervals, the page ranges to extract for SplitModePages, e.g.
+// "1-3,5".
+//
+// Besides SplitPDF, the Chromium and LibreOffice convert routes split the
+// converted PDF directly, saving a round trip:
+//
+//	resp, err := client.ConvertHTML(ctx, html).
+//		Split(gotenberg.SplitModeIntervals, "1").
+//		Send()
+//	...
+//	err = resp.EachDocument(func(name string, pdf io.Reader) error { ... })
+//
+// Other routes fail with ErrInvalidField.
+func (r *Request) Split(mode, span string) *Request {
+	switch mode {
+	case SplitModeIntervals:
+		if n, err := strconv.Atoi(span); err != nil || n < 1 {
+			r.setErr(fmt.Errorf("%w: split span %q is not a number of pages", ErrInvalidField, span))
+			return r
+		}
+	case SplitModePages:
+		if !pageRanges.MatchString(span) {
+			r.setErr(fmt.Errorf("%w: split mode %s requires page ranges such as \"1-3,5\", not %q", ErrInvalidField, mode, span))
+			return r
+		}
+	default:
+		r.setErr(fmt.Errorf("%w: unsupported split mode %q", ErrInvalidField, mode))
+		return r
+	}
+	r.splitMode = mode
+	return r.Param(FieldSplitMode, mode).Param(FieldSplitSpan, span)
+}
+
+// pageRanges matches a comma-separated list of pages and page ranges.
+var pageRanges = regexp.MustCompile(`^\s*\d+(\s*-\s*\d+)?(\s*,\s*\d+(\s*-\s*\d+)?)*\s*$`)
+
+// SplitUnify merges the extracted pages of SplitModePages into one PDF
+// instead of one PDF per page range.
+func (r *Request) SplitUnify(unify bool) *Request {
+	r.splitUnify = unify
+	return r.Bool(FieldSplitUnify, unify)
+}
+
+// validateSplit checks the split options against the route and each other.
+func (r *Request) validateSplit() error {
+	if r.splitMode == "" {
+		if r.splitUnify {
+			return fmt.Errorf("%w: SplitUnify requires Split with %s", ErrInvalidField, SplitModePages)
+		}
+		return nil
+	}
+	switch r.route {
+	case SplitPDF, ConvertHTML, ConvertURL, ConvertMarkdown, ConvertOffice:
+	default:
+		return fmt.Errorf("%w: route %s does not split documents", ErrInvalidField, r.route)
+	}
+	if r.splitUnify && r.splitMode != SplitModePages {
+		return fmt.Errorf("%w: SplitUnify requires split mode %s, not %s", ErrInvalidField, SplitModePages, r.splitMode)
+	}
+	return nil
+}
+
+// EachDocument calls fn with every document of the response: the files of a
+// zip answer, e.g. of SplitPDF or of conversions of several files, or the
+// response body itself, named after its Content-Disposition. The body is
+// closed; zip answers are spooled to a temporary file when too large for
+// memory. It returns the error of Err for failed conversions.
+func (r *Response) EachDocument(fn func(name string, content io.Reader) error) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/zip" {
+		return fn(r.filename(), r.Body)
+	}
+
+	body, err := spoolBody(r.Body, DefaultWebhookMemoryThreshold)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	// Spooled bodies are bytes.Readers or files
+	archive, err := zip.NewReader(body.ReadSeeker.(io.ReaderAt), body.size)
+	if err != nil {
+		return err
+	}
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		content, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(f.Name, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filename returns the filename of the Content-Disposition of the response,
+// "document" with the extension of its content type if there is none.
+func (r *Response) filename() string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		return params["filename"]
+	}
+	return "document" + resultExt(&WebhookResult{ContentType: r.Header.Get("Content-Type")})
+}
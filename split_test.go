@@ -0,0 +1,115 @@
+package gotenberg
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSplitPDF(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.SplitPDF(context.Background(), strings.NewReader("%PDF")).
+		Split(SplitModePages, "1-2,4").
+		SplitUnify(true).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.path != SplitPDF || capture.files["document.pdf"] != "%PDF" {
+		t.Errorf("unexpected request %s %v", capture.path, capture.files)
+	}
+	if capture.value(FieldSplitMode) != SplitModePages || capture.value(FieldSplitSpan) != "1-2,4" || capture.value(FieldSplitUnify) != "true" {
+		t.Errorf("unexpected split fields %v", capture.form.Value)
+	}
+
+	for _, split := range [][2]string{{SplitModeIntervals, "0"}, {SplitModeIntervals, "two"}, {SplitModePages, ""}, {"chapters", "1"}} {
+		_, err := c.SplitPDF(context.Background(), strings.NewReader("%PDF")).Split(split[0], split[1]).Send()
+		if !errors.Is(err, ErrInvalidField) {
+			t.Errorf("expected ErrInvalidField for %v, got %v", split, err)
+		}
+	}
+}
+
+func TestResponseEachDocument(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		if r.FormValue(FieldSplitMode) == "" {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+			w.Write([]byte("%PDF report"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		zw := zip.NewWriter(w)
+		for _, name := range []string{"document_0.pdf", "document_1.pdf"} {
+			f, _ := zw.Create(name)
+			f.Write([]byte("%PDF " + name))
+		}
+		zw.Close()
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documents := func(r *Request) map[string]string {
+		resp, err := r.Send()
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		got := make(map[string]string)
+		err = resp.EachDocument(func(name string, content io.Reader) error {
+			data, err := io.ReadAll(content)
+			got[name] = string(data)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("EachDocument failed: %v", err)
+		}
+		return got
+	}
+
+	split := documents(c.SplitPDF(context.Background(), strings.NewReader("%PDF")).Split(SplitModeIntervals, "1"))
+	if len(split) != 2 || split["document_0.pdf"] != "%PDF document_0.pdf" || split["document_1.pdf"] != "%PDF document_1.pdf" {
+		t.Errorf("unexpected split documents %v", split)
+	}
+	converted := documents(c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Split(SplitModeIntervals, "1"))
+	if len(converted) != 2 {
+		t.Errorf("unexpected split conversion documents %v", converted)
+	}
+	single := documents(c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")))
+	if len(single) != 1 || single["report.pdf"] != "%PDF report" {
+		t.Errorf("unexpected documents %v", single)
+	}
+}
+
+func TestSplitOnConvertRoutes(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	_, err := c.ConvertURL(context.Background(), "https://example.com").
+		Split(SplitModePages, "1-2, 5").
+		SplitUnify(true).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if capture.value(FieldSplitMode) != SplitModePages || capture.value(FieldSplitSpan) != "1-2, 5" || capture.value(FieldSplitUnify) != "true" {
+		t.Errorf("unexpected split fields %v", capture.form.Value)
+	}
+
+	for name, r := range map[string]*Request{
+		"screenshot route": c.ScreenshotURL(context.Background(), "https://example.com").Split(SplitModeIntervals, "1"),
+		"unify intervals":  c.ConvertURL(context.Background(), "https://example.com").Split(SplitModeIntervals, "1").SplitUnify(true),
+		"unify alone":      c.ConvertURL(context.Background(), "https://example.com").SplitUnify(true),
+		"bad page ranges":  c.ConvertURL(context.Background(), "https://example.com").Split(SplitModePages, "1-,x"),
+	} {
+		if err := r.Validate(); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("%s: expected ErrInvalidField, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gotenberg
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the conversions and buffers of a Client, e.g. for
+// dashboards of its memory behavior in production.
+type Stats struct {
+	// ActiveRequests is the number of conversions sent to Gotenberg whose
+	// response body is not closed yet.
+	ActiveRequests int64
+
+	// QueuedRequests is the number of conversions waiting for a slot of the
+	// concurrency limit, see WithConcurrencyLimit.
+	QueuedRequests int64
+
+	// ConcurrencyLimit is the current concurrency limit, zero without limit.
+	ConcurrencyLimit int
+
+	// UploadBytes is the number of file bytes sent by conversions still
+	// waiting for Gotenberg's response.
+	UploadBytes int64
+
+	// Buffers is the number of webhook documents and proxied conversions
+	// held in memory, and BufferedBytes their size.
+	Buffers       int64
+	BufferedBytes int64
+
+	// SpooledFiles is the number of webhook documents and proxied
+	// conversions too large for memory, held in temporary files.
+	SpooledFiles int64
+
+	// Conversions is the number of conversions whose response body was read
+	// or closed, and ConversionTimings the sum of their Timings, e.g. to
+	// compute where the latency of conversions comes from on average.
+	Conversions       int64
+	ConversionTimings Timings
+}
+
+// clientStats are the counters of Client.Stats.
+type clientStats struct {
+	active        atomic.Int64
+	uploadBytes   atomic.Int64
+	buffers       atomic.Int64
+	bufferedBytes atomic.Int64
+	spooledFiles  atomic.Int64
+
+	conversions atomic.Int64
+	queue       atomic.Int64
+	upload      atomic.Int64
+	server      atomic.Int64
+	download    atomic.Int64
+}
+
+// addTimings adds the timings of a conversion to the totals.
+func (s *clientStats) addTimings(t Timings) {
+	s.conversions.Add(1)
+	s.queue.Add(int64(t.Queue))
+	s.upload.Add(int64(t.Upload))
+	s.server.Add(int64(t.Server))
+	s.download.Add(int64(t.Download))
+}
+
+// Stats returns the current Stats of the client.
+func (c *Client) Stats() Stats {
+	var queued int64
+	var limit int
+	if c.limiter != nil {
+		queued = int64(c.limiter.queued())
+		limit, _ = c.limiter.current()
+	}
+	return Stats{
+		QueuedRequests:   queued,
+		ConcurrencyLimit: limit,
+		ActiveRequests:   c.stats.active.Load(),
+		UploadBytes:      c.stats.uploadBytes.Load(),
+		Buffers:          c.stats.buffers.Load(),
+		BufferedBytes:    c.stats.bufferedBytes.Load(),
+		SpooledFiles:     c.stats.spooledFiles.Load(),
+		Conversions:      c.stats.conversions.Load(),
+		ConversionTimings: Timings{
+			Queue:    time.Duration(c.stats.queue.Load()),
+			Upload:   time.Duration(c.stats.upload.Load()),
+			Server:   time.Duration(c.stats.server.Load()),
+			Download: time.Duration(c.stats.download.Load()),
+		},
+	}
+}
+
+// spoolBody spools body like spoolBody, counting it in the client Stats
+// until it is closed.
+func (c *Client) spoolBody(body io.Reader, threshold int64) (*spooledBody, error) {
+	s, err := spoolBody(body, threshold)
+	if err != nil {
+		return nil, err
+	}
+	if s.file != nil {
+		c.stats.spooledFiles.Add(1)
+		s.release = func() { c.stats.spooledFiles.Add(-1) }
+		return s, nil
+	}
+	size := s.size
+	c.stats.buffers.Add(1)
+	c.stats.bufferedBytes.Add(size)
+	s.release = func() {
+		c.stats.buffers.Add(-1)
+		c.stats.bufferedBytes.Add(-size)
+	}
+	return s, nil
+}
+
+// activeBody is the body of a response counted in ActiveRequests until it is closed.
+type activeBody struct {
+	io.ReadCloser
+	stats  *clientStats
+	closed atomic.Bool
+}
+
+func (b *activeBody) Close() error {
+	if b.closed.CompareAndSwap(false, true) {
+		b.stats.active.Add(-1)
+	}
+	return b.ReadCloser.Close()
+}
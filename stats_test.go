@@ -0,0 +1,79 @@
+package gotenberg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStatsActiveRequests(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		close(received)
+		<-release
+		w.Write([]byte("%PDF"))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	html := strings.Repeat("x", 1000)
+	sent := make(chan *Response, 1)
+	go func() {
+		resp, err := c.ConvertHTML(context.Background(), strings.NewReader(html)).Send()
+		if err != nil {
+			t.Error(err)
+		}
+		sent <- resp
+	}()
+
+	<-received
+	if got := c.Stats(); got.ActiveRequests != 1 || got.UploadBytes != int64(len(html)) {
+		t.Errorf("unexpected stats while converting %+v", got)
+	}
+	close(release)
+	resp := <-sent
+	if got := c.Stats(); got.ActiveRequests != 1 || got.UploadBytes != 0 {
+		t.Errorf("unexpected stats while reading the response %+v", got)
+	}
+	resp.Body.Close()
+	resp.Body.Close()
+	got := c.Stats()
+	if got.Conversions != 1 {
+		t.Errorf("unexpected conversions after closing the response %+v", got)
+	}
+	// Only the totals of finished conversions are left
+	got.Conversions, got.ConversionTimings = 0, Timings{}
+	if got != (Stats{}) {
+		t.Errorf("unexpected stats after closing the response %+v", got)
+	}
+}
+
+func TestStatsBuffers(t *testing.T) {
+	c := newTestClient(t)
+
+	small, err := c.spoolBody(strings.NewReader("small"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := c.spoolBody(strings.NewReader("larger than the threshold"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Stats(); got.Buffers != 1 || got.BufferedBytes != 5 || got.SpooledFiles != 1 {
+		t.Errorf("unexpected stats %+v", got)
+	}
+
+	small.Close()
+	large.Close()
+	if got := c.Stats(); got != (Stats{}) {
+		t.Errorf("unexpected stats after closing %+v", got)
+	}
+}
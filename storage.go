@@ -0,0 +1,45 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Storage is the object storage used by the storage-backed helpers.
+// MinioClient implements it; other backends can be plugged in by implementing
+// the same methods.
+type Storage interface {
+	UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error)
+	DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error)
+	GetFileInfo(ctx context.Context, objectName string) (minio.ObjectInfo, error)
+	DeleteFile(ctx context.Context, objectName string) error
+}
+
+// Lister is implemented by storages that list their objects, such as
+// MinioClient. Helpers that walk stored objects require it.
+type Lister interface {
+	// ListFiles lists the objects under prefix, recursively. Listing errors
+	// are reported in the Err field of the objects.
+	ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo
+}
+
+var (
+	_ Storage = (*MinioClient)(nil)
+	_ Lister  = (*MinioClient)(nil)
+)
+
+// listFiles lists the objects of storage if it is a Lister, and reports an
+// error otherwise.
+func listFiles(ctx context.Context, storage Storage, prefix string) <-chan minio.ObjectInfo {
+	lister, ok := storage.(Lister)
+	if !ok {
+		objects := make(chan minio.ObjectInfo, 1)
+		objects <- minio.ObjectInfo{Err: fmt.Errorf("gotenberg: storage %T cannot list objects", storage)}
+		close(objects)
+		return objects
+	}
+	return lister.ListFiles(ctx, prefix)
+}
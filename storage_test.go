@@ -0,0 +1,87 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// memoryStorage is an in-memory Storage for testing
+type memoryStorage struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	types map[string]string
+
+	modified map[string]time.Time
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: make(map[string][]byte), types: make(map[string]string), modified: make(map[string]time.Time)}
+}
+
+func (m *memoryStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[objectName] = data
+	m.types[objectName] = contentType
+	m.modified[objectName] = time.Now()
+	return &minio.UploadInfo{Key: objectName, Size: int64(len(data))}, nil
+}
+
+func (m *memoryStorage) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[objectName]
+	if !ok {
+		return nil, errNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *memoryStorage) GetFileInfo(ctx context.Context, objectName string) (minio.ObjectInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[objectName]
+	if !ok {
+		return minio.ObjectInfo{}, errNotFound
+	}
+	return minio.ObjectInfo{Key: objectName, Size: int64(len(data)), ContentType: m.types[objectName], LastModified: m.modified[objectName]}, nil
+}
+
+func (m *memoryStorage) DeleteFile(ctx context.Context, objectName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, objectName)
+	delete(m.types, objectName)
+	return nil
+}
+
+func (m *memoryStorage) ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var names []string
+	for name := range m.files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	objects := make(chan minio.ObjectInfo, len(names))
+	for _, name := range names {
+		objects <- minio.ObjectInfo{Key: name, Size: int64(len(m.files[name])), ContentType: m.types[name], LastModified: m.modified[name]}
+	}
+	close(objects)
+	return objects
+}
+
+var errNotFound = minio.ErrorResponse{Code: "NoSuchKey", StatusCode: 404, Message: "The specified key does not exist."}
@@ -0,0 +1,79 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TempSweeper removes intermediate objects left in storage, such as the files
+// MinioStager staged for webhook conversions or for a process that crashed
+// before releasing them. Every object under Prefix older than MaxAge is
+// removed.
+//
+//	sweeper := &gotenberg.TempSweeper{Storage: minioClient, Prefix: "staging", MaxAge: 24 * time.Hour}
+//	go sweeper.Run(ctx, time.Hour, nil)
+type TempSweeper struct {
+	// Storage holds the objects and must implement Lister.
+	Storage Storage
+	// Prefix holds the intermediate objects only. It must not be empty.
+	Prefix string
+	// MaxAge is the age from which an object is removed. It should exceed
+	// the time Gotenberg takes to fetch staged files, including the queue
+	// of webhook conversions.
+	MaxAge time.Duration
+
+	now func() time.Time
+}
+
+// Sweep removes the objects older than MaxAge once and returns their number.
+func (s *TempSweeper) Sweep(ctx context.Context) (int, error) {
+	if strings.Trim(s.Prefix, "/") == "" {
+		return 0, errors.New("gotenberg: sweeper prefix must not be empty")
+	}
+	lister, ok := s.Storage.(Lister)
+	if !ok {
+		return 0, fmt.Errorf("gotenberg: storage %T cannot list objects", s.Storage)
+	}
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	cutoff := now().Add(-s.MaxAge)
+
+	removed := 0
+	var errs []error
+	for object := range lister.ListFiles(ctx, strings.TrimSuffix(s.Prefix, "/")+"/") {
+		if object.Err != nil {
+			return removed, object.Err
+		}
+		if object.LastModified.After(cutoff) {
+			continue
+		}
+		if err := s.Storage.DeleteFile(ctx, object.Key); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+// Run calls Sweep every interval until ctx is done. Sweep errors are passed
+// to onError, if not nil.
+func (s *TempSweeper) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gotenberg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTempSweeper(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	now := time.Now()
+	for name, age := range map[string]time.Duration{
+		"staging/a/index.html": 48 * time.Hour,
+		"staging/b/big.css":    time.Hour,
+		"stagingold/keep.pdf":  48 * time.Hour,
+		"invoices/42.pdf":      48 * time.Hour,
+	} {
+		storage.UploadFile(ctx, name, strings.NewReader("x"), 1, "text/plain")
+		storage.modified[name] = now.Add(-age)
+	}
+
+	sweeper := &TempSweeper{Storage: storage, Prefix: "staging", MaxAge: 24 * time.Hour, now: func() time.Time { return now }}
+	removed, err := sweeper.Sweep(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 removed object, got %d", removed)
+	}
+	if _, ok := storage.files["staging/a/index.html"]; ok {
+		t.Error("expired object was not removed")
+	}
+	for _, name := range []string{"staging/b/big.css", "stagingold/keep.pdf", "invoices/42.pdf"} {
+		if _, ok := storage.files[name]; !ok {
+			t.Errorf("%s was removed", name)
+		}
+	}
+
+	if _, err := (&TempSweeper{Storage: storage, MaxAge: time.Hour}).Sweep(ctx); err == nil {
+		t.Error("expected error for an empty prefix")
+	}
+}
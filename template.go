@@ -0,0 +1,102 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Template is a template rendering HTML, such as *html/template.Template
+// or *text/template.Template.
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// ConvertTemplate creates a request to convert the HTML rendered by tmpl to PDF.
+// The template is executed while the request is sent and its output is streamed
+// directly into the index.html part, so the rendered document is never buffered as a whole.
+// Template errors abort the request and are returned by Send.
+//
+// html/template templates are executed as clones, so the same template can
+// serve plain and localized requests. When a locale is set with WithLocale or
+// Request.Locale, the FuncMap formatting functions of the clone are bound to
+// that locale; templates executed directly by their owner cannot be localized,
+// as html/template cannot clone executed templates.
+func (c *Client) ConvertTemplate(ctx context.Context, tmpl Template, data any) *Request {
+	t := newTemplateReader(ctx, tmpl, data)
+	t.locale = c.locale
+	r := c.ConvertHTML(ctx, t)
+	r.closers = append(r.closers, t)
+	r.template = t
+	t.panicked = r.panicked
+	return r
+}
+
+// templateReader executes a template into a pipe on first read.
+// Starting lazily keeps requests that are never sent from leaking a blocked goroutine.
+type templateReader struct {
+	ctx  context.Context
+	tmpl Template
+	data any
+
+	// locale binds the formatting functions before execution, if set
+	locale string
+
+	// panicked reports a panic of the execution, which fails the read
+	panicked func(recovered any) error
+
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+func newTemplateReader(ctx context.Context, tmpl Template, data any) *templateReader {
+	return &templateReader{ctx: ctx, tmpl: tmpl, data: data}
+}
+
+func (t *templateReader) Read(p []byte) (int, error) {
+	t.once.Do(t.start)
+	return t.pr.Read(p)
+}
+
+// Close stops the execution when the request is done, e.g. when Send failed
+// or Gotenberg answered before reading the whole page, so the template does
+// not block on the pipe forever.
+func (t *templateReader) Close() error {
+	t.once.Do(func() { t.pr, _ = io.Pipe() })
+	return t.pr.Close()
+}
+
+func (t *templateReader) start() {
+	pr, pw := io.Pipe()
+	t.pr = pr
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if p := recover(); p != nil {
+				err := fmt.Errorf("%w: %v", ErrPanic, p)
+				if t.panicked != nil {
+					err = t.panicked(p)
+				}
+				pw.CloseWithError(err)
+			}
+		}()
+		tmpl, err := localize(t.tmpl, t.locale)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(tmpl.Execute(pw, t.data))
+	}()
+
+	// Unblock the template if the request is abandoned mid-stream
+	go func() {
+		select {
+		case <-t.ctx.Done():
+			pw.CloseWithError(t.ctx.Err())
+		case <-done:
+		}
+	}()
+}
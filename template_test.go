@@ -0,0 +1,100 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertTemplate(t *testing.T) {
+	c, capture := newCaptureClient(t)
+	tmpl := template.Must(template.New("t").Parse(`<h1>{{.}}</h1>`))
+
+	_, err := c.ConvertTemplate(context.Background(), tmpl, "Invoice <1>").Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if got := capture.files[FileIndexHTML]; got != "<h1>Invoice &lt;1&gt;</h1>" {
+		t.Errorf("unexpected index.html %q", got)
+	}
+}
+
+type failingTemplate struct{}
+
+func (failingTemplate) Execute(w io.Writer, data any) error {
+	io.WriteString(w, "<h1>")
+	return errors.New("boom")
+}
+
+func TestTemplateReaderError(t *testing.T) {
+	r := newTemplateReader(context.Background(), failingTemplate{}, nil)
+	data, err := io.ReadAll(r)
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("expected template error, got %v", err)
+	}
+	if string(data) != "<h1>" {
+		t.Errorf("unexpected partial output %q", data)
+	}
+}
+
+type blockingTemplate struct{}
+
+func (blockingTemplate) Execute(w io.Writer, data any) error {
+	_, err := io.Copy(w, strings.NewReader(strings.Repeat("x", 1<<20)))
+	return err
+}
+
+func TestTemplateReaderCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := newTemplateReader(ctx, blockingTemplate{}, nil)
+	r.Read(make([]byte, 8))
+	cancel()
+
+	if _, err := io.ReadAll(r); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+// signalingTemplate writes a large page and reports when Execute returns.
+type signalingTemplate struct {
+	done chan error
+}
+
+func (s signalingTemplate) Execute(w io.Writer, data any) error {
+	_, err := io.Copy(w, strings.NewReader(strings.Repeat("x", 1<<20)))
+	s.done <- err
+	return err
+}
+
+// earlyAnswerRoundTripper reads the beginning of the request body and fails,
+// like a connection dropped mid-upload.
+type earlyAnswerRoundTripper struct{}
+
+func (earlyAnswerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	io.ReadFull(req.Body, make([]byte, 1024))
+	return nil, errors.New("connection reset")
+}
+
+func TestTemplateStopsWhenSendFails(t *testing.T) {
+	c, err := NewClient(&http.Client{Transport: earlyAnswerRoundTripper{}}, "http://localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := signalingTemplate{done: make(chan error, 1)}
+	if _, err := c.ConvertTemplate(context.Background(), tmpl, nil).Send(); err == nil {
+		t.Fatal("expected Send to fail")
+	}
+	select {
+	case err := <-tmpl.done:
+		if err == nil {
+			t.Error("template execution completed without a reader")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("template execution still blocked after Send failed")
+	}
+}
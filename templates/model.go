@@ -0,0 +1,102 @@
+package templates
+
+import "time"
+
+// Party is a sender or recipient of a document.
+type Party struct {
+	Name    string
+	Address []string
+	Email   string
+	Phone   string
+}
+
+// LineItem is a billed position of an invoice.
+type LineItem struct {
+	Description string
+	Quantity    float64
+	UnitPrice   float64
+}
+
+// Amount returns the line total.
+func (i LineItem) Amount() float64 {
+	return i.Quantity * i.UnitPrice
+}
+
+// InvoiceData is the data rendered by the Invoice template.
+type InvoiceData struct {
+	Number   string
+	IssuedAt time.Time
+	DueAt    time.Time
+	Currency string
+
+	From Party
+	To   Party
+
+	Items   []LineItem
+	TaxRate float64 // e.g. 0.2 for 20%
+
+	PaymentTerms string
+	Notes        string
+
+	// Logo is the file name of an image attached to the request, optional.
+	Logo string
+}
+
+// Subtotal returns the sum of all line items.
+func (d InvoiceData) Subtotal() float64 {
+	var total float64
+	for _, item := range d.Items {
+		total += item.Amount()
+	}
+	return total
+}
+
+// Tax returns the tax amount.
+func (d InvoiceData) Tax() float64 {
+	return d.Subtotal() * d.TaxRate
+}
+
+// TaxPercent returns the tax rate in percent.
+func (d InvoiceData) TaxPercent() float64 {
+	return d.TaxRate * 100
+}
+
+// Total returns the subtotal including tax.
+func (d InvoiceData) Total() float64 {
+	return d.Subtotal() + d.Tax()
+}
+
+// Table is a simple table of text cells.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Section is a titled part of a report.
+type Section struct {
+	Heading    string
+	Paragraphs []string
+	Table      *Table
+}
+
+// ReportData is the data rendered by the Report template.
+type ReportData struct {
+	Title    string
+	Subtitle string
+	Author   string
+	Date     time.Time
+	Summary  string
+	Sections []Section
+}
+
+// LetterData is the data rendered by the Letter template.
+type LetterData struct {
+	From       Party
+	To         Party
+	Date       time.Time
+	Subject    string
+	Salutation string
+	Paragraphs []string
+	Closing    string
+	Signature  string
+}
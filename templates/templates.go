@@ -0,0 +1,32 @@
+// Package templates provides parameterized HTML templates for common business
+// documents (invoices, reports, letters) with typed data models.
+// The templates can be passed directly to gotenberg.Client.ConvertTemplate,
+// which formats dates and amounts for the locale set with gotenberg.WithLocale
+// or Request.Locale (en-US by default).
+package templates
+
+import (
+	"embed"
+	"html/template"
+
+	gotenberg "github.com/nativebpm/gotenberg-client"
+)
+
+//go:embed *.html
+var files embed.FS
+
+var (
+	// Invoice renders an Invoice.
+	Invoice = parse("invoice.html")
+
+	// Report renders a Report.
+	Report = parse("report.html")
+
+	// Letter renders a Letter.
+	Letter = parse("letter.html")
+)
+
+// parse parses a document template together with the shared partials.
+func parse(name string) *template.Template {
+	return template.Must(template.New(name).Funcs(gotenberg.FuncMap(gotenberg.DefaultLocale)).ParseFS(files, name, "party.html"))
+}
@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvoice(t *testing.T) {
+	data := InvoiceData{
+		Number:   "INV-7",
+		IssuedAt: time.Date(2024, time.May, 3, 0, 0, 0, 0, time.UTC),
+		Currency: "EUR",
+		From:     Party{Name: "Seller GmbH", Address: []string{"Hauptstr. 1", "10115 Berlin"}},
+		To:       Party{Name: "Buyer Ltd", Email: "ap@buyer.example"},
+		Items: []LineItem{
+			{Description: "Consulting", Quantity: 2, UnitPrice: 100},
+			{Description: "Travel", Quantity: 1, UnitPrice: 50},
+		},
+		TaxRate: 0.19,
+	}
+
+	var buf bytes.Buffer
+	if err := Invoice.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	for _, want := range []string{"INV-7", "May 3, 2024", "Hauptstr. 1", "€250.00", "Tax (19%)", "€47.50", "€297.50", `lang="en-US"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("invoice does not contain %q", want)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	data := ReportData{
+		Title: "Quarterly report",
+		Sections: []Section{{
+			Heading:    "Revenue",
+			Paragraphs: []string{"Revenue grew."},
+			Table:      &Table{Columns: []string{"Quarter", "Revenue"}, Rows: [][]string{{"Q1", "10"}}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := Report.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<th>Quarter</th>") {
+		t.Error("report table not rendered")
+	}
+}
+
+func TestLetter(t *testing.T) {
+	data := LetterData{
+		From:       Party{Name: "Alice"},
+		To:         Party{Name: "Bob", Address: []string{"1 Main St"}},
+		Subject:    "Hello",
+		Paragraphs: []string{"First.", "Second."},
+		Closing:    "Regards,",
+		Signature:  "Alice",
+	}
+
+	var buf bytes.Buffer
+	if err := Letter.Execute(&buf, data); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "1 Main St") || !strings.Contains(buf.String(), "<p>Second.</p>") {
+		t.Error("letter not rendered")
+	}
+}
@@ -0,0 +1,245 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ErrInvalidBucketName is returned for bucket names that do not follow the
+// S3 naming rules.
+var ErrInvalidBucketName = errors.New("gotenberg: invalid bucket name")
+
+type tenantKey struct{}
+
+// ContextWithTenant labels ctx with the tenant a storage operation is performed
+// for, see TenantBuckets. The storage helpers label their contexts with the
+// tenant of their request, spec or job.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant label of ctx, if any.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// TenantBucketName returns the bucket of tenant: prefix followed by the tenant
+// lowercased, with the characters S3 does not allow replaced by '-'. Names
+// that had to be changed or shortened get a hash of the tenant appended, so
+// distinct tenants never share a bucket.
+func TenantBucketName(prefix, tenant string) (string, error) {
+	if tenant == "" {
+		return "", fmt.Errorf("%w: empty tenant", ErrInvalidBucketName)
+	}
+	var b strings.Builder
+	for _, c := range strings.ToLower(tenant) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	name := prefix + b.String()
+	if b.String() != tenant || len(name) < 3 || len(name) > 63 {
+		sum := sha256.Sum256([]byte(tenant))
+		suffix := "-" + hex.EncodeToString(sum[:4])
+		name = strings.Trim(name[:min(len(name), 63-len(suffix))], "-.") + suffix
+	}
+	if err := validBucketName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// validBucketName checks the S3 bucket naming rules.
+func validBucketName(name string) error {
+	if len(name) < 3 || len(name) > 63 {
+		return fmt.Errorf("%w: %q must have 3 to 63 characters", ErrInvalidBucketName, name)
+	}
+	for i, c := range name {
+		alnum := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+		if !alnum && (c != '-' && c != '.' || i == 0 || i == len(name)-1) {
+			return fmt.Errorf("%w: %q", ErrInvalidBucketName, name)
+		}
+	}
+	if strings.Contains(name, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidBucketName, name)
+	}
+	return nil
+}
+
+// TenantBuckets is a Storage keeping the objects of every tenant in a bucket
+// of its own, named by TenantBucketName and created on first use. Objects of
+// contexts without a tenant label are kept in the bucket of Minio.
+//
+// The Pipeline, SaveToStorage, Request.ConvertAndStore and the object
+// references of conversion specs label their contexts with their tenant, so their objects
+// land in the tenant's bucket without further configuration:
+//
+//	storage := &gotenberg.TenantBuckets{Minio: minioClient, Prefix: "docs-", Quota: 10 << 30}
+//	client, err := gotenberg.NewClient(httpClient, url, gotenberg.WithStorage(storage))
+type TenantBuckets struct {
+	// Minio provides the connection and the bucket of contexts without tenant.
+	Minio *MinioClient
+
+	// Prefix starts the name of every tenant bucket.
+	Prefix string
+
+	// Policy returns the bucket policy set on a new tenant bucket, e.g. to
+	// grant a tenant role access to it. Optional.
+	Policy func(tenant, bucket string) string
+
+	// Quota is the number of bytes a tenant bucket may hold. Uploads
+	// exceeding it fail with ErrQuotaExceeded. The usage is computed by
+	// listing the bucket, so large buckets make uploads slower. Zero
+	// disables the quota.
+	Quota int64
+
+	// ensured holds the buckets known to exist
+	ensured sync.Map
+}
+
+var (
+	_ Storage = (*TenantBuckets)(nil)
+	_ Lister  = (*TenantBuckets)(nil)
+)
+
+// EnsureBucket creates the bucket of tenant, with its policy, unless it
+// exists, and returns its name.
+func (t *TenantBuckets) EnsureBucket(ctx context.Context, tenant string) (string, error) {
+	bucket, err := TenantBucketName(t.Prefix, tenant)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := t.ensured.Load(bucket); ok {
+		return bucket, nil
+	}
+
+	client := t.Minio.client
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+			return "", fmt.Errorf("gotenberg: create bucket %s for tenant %s: %w", bucket, tenant, err)
+		}
+		if t.Policy != nil {
+			if err := client.SetBucketPolicy(ctx, bucket, t.Policy(tenant, bucket)); err != nil {
+				return "", fmt.Errorf("gotenberg: set policy of bucket %s: %w", bucket, err)
+			}
+		}
+	}
+	t.ensured.Store(bucket, struct{}{})
+	return bucket, nil
+}
+
+// Usage returns the number of bytes stored in the bucket of tenant.
+func (t *TenantBuckets) Usage(ctx context.Context, tenant string) (int64, error) {
+	storage, err := t.storage(ContextWithTenant(ctx, tenant))
+	if err != nil {
+		return 0, err
+	}
+	var usage int64
+	for object := range storage.ListFiles(ctx, "") {
+		if object.Err != nil {
+			return 0, object.Err
+		}
+		usage += object.Size
+	}
+	return usage, nil
+}
+
+// storage returns the bucket of the tenant of ctx.
+func (t *TenantBuckets) storage(ctx context.Context) (*MinioClient, error) {
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		return t.Minio, nil
+	}
+	bucket, err := t.EnsureBucket(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return &MinioClient{client: t.Minio.client, bucketName: bucket}, nil
+}
+
+// UploadFile implements Storage, enforcing the quota of the tenant bucket.
+func (t *TenantBuckets) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenant := TenantFromContext(ctx); tenant != "" && t.Quota > 0 {
+		usage, err := t.Usage(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+		if usage+max(size, 0) > t.Quota || usage >= t.Quota {
+			return nil, fmt.Errorf("%w: bucket %s of tenant %s holds %d of %d bytes", ErrQuotaExceeded, storage.bucketName, tenant, usage, t.Quota)
+		}
+	}
+	return storage.UploadFile(ctx, objectName, reader, size, contentType)
+}
+
+// DownloadFile implements Storage.
+func (t *TenantBuckets) DownloadFile(ctx context.Context, objectName string) (io.ReadCloser, error) {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return storage.DownloadFile(ctx, objectName)
+}
+
+// GetFileInfo implements Storage.
+func (t *TenantBuckets) GetFileInfo(ctx context.Context, objectName string) (minio.ObjectInfo, error) {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+	return storage.GetFileInfo(ctx, objectName)
+}
+
+// DeleteFile implements Storage.
+func (t *TenantBuckets) DeleteFile(ctx context.Context, objectName string) error {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		return err
+	}
+	return storage.DeleteFile(ctx, objectName)
+}
+
+// ListFiles implements Lister.
+func (t *TenantBuckets) ListFiles(ctx context.Context, prefix string) <-chan minio.ObjectInfo {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		objects := make(chan minio.ObjectInfo, 1)
+		objects <- minio.ObjectInfo{Err: err}
+		close(objects)
+		return objects
+	}
+	return storage.ListFiles(ctx, prefix)
+}
+
+// PresignedURL returns a presigned URL of an object of the tenant of ctx, see
+// MinioClient.PresignedURL.
+func (t *TenantBuckets) PresignedURL(ctx context.Context, objectName string, expiry time.Duration, filename string) (*url.URL, error) {
+	storage, err := t.storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return storage.PresignedURL(ctx, objectName, expiry, filename)
+}
@@ -0,0 +1,96 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestTenantBucketName(t *testing.T) {
+	tests := []struct {
+		prefix, tenant, want string
+	}{
+		{"docs-", "acme", "docs-acme"},
+		{"", "acme42", "acme42"},
+		{"docs-", "Acme", "docs-acme-"},
+		{"docs-", "acme_corp", "docs-acme-corp-"},
+		{"", "ab", "ab-"},
+		{"", "_x", "x-"},
+		{"docs-", strings.Repeat("a", 80), "docs-aaaa"},
+	}
+	for _, tt := range tests {
+		got, err := TenantBucketName(tt.prefix, tt.tenant)
+		if err != nil {
+			t.Errorf("%q: %v", tt.tenant, err)
+			continue
+		}
+		if !strings.HasPrefix(got, tt.want) || len(got) > 63 || validBucketName(got) != nil {
+			t.Errorf("%q: unexpected bucket %q", tt.tenant, got)
+		}
+	}
+
+	// Tenants differing only by characters S3 does not allow get distinct buckets
+	a, _ := TenantBucketName("docs-", "Acme")
+	b, _ := TenantBucketName("docs-", "ACME")
+	if a == b {
+		t.Errorf("tenants share bucket %q", a)
+	}
+
+	if _, err := TenantBucketName("docs-", ""); !errors.Is(err, ErrInvalidBucketName) {
+		t.Errorf("expected ErrInvalidBucketName, got %v", err)
+	}
+	if _, err := TenantBucketName("-", "acme"); !errors.Is(err, ErrInvalidBucketName) {
+		t.Errorf("expected ErrInvalidBucketName for a leading hyphen, got %v", err)
+	}
+}
+
+// tenantStorage records the tenant labels of uploads
+type tenantStorage struct {
+	*memoryStorage
+	mu      sync.Mutex
+	tenants map[string]string
+}
+
+func (s *tenantStorage) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (*minio.UploadInfo, error) {
+	s.mu.Lock()
+	s.tenants[objectName] = TenantFromContext(ctx)
+	s.mu.Unlock()
+	return s.memoryStorage.UploadFile(ctx, objectName, reader, size, contentType)
+}
+
+func TestStorageTenantLabel(t *testing.T) {
+	storage := &tenantStorage{memoryStorage: newMemoryStorage(), tenants: make(map[string]string)}
+
+	c := newTestClient(t)
+	if _, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		Tenant("acme").
+		ConvertAndStore(storage, "invoices/42.pdf"); err != nil {
+		t.Fatalf("ConvertAndStore failed: %v", err)
+	}
+
+	profiles := Profiles{"invoice": {PostProcess: []PostStep{{Action: StepStore, Key: "raw/{job}{ext}"}}}}
+	pc, err := NewClient(c.httpClient, "http://localhost", WithProfiles(profiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline := &Pipeline{Client: pc, Storage: storage}
+	job := Job{ID: "job1", Spec: ConversionSpec{Profile: "invoice", Tenant: "globex"}, CreatedAt: time.Now()}
+	result := &WebhookResult{Trace: "job1", ContentType: "application/pdf", Size: 3, Body: bytes.NewReader([]byte("pdf"))}
+	if err := pipeline.Consume(context.Background(), job, result); err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	if storage.tenants["invoices/42.pdf"] != "acme" || storage.tenants["raw/job1.pdf"] != "globex" {
+		t.Errorf("unexpected tenant labels %v", storage.tenants)
+	}
+	if TenantFromContext(ContextWithTenant(context.Background(), "")) != "" {
+		t.Error("expected no tenant label")
+	}
+}
@@ -0,0 +1,130 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// DefaultPDFJSURL is the pdf.js build used by GotenbergThumbnailRenderer and
+// PageNumberStamper when they are not given one. It is a public CDN: the
+// Chromium of Gotenberg must have internet access to load it, and without it
+// thumbnails and stamping fail. Deployments without internet access must host
+// pdf.js where Gotenberg can reach it and set PDFJSURL.
+const DefaultPDFJSURL = "https://cdnjs.cloudflare.com/ajax/libs/pdf.js/3.11.174"
+
+// ThumbnailRenderer rasterizes the first page of a PDF document into a PNG image.
+type ThumbnailRenderer interface {
+	RenderThumbnail(ctx context.Context, pdf io.Reader) (io.ReadCloser, error)
+}
+
+// ThumbnailName returns the deterministic object name of the thumbnail
+// generated for objectName.
+func ThumbnailName(objectName string) string {
+	return objectName + ".thumb.png"
+}
+
+// GenerateThumbnail renders the first page of the stored PDF objectName
+// and stores the PNG thumbnail next to it under ThumbnailName(objectName).
+// Existing thumbnails are overwritten. Returns the thumbnail object name.
+func GenerateThumbnail(ctx context.Context, storage Storage, renderer ThumbnailRenderer, objectName string) (string, error) {
+	pdf, err := storage.DownloadFile(ctx, objectName)
+	if err != nil {
+		return "", err
+	}
+	defer pdf.Close()
+
+	thumb, err := renderer.RenderThumbnail(ctx, pdf)
+	if err != nil {
+		return "", err
+	}
+	defer thumb.Close()
+
+	name := ThumbnailName(objectName)
+	if _, err := storage.UploadFile(ctx, name, thumb, -1, "image/png"); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// GotenbergThumbnailRenderer renders thumbnails with the Gotenberg screenshot route.
+// The PDF is embedded into an HTML viewer page that draws the first page
+// with pdf.js, which is then captured as a PNG screenshot.
+type GotenbergThumbnailRenderer struct {
+	Client *Client
+
+	// Width is the thumbnail width in pixels, 400 if zero.
+	// The height follows the ISO 216 page ratio.
+	Width int
+
+	// PDFJSURL is the base URL of the pdf.js build, DefaultPDFJSURL if
+	// empty, which Gotenberg loads from the internet.
+	PDFJSURL string
+}
+
+// RenderThumbnail implements ThumbnailRenderer.
+func (g *GotenbergThumbnailRenderer) RenderThumbnail(ctx context.Context, pdf io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	width := g.Width
+	if width == 0 {
+		width = 400
+	}
+	height := int(math.Ceil(float64(width) * math.Sqrt2))
+
+	pdfjs := g.PDFJSURL
+	if pdfjs == "" {
+		pdfjs = DefaultPDFJSURL
+	}
+
+	resp, err := g.Client.ScreenshotHTML(ctx, strings.NewReader(thumbnailViewer(pdfjs, width, data))).
+		ScreenshotWidth(width).
+		ScreenshotHeight(height).
+		Param(FieldWaitForExpression, "window.thumbnailReady === true").
+		Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resp.Err(); err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// thumbnailViewer returns an HTML page drawing the first page of pdf scaled to width pixels.
+func thumbnailViewer(pdfjs string, width int, pdf []byte) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<!DOCTYPE html>
+<html>
+<head>
+<style>html, body { margin: 0; background: #fff; } canvas { display: block; }</style>
+<script src="%[1]s/pdf.min.js"></script>
+</head>
+<body>
+<canvas id="page"></canvas>
+<script>
+pdfjsLib.GlobalWorkerOptions.workerSrc = "%[1]s/pdf.worker.min.js";
+pdfjsLib.getDocument({ data: atob("%[2]s") }).promise
+	.then(function (doc) { return doc.getPage(1); })
+	.then(function (page) {
+		var viewport = page.getViewport({ scale: %[3]d / page.getViewport({ scale: 1 }).width });
+		var canvas = document.getElementById("page");
+		canvas.width = viewport.width;
+		canvas.height = viewport.height;
+		return page.render({ canvasContext: canvas.getContext("2d"), viewport: viewport }).promise;
+	})
+	.then(function () { window.thumbnailReady = true; });
+</script>
+</body>
+</html>`, pdfjs, base64.StdEncoding.EncodeToString(pdf), width)
+	return b.String()
+}
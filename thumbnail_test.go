@@ -0,0 +1,58 @@
+package gotenberg
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+type stubRenderer struct{}
+
+func (stubRenderer) RenderThumbnail(ctx context.Context, pdf io.Reader) (io.ReadCloser, error) {
+	data, err := io.ReadAll(pdf)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader("png:" + string(data))), nil
+}
+
+func TestGenerateThumbnail(t *testing.T) {
+	ctx := context.Background()
+	storage := newMemoryStorage()
+	storage.UploadFile(ctx, "docs/invoice.pdf", strings.NewReader("pdf"), 3, "application/pdf")
+
+	name, err := GenerateThumbnail(ctx, storage, stubRenderer{}, "docs/invoice.pdf")
+	if err != nil {
+		t.Fatalf("GenerateThumbnail failed: %v", err)
+	}
+	if name != "docs/invoice.pdf.thumb.png" {
+		t.Errorf("unexpected thumbnail name %q", name)
+	}
+	if got := string(storage.files[name]); got != "png:pdf" {
+		t.Errorf("unexpected thumbnail content %q", got)
+	}
+	if storage.types[name] != "image/png" {
+		t.Errorf("unexpected content type %q", storage.types[name])
+	}
+}
+
+func TestGenerateThumbnailMissingObject(t *testing.T) {
+	_, err := GenerateThumbnail(context.Background(), newMemoryStorage(), stubRenderer{}, "missing.pdf")
+	if err == nil {
+		t.Fatal("expected error for missing object")
+	}
+}
+
+func TestGotenbergThumbnailRenderer(t *testing.T) {
+	renderer := &GotenbergThumbnailRenderer{Client: newTestClient(t)}
+	img, err := renderer.RenderThumbnail(context.Background(), strings.NewReader("%PDF-1.7"))
+	if err != nil {
+		t.Fatalf("RenderThumbnail failed: %v", err)
+	}
+	defer img.Close()
+	data, _ := io.ReadAll(img)
+	if string(data) != "pdf-bytes" {
+		t.Errorf("unexpected image %q", data)
+	}
+}
@@ -0,0 +1,125 @@
+package gotenberg
+
+import (
+	"errors"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings break down the latency of a conversion, see Response.Timings,
+// Result.Timings and Stats.ConversionTimings.
+type Timings struct {
+	// Queue is the time spent waiting for a slot of the concurrency limit.
+	Queue time.Duration `json:"queue"`
+	// Upload is the time from sending the request to the end of its body,
+	// connecting to Gotenberg included.
+	Upload time.Duration `json:"upload"`
+	// Server is the time from the end of the request to the first byte of the
+	// response: the conversion in Gotenberg.
+	Server time.Duration `json:"server"`
+	// Download is the time from the first byte of the response to the end of
+	// its body, zero until the body is read or closed.
+	Download time.Duration `json:"download"`
+}
+
+// requestTimings records the events of a conversion. The events of the
+// connection are reported by the HTTP transport, from its own goroutines.
+type requestTimings struct {
+	mu     sync.Mutex
+	queued time.Time
+	sent   time.Time
+	// wrote and firstByte are zero with transports not reporting them,
+	// e.g. custom RoundTrippers
+	wrote     time.Time
+	firstByte time.Time
+	answered  time.Time
+	done      time.Time
+}
+
+// clientTrace returns the httptrace hooks recording the end of the upload
+// and the first byte of the response.
+func (t *requestTimings) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wrote) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+func (t *requestTimings) mark(event *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if event.IsZero() {
+		*event = time.Now()
+	}
+}
+
+// timings returns the Timings recorded so far. Without transport events the
+// upload is reported as part of Server.
+func (t *requestTimings) timings() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sent.IsZero() {
+		return Timings{}
+	}
+	firstByte := t.firstByte
+	if firstByte.IsZero() {
+		firstByte = t.answered
+	}
+	wrote := t.wrote
+	switch {
+	case wrote.IsZero():
+		wrote = t.sent
+	case wrote.After(firstByte):
+		// Gotenberg answered before reading the whole request, e.g. to reject it
+		wrote = firstByte
+	}
+	timings := Timings{
+		Queue:  t.sent.Sub(t.queued),
+		Upload: wrote.Sub(t.sent),
+		Server: firstByte.Sub(wrote),
+	}
+	if !t.done.IsZero() {
+		timings.Download = t.done.Sub(firstByte)
+	}
+	return timings
+}
+
+// Timings returns the latency breakdown of the conversion. Download is set
+// once the body is read to its end or closed.
+func (r *Response) Timings() Timings {
+	if r.timings == nil {
+		return Timings{}
+	}
+	return r.timings.timings()
+}
+
+// timedBody is a response body recording the end of the download, and adding
+// the timings of the conversion to the client Stats.
+type timedBody struct {
+	io.ReadCloser
+	timings *requestTimings
+	stats   *clientStats
+	once    sync.Once
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *timedBody) finish() {
+	b.once.Do(func() {
+		b.timings.mark(&b.timings.done)
+		b.stats.addTimings(b.timings.timings())
+	})
+}
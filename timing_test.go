@@ -0,0 +1,57 @@
+package gotenberg
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.7"))
+	}))
+	defer srv.Close()
+	c, err := NewClient(srv.Client(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	storage := newMemoryStorage()
+	result, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		ConvertAndStore(storage, "doc.pdf")
+	if err != nil {
+		t.Fatalf("ConvertAndStore failed: %v", err)
+	}
+	timings := result.Timings
+	if timings.Server < 50*time.Millisecond || timings.Upload < 0 || timings.Download < 0 || timings.Queue < 0 {
+		t.Errorf("unexpected timings %+v", timings)
+	}
+	if total := timings.Queue + timings.Upload + timings.Server + timings.Download; total > result.Duration {
+		t.Errorf("timings %+v exceed the duration %v", timings, result.Duration)
+	}
+
+	stats := c.Stats()
+	if stats.Conversions != 1 || stats.ConversionTimings != timings {
+		t.Errorf("stats report %d conversions with %+v, want 1 with %+v", stats.Conversions, stats.ConversionTimings, timings)
+	}
+}
+
+func TestTimingsWithoutTransportEvents(t *testing.T) {
+	c, _ := newCaptureClient(t)
+	resp, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if timings := resp.Timings(); timings.Upload != 0 || timings.Server < 0 || timings.Download < 0 {
+		t.Errorf("unexpected timings %+v", timings)
+	}
+}
@@ -0,0 +1,187 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TraceChecker reports whether a webhook callback belongs to a conversion
+// submitted by this application, see RequireKnownTrace.
+type TraceChecker interface {
+	KnownTrace(ctx context.Context, trace string) (bool, error)
+}
+
+// TraceForgetter is a TraceChecker that can forget a trace, see RequireKnownTrace.
+type TraceForgetter interface {
+	TraceChecker
+	Forget(trace string)
+}
+
+// RequireKnownTrace wraps a webhook handler so that it only serves callbacks
+// whose Gotenberg trace is known to checker. Other callbacks are answered with 404.
+// If checker is a TraceForgetter, the trace is forgotten once next answered
+// the callback with a 2xx status, so a replayed callback is rejected.
+func RequireKnownTrace(checker TraceChecker, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace := r.Header.Get(HeaderGotenbergTrace)
+		known := false
+		if trace != "" {
+			var err error
+			if known, err = checker.KnownTrace(r.Context(), trace); err != nil {
+				WriteError(w, r, err)
+				return
+			}
+		}
+		if !known {
+			writeErrorResponse(w, r, http.StatusNotFound, "Unknown trace")
+			return
+		}
+		forgetter, ok := checker.(TraceForgetter)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		if sw.status >= 200 && sw.status < 300 {
+			forgetter.Forget(trace)
+		}
+	})
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusWriter) WriteHeader(status int) {
+	if !s.wroteHeader {
+		s.status = status
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusWriter) Write(p []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(p)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (s *statusWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// DefaultTraceTTL is the time a TraceAllowlist keeps a trace unless
+// configured otherwise, see NewTraceAllowlist.
+const DefaultTraceTTL = 24 * time.Hour
+
+// TraceAllowlist is an in-process TraceForgetter. With WithTraceAllowlist, the
+// client registers the trace of every webhook conversion before sending it.
+type TraceAllowlist struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu     sync.Mutex
+	traces map[string]time.Time
+	pruned time.Time
+}
+
+// NewTraceAllowlist creates a TraceAllowlist forgetting traces ttl after
+// their registration, or DefaultTraceTTL after if ttl is not positive.
+func NewTraceAllowlist(ttl time.Duration) *TraceAllowlist {
+	if ttl <= 0 {
+		ttl = DefaultTraceTTL
+	}
+	return &TraceAllowlist{
+		ttl:    ttl,
+		now:    time.Now,
+		traces: make(map[string]time.Time),
+	}
+}
+
+// Register adds trace to the allowlist.
+func (a *TraceAllowlist) Register(trace string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	// Expired traces are dropped once per ttl, which bounds the list to the
+	// traces registered within twice the ttl
+	if now.Sub(a.pruned) > a.ttl {
+		for t, registered := range a.traces {
+			if a.expired(registered, now) {
+				delete(a.traces, t)
+			}
+		}
+		a.pruned = now
+	}
+	a.traces[trace] = now
+}
+
+// Forget removes trace from the allowlist, e.g. once its callback was handled.
+func (a *TraceAllowlist) Forget(trace string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.traces, trace)
+}
+
+// KnownTrace implements TraceChecker.
+func (a *TraceAllowlist) KnownTrace(ctx context.Context, trace string) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	registered, ok := a.traces[trace]
+	return ok && !a.expired(registered, a.now()), nil
+}
+
+func (a *TraceAllowlist) expired(registered, now time.Time) bool {
+	return now.Sub(registered) > a.ttl
+}
+
+// JobTraces is a TraceChecker accepting the traces of the pending jobs in
+// Store, which can be shared by several processes. Outbox jobs use their ID
+// as trace, and a job is no longer pending once its callback was handled.
+type JobTraces struct {
+	Store JobStore
+}
+
+// KnownTrace implements TraceChecker.
+func (j JobTraces) KnownTrace(ctx context.Context, trace string) (bool, error) {
+	job, err := j.Store.Get(ctx, trace)
+	if errors.Is(err, ErrJobNotFound) {
+		return false, nil
+	}
+	return err == nil && job.Status == JobPending, err
+}
+
+// WithTraceAllowlist makes the client register the trace of every conversion
+// sent in webhook mode with allowlist. Requests without a trace get a random one.
+func WithTraceAllowlist(allowlist *TraceAllowlist) ClientOption {
+	return func(c *Client) {
+		c.traces = allowlist
+	}
+}
+
+// Trace sets the Gotenberg trace of the conversion, which Gotenberg
+// passes back with the webhook callbacks.
+func (r *Request) Trace(trace string) *Request {
+	r.trace = trace
+	r.req.Header(HeaderGotenbergTrace, trace)
+	return r
+}
+
+// registerTrace registers the trace of a webhook conversion with the client's allowlist.
+func (r *Request) registerTrace() {
+	if r.client.traces == nil || r.webhookURL == "" {
+		return
+	}
+	if r.trace == "" {
+		r.Trace(newJobID())
+	}
+	r.client.traces.Register(r.trace)
+}
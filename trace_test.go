@@ -0,0 +1,104 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTraceAllowlist(t *testing.T) {
+	allowlist := NewTraceAllowlist(time.Hour)
+	c, capture := newCaptureClient(t, WithTraceAllowlist(allowlist))
+
+	// webhook conversions get a registered trace
+	_, err := c.ConvertURL(context.Background(), "https://example.com").
+		WebhookURL("http://hook/ok", http.MethodPost).
+		WebhookErrorURL("http://hook/err", http.MethodPost).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	trace := capture.header.Get(HeaderGotenbergTrace)
+	if known, _ := allowlist.KnownTrace(context.Background(), trace); trace == "" || !known {
+		t.Fatalf("trace %q not registered", trace)
+	}
+
+	handler := RequireKnownTrace(allowlist, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	callback := func(trace string) int {
+		req := httptest.NewRequest(http.MethodPost, "/ok", nil)
+		req.Header.Set(HeaderGotenbergTrace, trace)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if code := callback(trace); code != http.StatusAccepted {
+		t.Errorf("expected known trace to be served, got %d", code)
+	}
+	if code := callback(trace); code != http.StatusNotFound {
+		t.Errorf("expected 404 for replayed callback, got %d", code)
+	}
+	if code := callback("forged"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown trace, got %d", code)
+	}
+
+	allowlist.Register(trace)
+	now := time.Now()
+	allowlist.now = func() time.Time { return now.Add(2 * time.Hour) }
+	if code := callback(trace); code != http.StatusNotFound {
+		t.Errorf("expected 404 for expired trace, got %d", code)
+	}
+}
+
+func TestTraceAllowlistKeepsFailedCallbacks(t *testing.T) {
+	allowlist := NewTraceAllowlist(time.Hour)
+	allowlist.Register("trace")
+	handler := RequireKnownTrace(allowlist, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "try again", http.StatusServiceUnavailable)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/ok", nil)
+	req.Header.Set(HeaderGotenbergTrace, "trace")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Gotenberg retries failed callbacks
+	if known, _ := allowlist.KnownTrace(context.Background(), "trace"); !known {
+		t.Error("trace forgotten after a failed callback")
+	}
+}
+
+func TestTraceAllowlistPrunes(t *testing.T) {
+	allowlist := NewTraceAllowlist(0)
+	if allowlist.ttl != DefaultTraceTTL {
+		t.Errorf("expected default ttl, got %v", allowlist.ttl)
+	}
+	now := time.Now()
+	allowlist.now = func() time.Time { return now }
+	for i := 0; i < 10; i++ {
+		allowlist.Register(fmt.Sprint(i))
+	}
+	now = now.Add(DefaultTraceTTL + time.Minute)
+	allowlist.Register("new")
+	if len(allowlist.traces) != 1 {
+		t.Errorf("expected expired traces to be pruned, got %d", len(allowlist.traces))
+	}
+}
+
+func TestJobTraces(t *testing.T) {
+	store := NewMemoryJobStore()
+	store.Save(context.Background(), Job{ID: "job", Status: JobPending})
+
+	if known, err := (JobTraces{Store: store}).KnownTrace(context.Background(), "job"); err != nil || !known {
+		t.Errorf("expected known job trace, got %v, %v", known, err)
+	}
+	if known, err := (JobTraces{Store: store}).KnownTrace(context.Background(), "other"); err != nil || known {
+		t.Errorf("expected unknown trace, got %v, %v", known, err)
+	}
+	store.Save(context.Background(), Job{ID: "done", Status: JobCompleted})
+	if known, err := (JobTraces{Store: store}).KnownTrace(context.Background(), "done"); err != nil || known {
+		t.Errorf("expected handled job to be rejected, got %v, %v", known, err)
+	}
+}
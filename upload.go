@@ -0,0 +1,276 @@
+package gotenberg
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EstimatedUploadSize returns the number of bytes the request will upload to Gotenberg,
+// computed from the form fields and the files whose size is known up front.
+// Sizes are known for readers exposing Len (bytes.Buffer, bytes.Reader, strings.Reader),
+// Stat (os.File) or Seek. exact is false when at least one file has an unknown size,
+// in which case size only accounts for the known sources.
+// Multipart framing overhead is not included.
+func (r *Request) EstimatedUploadSize() (size int64, exact bool) {
+	return r.upload.total, !r.upload.unknown
+}
+
+// uploadSize accumulates the estimated upload size of a request.
+type uploadSize struct {
+	total   int64
+	unknown bool
+}
+
+func (u *uploadSize) addField(key, value string) {
+	u.total += int64(len(key) + len(value))
+}
+
+func (u *uploadSize) addFile(key, filename string, content io.Reader) {
+	u.total += int64(len(key) + len(filename))
+	n, ok := readerSize(content)
+	if !ok {
+		u.unknown = true
+		return
+	}
+	u.total += n
+}
+
+// readerSize returns the number of bytes left to read from r, if it can be determined
+// without consuming it.
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case interface{ Stat() (fs.FileInfo, error) }:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		size := info.Size()
+		if s, ok := r.(io.Seeker); ok {
+			if offset, err := s.Seek(0, io.SeekCurrent); err == nil {
+				size -= offset
+			}
+		}
+		return size, true
+	case io.Seeker:
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := v.Seek(offset, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - offset, true
+	}
+	return 0, false
+}
+
+// Stager stores request files where Gotenberg can download them.
+type Stager interface {
+	// Stage stores content under filename and returns the URL Gotenberg downloads it from.
+	// size is -1 when unknown.
+	Stage(ctx context.Context, filename string, content io.Reader, size int64) (string, error)
+}
+
+// StageReleaser is implemented by Stagers that remove staged files once
+// Gotenberg fetched them. Files of synchronous conversions are released when
+// Send returns, whether the conversion succeeded or failed. Files of webhook
+// conversions, which Gotenberg may fetch later, are left in place; remove
+// them with a TempSweeper.
+type StageReleaser interface {
+	// Release removes the file staged at url.
+	Release(ctx context.Context, url string) error
+}
+
+// UploadStrategy switches requests from direct upload to Gotenberg's downloadFrom
+// when their estimated upload size exceeds Threshold. The files are then staged
+// with Stager and Gotenberg fetches them itself.
+// Requests of unknown size are staged when their known part exceeds Threshold.
+type UploadStrategy struct {
+	Threshold int64
+	Stager    Stager
+}
+
+// WithUploadStrategy enables automatic staging of large request files.
+func WithUploadStrategy(threshold int64, stager Stager) ClientOption {
+	return func(c *Client) {
+		c.stage = &UploadStrategy{Threshold: threshold, Stager: stager}
+	}
+}
+
+// formFile is a file part waiting to be attached to the multipart request.
+type formFile struct {
+	key      string
+	filename string
+	content  io.Reader
+}
+
+// downloadFrom is an entry of Gotenberg's downloadFrom form field.
+type downloadFrom struct {
+	URL              string            `json:"url"`
+	ExtraHTTPHeaders map[string]string `json:"extraHttpHeaders,omitempty"`
+}
+
+// DownloadFrom makes Gotenberg download a file from url instead of receiving it
+// in the request, sending headers with the download request.
+func (r *Request) DownloadFrom(url string, headers map[string]string) *Request {
+	r.downloads = append(r.downloads, downloadFrom{URL: url, ExtraHTTPHeaders: headers})
+	r.upload.total += int64(len(url))
+	return r
+}
+
+// attachFiles adds the request files to the multipart request,
+// either directly or through the client's upload strategy,
+// and sets the downloadFrom field.
+func (r *Request) attachFiles() error {
+	staged, err := r.stageFiles()
+	if err != nil {
+		return err
+	}
+
+	for i, f := range r.files {
+		if staged[i] {
+			continue
+		}
+		r.req.File(f.key, f.filename, r.inputs.reader(r.fileContent(f)))
+	}
+
+	if len(r.downloads) > 0 {
+		data, err := json.Marshal(r.downloads)
+		if err != nil {
+			return err
+		}
+		r.noteField(FieldDownloadFrom, string(data))
+		r.req.Param(FieldDownloadFrom, string(data))
+	}
+	return nil
+}
+
+// fileContent returns the content of a form file, with the HTML suffix
+// appended to index.html and the cover page composed before it.
+func (r *Request) fileContent(f formFile) io.Reader {
+	if !r.rewritesIndex(f) {
+		return f.content
+	}
+	content := f.content
+	if len(r.htmlSuffix) > 0 {
+		content = io.MultiReader(content, strings.NewReader(strings.Join(r.htmlSuffix, "\n")))
+	}
+	if r.coverHTML != "" {
+		content = ComposeHTML(strings.NewReader(r.coverHTML), content)
+	}
+	return content
+}
+
+// rewritesIndex reports whether f is an index.html changed by fileContent.
+func (r *Request) rewritesIndex(f formFile) bool {
+	return f.key == FieldFiles && f.filename == FileIndexHTML && (len(r.htmlSuffix) > 0 || r.coverHTML != "")
+}
+
+// stageFiles stages the form files when the client's upload strategy requires it
+// and adds them to the downloads. Returns the indexes of the staged files.
+func (r *Request) stageFiles() (map[int]bool, error) {
+	strategy := r.client.stage
+	if strategy == nil {
+		return nil, nil
+	}
+	if size, _ := r.EstimatedUploadSize(); size <= strategy.Threshold {
+		return nil, nil
+	}
+
+	staged := make(map[int]bool)
+	for i, f := range r.files {
+		if f.key != FieldFiles {
+			continue
+		}
+		size, ok := readerSize(f.content)
+		if !ok || r.rewritesIndex(f) {
+			size = -1
+		}
+		url, err := strategy.Stager.Stage(r.ctx, f.filename, r.inputs.reader(r.fileContent(f)), size)
+		if err != nil {
+			return nil, err
+		}
+		r.downloads = append(r.downloads, downloadFrom{URL: url})
+		staged[i] = true
+		if _, ok := strategy.Stager.(StageReleaser); ok {
+			r.staged = append(r.staged, url)
+		}
+	}
+	return staged, nil
+}
+
+// releaseStaged releases the files staged for a synchronous conversion.
+// Releasing is best effort: files left behind are removed by a TempSweeper.
+func (r *Request) releaseStaged() {
+	if len(r.staged) == 0 || r.webhookURL != "" {
+		return
+	}
+	releaser := r.client.stage.Stager.(StageReleaser)
+	ctx := context.WithoutCancel(r.ctx)
+	for _, url := range r.staged {
+		releaser.Release(ctx, url)
+	}
+}
+
+// MinioStager stages files in MinIO and hands out presigned download URLs.
+// Staged objects are stored under Prefix. They are removed once synchronous
+// conversions are done, see StageReleaser; run a TempSweeper on Prefix to
+// remove the objects of webhook conversions and of crashed processes.
+type MinioStager struct {
+	Client *MinioClient
+	Prefix string
+
+	// Expiry is the validity of the presigned URLs, one hour if zero.
+	Expiry time.Duration
+
+	// objects holds the staged object of every URL until it is released
+	objects sync.Map
+}
+
+// Stage implements Stager.
+func (s *MinioStager) Stage(ctx context.Context, filename string, content io.Reader, size int64) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	objectName := path.Join(s.Prefix, hex.EncodeToString(id), filename)
+	if _, err := s.Client.UploadFile(ctx, objectName, content, size, "application/octet-stream"); err != nil {
+		return "", err
+	}
+
+	expiry := s.Expiry
+	if expiry == 0 {
+		expiry = time.Hour
+	}
+	u, err := s.Client.PresignedURL(ctx, objectName, expiry, filename)
+	if err != nil {
+		s.Client.DeleteFile(ctx, objectName)
+		return "", err
+	}
+	s.objects.Store(u.String(), objectName)
+	return u.String(), nil
+}
+
+// Release implements StageReleaser.
+func (s *MinioStager) Release(ctx context.Context, url string) error {
+	objectName, ok := s.objects.LoadAndDelete(url)
+	if !ok {
+		return nil
+	}
+	return s.Client.DeleteFile(ctx, objectName.(string))
+}
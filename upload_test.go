@@ -0,0 +1,152 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEstimatedUploadSize(t *testing.T) {
+	c := newTestClient(t)
+	html := "<html></html>"
+	r := c.ConvertHTML(context.Background(), strings.NewReader(html)).
+		File(FieldFiles, "logo.png", bytes.NewReader(make([]byte, 100))).
+		Bool(FieldLandscape, true)
+
+	size, exact := r.EstimatedUploadSize()
+	if !exact {
+		t.Error("expected exact size")
+	}
+	want := int64(len(FieldFiles)+len(FileIndexHTML)+len(html)) +
+		int64(len(FieldFiles)+len("logo.png")+100) +
+		int64(len(FieldLandscape)+len("true"))
+	if size != want {
+		t.Errorf("expected %d, got %d", want, size)
+	}
+}
+
+func TestEstimatedUploadSizeUnknown(t *testing.T) {
+	c := newTestClient(t)
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	r := c.ConvertHTML(context.Background(), pr)
+	if _, exact := r.EstimatedUploadSize(); exact {
+		t.Error("expected inexact size for pipe")
+	}
+}
+
+func TestReaderSizeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.bin")
+	if err := os.WriteFile(path, make([]byte, 42), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.Seek(2, io.SeekStart)
+
+	if n, ok := readerSize(f); !ok || n != 40 {
+		t.Errorf("expected 40, got %d (%v)", n, ok)
+	}
+}
+
+type recordingStager struct {
+	staged map[string]string
+}
+
+func (s *recordingStager) Stage(ctx context.Context, filename string, content io.Reader, size int64) (string, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	s.staged[filename] = string(data)
+	return "http://storage/" + filename, nil
+}
+
+func TestUploadStrategyStagesLargeRequests(t *testing.T) {
+	stager := &recordingStager{staged: make(map[string]string)}
+	c, capture := newCaptureClient(t, WithUploadStrategy(64, stager))
+
+	big := strings.Repeat("x", 100)
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		File(FieldFiles, "big.css", strings.NewReader(big)).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if stager.staged["big.css"] != big || stager.staged[FileIndexHTML] != "<html></html>" {
+		t.Errorf("files not staged: %v", stager.staged)
+	}
+	if len(capture.files) != 0 {
+		t.Errorf("staged files were uploaded: %v", capture.files)
+	}
+	want := `[{"url":"http://storage/index.html"},{"url":"http://storage/big.css"}]`
+	if got := capture.value(FieldDownloadFrom); got != want {
+		t.Errorf("unexpected downloadFrom %s", got)
+	}
+}
+
+func TestUploadStrategyBelowThreshold(t *testing.T) {
+	stager := &recordingStager{staged: make(map[string]string)}
+	c, capture := newCaptureClient(t, WithUploadStrategy(1<<20, stager))
+
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(stager.staged) != 0 {
+		t.Errorf("unexpected staging: %v", stager.staged)
+	}
+	if capture.files[FileIndexHTML] != "<html></html>" {
+		t.Errorf("index.html not uploaded: %v", capture.files)
+	}
+}
+
+// releasingStager records the released files
+type releasingStager struct {
+	recordingStager
+	mu       sync.Mutex
+	released []string
+}
+
+func (s *releasingStager) Release(ctx context.Context, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = append(s.released, url)
+	return nil
+}
+
+func TestUploadStrategyReleasesStagedFiles(t *testing.T) {
+	send := func(transport http.RoundTripper, webhook bool) []string {
+		stager := &releasingStager{recordingStager: recordingStager{staged: make(map[string]string)}}
+		c, err := NewClient(&http.Client{Transport: transport}, "http://localhost", WithUploadStrategy(8, stager))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>"))
+		if webhook {
+			r.WebhookURL("http://hook/result", "POST").WebhookErrorURL("http://hook/error", "POST")
+		}
+		r.Send()
+		return stager.released
+	}
+
+	if released := send(&mockRoundTripper{}, false); len(released) != 1 || released[0] != "http://storage/index.html" {
+		t.Errorf("unexpected released files after success %v", released)
+	}
+	if released := send(failingRoundTripper{}, false); len(released) != 1 {
+		t.Errorf("unexpected released files after failure %v", released)
+	}
+	if released := send(&mockRoundTripper{}, true); len(released) != 0 {
+		t.Errorf("files of a webhook conversion were released: %v", released)
+	}
+}
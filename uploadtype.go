@@ -0,0 +1,143 @@
+package gotenberg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedType is returned for uploads whose content type is not
+// accepted, see WithUploadTypes. It is answered with 415.
+var ErrUnsupportedType = errors.New("gotenberg: unsupported content type")
+
+// DefaultUploadTypes are the media types accepted by the upload endpoint of
+// MinioAPI unless configured with WithUploadTypes: the inputs of Gotenberg's
+// routes, i.e. PDF, HTML, Markdown, images and office documents.
+var DefaultUploadTypes = []string{
+	"application/pdf",
+	"text/html",
+	"text/markdown",
+	"text/plain",
+	"text/css",
+	"image/*",
+	"font/*",
+	"application/rtf",
+	"application/msword",
+	"application/vnd.ms-excel",
+	"application/vnd.ms-powerpoint",
+	"application/vnd.openxmlformats-officedocument.*",
+	"application/vnd.oasis.opendocument.*",
+}
+
+// officeTypes are the types of office documents by extension. Their content
+// sniffs as a generic zip or binary, and mime.TypeByExtension depends on the
+// system tables.
+var officeTypes = map[string]string{
+	".doc":  "application/msword",
+	".xls":  "application/vnd.ms-excel",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".rtf":  "application/rtf",
+	".md":   "text/markdown",
+	".svg":  "image/svg+xml",
+}
+
+// executableMagic starts executables and scripts, rejected whatever their
+// name or declared type.
+var executableMagic = [][]byte{
+	[]byte("MZ"),             // Windows PE
+	[]byte("\x7fELF"),        // ELF
+	{0xfe, 0xed, 0xfa, 0xce}, // Mach-O
+	{0xfe, 0xed, 0xfa, 0xcf}, // Mach-O 64-bit
+	{0xce, 0xfa, 0xed, 0xfe}, // Mach-O, little endian
+	{0xcf, 0xfa, 0xed, 0xfe}, // Mach-O 64-bit, little endian
+	{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal
+	[]byte("#!"),             // scripts
+}
+
+// WithUploadTypes sets the media types accepted by the upload endpoint,
+// instead of DefaultUploadTypes. A type may end in "/*" or ".*" to accept a
+// family, e.g. "image/*"; no type accepts everything but executables, which
+// are always rejected.
+func WithUploadTypes(types ...string) MinioAPIOption {
+	return func(api *MinioAPI) {
+		api.uploadTypes = types
+	}
+}
+
+// detectUploadType returns the media type of the upload filename from the
+// first bytes of its content, falling back to its extension when the content
+// is too generic to tell, e.g. for office documents, which are zip files.
+func detectUploadType(filename string, head []byte) (string, error) {
+	for _, magic := range executableMagic {
+		if bytes.HasPrefix(head, magic) {
+			return "", fmt.Errorf("%w: %s is an executable", ErrUnsupportedType, filename)
+		}
+	}
+	sniffed := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(sniffed)
+	switch mediaType {
+	case "application/octet-stream", "application/zip", "text/plain", "text/xml":
+		ext := strings.ToLower(filepath.Ext(filename))
+		if byExt, ok := officeTypes[ext]; ok {
+			return byExt, nil
+		}
+		if byExt := mime.TypeByExtension(ext); byExt != "" && mediaType != "application/octet-stream" {
+			return byExt, nil
+		}
+	}
+	return sniffed, nil
+}
+
+// acceptedType reports whether contentType is one of types.
+func acceptedType(contentType string, types []string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, accepted := range types {
+		switch {
+		case strings.HasSuffix(accepted, "*"):
+			if strings.HasPrefix(mediaType, strings.TrimSuffix(accepted, "*")) {
+				return true
+			}
+		case mediaType == accepted:
+			return true
+		}
+	}
+	return false
+}
+
+// sniffUpload detects the media type of the upload filename, read from file,
+// which is rewound, and checks it against types.
+func sniffUpload(filename string, file io.ReadSeeker, types []string) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	contentType, err := detectUploadType(filename, head[:n])
+	if err != nil {
+		return "", err
+	}
+	if !acceptedType(contentType, types) {
+		return "", fmt.Errorf("%w: %s is %s", ErrUnsupportedType, filename, contentType)
+	}
+	return contentType, nil
+}
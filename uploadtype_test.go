@@ -0,0 +1,83 @@
+package gotenberg
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectUploadType(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+		want     string
+	}{
+		{"index.html", "<!DOCTYPE html><html><body>Hi</body></html>", "text/html; charset=utf-8"},
+		{"report.pdf", "%PDF-1.7\n", "application/pdf"},
+		{"letter.docx", "PK\x03\x04\x14\x00\x06\x00", "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"notes.md", "# Notes\n", "text/markdown"},
+		{"logo.png", "\x89PNG\r\n\x1a\n", "image/png"},
+	}
+	for _, tt := range tests {
+		got, err := detectUploadType(tt.filename, []byte(tt.content))
+		if err != nil || got != tt.want {
+			t.Errorf("detectUploadType(%q) = %q, %v, want %q", tt.filename, got, err, tt.want)
+		}
+	}
+
+	for _, content := range []string{"MZ\x90\x00", "\x7fELF\x02\x01", "#!/bin/sh\nrm -rf /\n"} {
+		if _, err := detectUploadType("invoice.pdf", []byte(content)); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("detectUploadType(%q) error = %v, want ErrUnsupportedType", content, err)
+		}
+	}
+}
+
+func TestAcceptedType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		types       []string
+		want        bool
+	}{
+		{"image/png", DefaultUploadTypes, true},
+		{"text/html; charset=utf-8", DefaultUploadTypes, true},
+		{"application/vnd.oasis.opendocument.text", DefaultUploadTypes, true},
+		{"application/zip", DefaultUploadTypes, false},
+		{"application/octet-stream", DefaultUploadTypes, false},
+		{"image/png", []string{"application/pdf"}, false},
+		{"application/zip", nil, true},
+	}
+	for _, tt := range tests {
+		if got := acceptedType(tt.contentType, tt.types); got != tt.want {
+			t.Errorf("acceptedType(%q, %v) = %v, want %v", tt.contentType, tt.types, got, tt.want)
+		}
+	}
+}
+
+func TestHandleUploadRejectsType(t *testing.T) {
+	upload := func(api *MinioAPI, filename, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", filename)
+		part.Write([]byte(content))
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/api/upload", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		api.HandleUpload(rec, req)
+		return rec
+	}
+
+	rec := upload(NewMinioAPI(nil), "setup.pdf", "MZ\x90\x00\x03\x00\x00\x00")
+	if rec.Code != http.StatusUnsupportedMediaType || !strings.Contains(rec.Body.String(), `"unsupported_media_type"`) {
+		t.Errorf("executable upload = %d %s, want 415", rec.Code, rec.Body.String())
+	}
+
+	rec = upload(NewMinioAPI(nil, WithUploadTypes("application/pdf")), "page.html", "<html><body>Hi</body></html>")
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("HTML upload with PDF only = %d, want 415", rec.Code)
+	}
+}
@@ -0,0 +1,235 @@
+package gotenberg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrInputRejected is returned by Send when an InputValidator rejects the
+// inputs of a conversion.
+var ErrInputRejected = errors.New("gotenberg: conversion input rejected")
+
+// InputValidator checks the inputs of a conversion before it is sent, see
+// WithInputValidators and Request.ValidateInputs. Validators run in order,
+// the first error fails Send.
+type InputValidator interface {
+	// ValidateInputs returns an error, wrapping ErrInputRejected, for inputs
+	// that must not be converted.
+	ValidateInputs(inputs *ConversionInputs) error
+}
+
+// InputValidatorFunc is an InputValidator of a function.
+type InputValidatorFunc func(inputs *ConversionInputs) error
+
+// ValidateInputs implements InputValidator.
+func (f InputValidatorFunc) ValidateInputs(inputs *ConversionInputs) error {
+	return f(inputs)
+}
+
+// ConversionInputs are the inputs of a conversion, as seen by an
+// InputValidator.
+type ConversionInputs struct {
+	Route string
+	// Files are the files attached to the request, in order.
+	Files []*InputFile
+	// Downloads are the URLs Gotenberg downloads files from, see DownloadFrom.
+	Downloads []string
+}
+
+// InputFile is a file attached to a conversion.
+type InputFile struct {
+	// Field is the form field of the file, FieldFiles for documents and assets.
+	Field string
+	Name  string
+
+	read    func() ([]byte, error)
+	size    int64
+	content []byte
+}
+
+// Size returns the size of the file, reading it if the size of its reader
+// cannot be told otherwise.
+func (f *InputFile) Size() (int64, error) {
+	if f.size >= 0 {
+		return f.size, nil
+	}
+	content, err := f.Content()
+	return int64(len(content)), err
+}
+
+// Content returns the content of the file. The file is buffered in memory, to
+// be sent once validated.
+func (f *InputFile) Content() ([]byte, error) {
+	if f.content == nil {
+		content, err := f.read()
+		if err != nil {
+			return nil, err
+		}
+		f.content, f.size = content, int64(len(content))
+	}
+	return f.content, nil
+}
+
+// WithInputValidators sets the validators checking the inputs of every
+// conversion of the client, before those of Request.ValidateInputs.
+func WithInputValidators(validators ...InputValidator) ClientOption {
+	return func(c *Client) {
+		c.validators = validators
+	}
+}
+
+// ValidateInputs adds validators checking the inputs of the request when it
+// is sent, after those of WithInputValidators.
+func (r *Request) ValidateInputs(validators ...InputValidator) *Request {
+	r.validators = append(r.validators, validators...)
+	return r
+}
+
+// validateInputs runs the validators of the client and of the request.
+func (r *Request) validateInputs() error {
+	validators := append(append([]InputValidator(nil), r.client.validators...), r.validators...)
+	if len(validators) == 0 {
+		return nil
+	}
+
+	inputs := &ConversionInputs{Route: r.route}
+	for i, f := range r.files {
+		i := i
+		size := int64(-1)
+		if !r.rewritesIndex(f) {
+			if n, ok := readerSize(f.content); ok {
+				size = n
+			}
+		}
+		inputs.Files = append(inputs.Files, &InputFile{
+			Field: f.key,
+			Name:  f.filename,
+			size:  size,
+			read:  func() ([]byte, error) { return r.bufferFile(i) },
+		})
+	}
+	for _, d := range r.downloads {
+		inputs.Downloads = append(inputs.Downloads, d.URL)
+	}
+
+	for _, v := range validators {
+		if err := v.ValidateInputs(inputs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bufferFile reads the file i of the request into memory, as it will be sent,
+// and replaces its reader with the buffer.
+func (r *Request) bufferFile(i int) ([]byte, error) {
+	f := r.files[i]
+	data, err := io.ReadAll(r.fileContent(f))
+	if err != nil {
+		return nil, err
+	}
+	if r.rewritesIndex(f) {
+		// The suffix and cover page are now part of the buffered content
+		r.htmlSuffix, r.coverHTML = nil, ""
+	}
+	r.files[i].content = bytes.NewReader(data)
+	return data, nil
+}
+
+// MaxInputFiles rejects conversions with more than n attached files.
+func MaxInputFiles(n int) InputValidator {
+	return InputValidatorFunc(func(inputs *ConversionInputs) error {
+		if len(inputs.Files) > n {
+			return fmt.Errorf("%w: %d files attached, at most %d allowed", ErrInputRejected, len(inputs.Files), n)
+		}
+		return nil
+	})
+}
+
+// MaxInputSize rejects conversions whose attached files total more than
+// maxBytes. Files of unknown size are buffered to be measured.
+func MaxInputSize(maxBytes int64) InputValidator {
+	return InputValidatorFunc(func(inputs *ConversionInputs) error {
+		var total int64
+		for _, f := range inputs.Files {
+			size, err := f.Size()
+			if err != nil {
+				return err
+			}
+			total += size
+			if total > maxBytes {
+				return fmt.Errorf("%w: files exceed %d bytes", ErrInputRejected, maxBytes)
+			}
+		}
+		return nil
+	})
+}
+
+// externalReference matches the remote URLs of the attributes loading
+// resources, stylesheet links, CSS url() and @import rules. Hyperlinks are
+// not loaded while rendering and are left alone.
+var externalReference = regexp.MustCompile(`(?i)(?:\b(?:src|srcset|poster|data|xlink:href)\s*=\s*["']?\s*|<link\b[^>]*?\bhref\s*=\s*["']?\s*|url\(\s*["']?\s*|@import\s+["']?)((?:[a-z][a-z0-9+.-]*:)?//[^\s"'<>)]*)`)
+
+// NoExternalReferences rejects conversions whose HTML and CSS files reference
+// remote resources, e.g. scripts, images or stylesheets served over http(s),
+// so documents render from their attachments only and cannot make Chromium
+// fetch arbitrary URLs. Hyperlinks are allowed. Requests downloading files
+// with DownloadFrom are rejected too.
+func NoExternalReferences() InputValidator {
+	return InputValidatorFunc(func(inputs *ConversionInputs) error {
+		if len(inputs.Downloads) > 0 {
+			return fmt.Errorf("%w: downloads from %s", ErrInputRejected, strings.Join(inputs.Downloads, ", "))
+		}
+		for _, f := range inputs.Files {
+			switch strings.ToLower(path.Ext(f.Name)) {
+			case ".html", ".htm", ".css", ".svg":
+			default:
+				continue
+			}
+			content, err := f.Content()
+			if err != nil {
+				return err
+			}
+			if m := externalReference.FindSubmatch(content); m != nil {
+				return fmt.Errorf("%w: %s references %s", ErrInputRejected, f.Name, m[1])
+			}
+		}
+		return nil
+	})
+}
+
+// InputLimits are the input validators of a Profile, applied to the specs
+// referencing it:
+//
+//	"inputs": {"maxFiles": 20, "maxSize": 10485760, "noExternalReferences": true}
+type InputLimits struct {
+	// MaxFiles is the maximum number of attached files, zero for no limit.
+	MaxFiles int `json:"maxFiles,omitempty"`
+	// MaxSize is the maximum total size of attached files, zero for no limit.
+	MaxSize int64 `json:"maxSize,omitempty"`
+	// NoExternalReferences rejects documents referencing remote resources.
+	NoExternalReferences bool `json:"noExternalReferences,omitempty"`
+}
+
+// ValidateInputs implements InputValidator.
+func (l InputLimits) ValidateInputs(inputs *ConversionInputs) error {
+	if l.MaxFiles > 0 {
+		if err := MaxInputFiles(l.MaxFiles).ValidateInputs(inputs); err != nil {
+			return err
+		}
+	}
+	if l.MaxSize > 0 {
+		if err := MaxInputSize(l.MaxSize).ValidateInputs(inputs); err != nil {
+			return err
+		}
+	}
+	if l.NoExternalReferences {
+		return NoExternalReferences().ValidateInputs(inputs)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInputValidators(t *testing.T) {
+	ctx := context.Background()
+	html := func(markup string) *strings.Reader {
+		return strings.NewReader("<html><body>" + markup + "</body></html>")
+	}
+
+	tests := []struct {
+		name      string
+		validator InputValidator
+		req       func(c *Client) *Request
+		rejected  bool
+	}{
+		{"files within limit", MaxInputFiles(2), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html("")).File(FieldFiles, "a.css", strings.NewReader("body{}"))
+		}, false},
+		{"too many files", MaxInputFiles(1), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html("")).File(FieldFiles, "a.css", strings.NewReader("body{}"))
+		}, true},
+		{"too large", MaxInputSize(32), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html(strings.Repeat("x", 32)))
+		}, true},
+		{"remote script", NoExternalReferences(), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html(`<script src="https://cdn.example.com/app.js"></script>`))
+		}, true},
+		{"remote stylesheet import", NoExternalReferences(), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html("")).File(FieldFiles, "a.css", strings.NewReader(`@import "//fonts.example.com/css";`))
+		}, true},
+		{"downloads", NoExternalReferences(), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html("")).DownloadFrom("https://files.example.com/logo.png", nil)
+		}, true},
+		{"links and local assets", NoExternalReferences(), func(c *Client) *Request {
+			return c.ConvertHTML(ctx, html(`<a href="https://example.com">site</a><img src="logo.png">`))
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCaptureClient(t)
+			_, err := tt.req(c).ValidateInputs(tt.validator).Send()
+			if got := errors.Is(err, ErrInputRejected); got != tt.rejected {
+				t.Errorf("Send error = %v, rejected %v", err, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestInputValidatorsSendBufferedFiles(t *testing.T) {
+	c, capture := newCaptureClient(t, WithInputValidators(NoExternalReferences()))
+	_, err := c.ConvertHTML(context.Background(), strings.NewReader("<html></html>")).
+		WithPrintCSS("body{}").
+		ValidateInputs(MaxInputSize(1 << 20)).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if index := capture.files[FileIndexHTML]; strings.Count(index, FilePrintCSS) != 1 {
+		t.Errorf("index.html = %q, want the print stylesheet linked once", index)
+	}
+	if capture.files[FilePrintCSS] != "body{}" {
+		t.Errorf("print.css = %q", capture.files[FilePrintCSS])
+	}
+}
+
+func TestProfileInputLimits(t *testing.T) {
+	profiles, err := LoadProfiles(strings.NewReader(`{"public": {"route": "/forms/chromium/convert/html", "inputs": {"maxFiles": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := newCaptureClient(t, WithProfiles(profiles))
+	spec := ConversionSpec{
+		Profile: "public",
+		Files: []FileRef{
+			{Filename: FileIndexHTML, Data: []byte("<html></html>")},
+			{Filename: "a.css", Data: []byte("body{}")},
+		},
+	}
+	if _, err := c.FromSpec(context.Background(), spec).Send(); !errors.Is(err, ErrInputRejected) {
+		t.Errorf("Send error = %v, want ErrInputRejected", err)
+	}
+}
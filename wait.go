@@ -0,0 +1,128 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultWaitPollInterval is the interval at which ConvertAndWait checks the
+// status of its job, see WithWaitPollInterval.
+const DefaultWaitPollInterval = 250 * time.Millisecond
+
+// submitShare is the share of the context deadline ConvertAndWait gives the
+// submission, the rest is left to the conversion and the webhook callback.
+const submitShare = 3
+
+// Phases of ConvertAndWait, see PhaseTimeoutError.
+const (
+	// PhaseSubmit is the submission of the job to Gotenberg.
+	PhaseSubmit = "submit"
+	// PhaseWait is the wait for the webhook callback of the job.
+	PhaseWait = "wait"
+)
+
+// PhaseTimeoutError is returned by ConvertAndWait when a phase runs out of
+// its share of the context deadline. It wraps context.DeadlineExceeded.
+type PhaseTimeoutError struct {
+	Phase string
+	JobID string
+	// Budget is the time the phase was given.
+	Budget time.Duration
+	Err    error
+}
+
+func (e *PhaseTimeoutError) Error() string {
+	return fmt.Sprintf("gotenberg: job %s timed out in phase %s after %v: %v", e.JobID, e.Phase, e.Budget.Round(time.Millisecond), e.Err)
+}
+
+// Unwrap allows errors.Is(err, context.DeadlineExceeded).
+func (e *PhaseTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WithWaitPollInterval sets the interval at which ConvertAndWait checks the
+// status of its job in the JobStore, DefaultWaitPollInterval by default.
+func WithWaitPollInterval(interval time.Duration) OutboxOption {
+	return func(o *Outbox) {
+		o.pollInterval = interval
+	}
+}
+
+// ConvertAndWait submits spec like Submit and waits until its webhook
+// callback was handled by ResultHandler or ErrorHandler, which may run in
+// another instance sharing the JobStore. It returns the Result recorded for
+// the completed job, with the job ID as Trace and the time from submission to
+// completion as Duration, and an error for failed and cancelled jobs.
+//
+// With a context deadline, the submission gets a third of the time left and
+// the wait the rest; a phase running out of time fails with a
+// *PhaseTimeoutError naming it, and leaves the job pending.
+func (o *Outbox) ConvertAndWait(ctx context.Context, spec ConversionSpec) (Result, error) {
+	start := time.Now()
+	submitCtx := ctx
+	deadline, hasDeadline := ctx.Deadline()
+	var budget time.Duration
+	if hasDeadline {
+		budget = time.Until(deadline) / submitShare
+		var cancel context.CancelFunc
+		submitCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+	job, err := o.Submit(submitCtx, spec)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = &PhaseTimeoutError{Phase: PhaseSubmit, JobID: job.ID, Budget: budget, Err: err}
+		}
+		return Result{Trace: job.ID}, err
+	}
+	if hasDeadline {
+		budget = time.Until(deadline)
+	}
+	job, err = o.wait(ctx, job, budget)
+	result := Result{Trace: job.ID}
+	if job.Result != nil {
+		result = *job.Result
+	}
+	result.Duration = time.Since(start)
+	return result, err
+}
+
+// wait polls the store until job is no longer pending, within budget if the
+// context has a deadline.
+func (o *Outbox) wait(ctx context.Context, job Job, budget time.Duration) (Job, error) {
+	interval := o.pollInterval
+	if interval <= 0 {
+		interval = DefaultWaitPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return job, &PhaseTimeoutError{Phase: PhaseWait, JobID: job.ID, Budget: budget, Err: ctx.Err()}
+			}
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := o.store.Get(ctx, job.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return job, err
+		}
+		current.outbox = o
+		switch current.Status {
+		case JobPending:
+			job = current
+		case JobCompleted:
+			return current, nil
+		default:
+			return current, fmt.Errorf("gotenberg: job %s %s: %s", current.ID, current.Status, current.Error)
+		}
+	}
+}
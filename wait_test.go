@@ -0,0 +1,94 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertAndWait(t *testing.T) {
+	store := NewMemoryJobStore()
+	outbox := NewOutbox(newTestClient(t), store, testWebhook, WithWaitPollInterval(5*time.Millisecond))
+	storage := newMemoryStorage()
+	handler := outbox.ResultHandler(SaveToStorage(storage, "{job}.pdf"))
+
+	// deliver the callback of the job once it was submitted
+	go func() {
+		for {
+			pending, _ := outbox.Pending(context.Background())
+			if len(pending) == 1 && pending[0].Attempts == 1 {
+				req := httptest.NewRequest(http.MethodPost, "/result", strings.NewReader("pdf"))
+				req.Header.Set(HeaderGotenbergTrace, pending[0].ID)
+				req.Header.Set("Content-Type", "application/pdf")
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := outbox.ConvertAndWait(ctx, testSpec())
+	if err != nil {
+		t.Fatalf("ConvertAndWait failed: %v", err)
+	}
+	if result.Trace == "" || result.StorageKey != result.Trace+".pdf" || result.Size != 3 || result.ContentType != "application/pdf" || result.Duration <= 0 {
+		t.Errorf("unexpected result %+v", result)
+	}
+	job, err := outbox.store.Get(context.Background(), result.Trace)
+	if err != nil || job.Status != JobCompleted {
+		t.Errorf("expected completed job, got %+v, %v", job, err)
+	}
+}
+
+func TestConvertAndWaitTimeouts(t *testing.T) {
+	stop := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stop
+	}))
+	defer slow.Close()
+	defer close(stop)
+	slowClient, err := NewClient(slow.Client(), slow.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		client *Client
+		phase  string
+	}{
+		{"submission", slowClient, PhaseSubmit},
+		{"webhook", newTestClient(t), PhaseWait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := NewOutbox(tt.client, NewMemoryJobStore(), testWebhook, WithWaitPollInterval(5*time.Millisecond))
+			ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			result, err := outbox.ConvertAndWait(ctx, testSpec())
+			var timeout *PhaseTimeoutError
+			if !errors.As(err, &timeout) || timeout.Phase != tt.phase || !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("ConvertAndWait error = %v, want a %s timeout", err, tt.phase)
+			}
+			if job, err := outbox.store.Get(context.Background(), result.Trace); timeout.JobID != result.Trace || err != nil || job.Status != JobPending {
+				t.Errorf("unexpected job %+v for %v", job, err)
+			}
+			// the submission gives up early, leaving time for the wait
+			if tt.phase == PhaseSubmit && time.Since(start) > 100*time.Millisecond {
+				t.Errorf("submission took %v of a 150ms deadline", time.Since(start))
+			}
+			// the budget is the share of the deadline the phase was given
+			if max := 150 * time.Millisecond; timeout.Budget <= 0 || timeout.Budget > max || tt.phase == PhaseSubmit && timeout.Budget > max/submitShare {
+				t.Errorf("unexpected %s budget %v", tt.phase, timeout.Budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultWebhookMemoryThreshold is the size up to which webhook documents
+// are buffered in memory before they are spooled to a temporary file.
+const DefaultWebhookMemoryThreshold = 8 << 20
+
+// WebhookResult is a document posted by Gotenberg to the webhook URL.
+type WebhookResult struct {
+	Trace       string
+	ContentType string
+	// Filename is taken from the Content-Disposition header, if any.
+	Filename string
+	Size     int64
+	// Checksum is the hex encoded SHA-256 of the document.
+	Checksum string
+	// Body is buffered in memory or in a temporary file and can be rewound.
+	Body io.ReadSeeker
+	// StorageKey is where the ResultConsumer saved the document, if it did.
+	// SaveToStorage and SaveToDir set it; it is recorded in Job.Result.
+	StorageKey string
+}
+
+// ResultConsumer handles the document of a job posted to the webhook URL,
+// see Outbox.ResultHandler.
+type ResultConsumer func(ctx context.Context, job Job, result *WebhookResult) error
+
+// DefaultResultKey is the KeyTemplate used when none is configured.
+const DefaultResultKey KeyTemplate = "{date}/{tenant}/{trace}{ext}"
+
+// KeyTemplate is the object key or file path webhook documents are saved
+// under, e.g. "{date}/{tenant}/{trace}.pdf". Placeholders are:
+//
+//	{date}      creation date of the job, YYYY-MM-DD
+//	{year}, {month}, {day}
+//	{tenant}    tenant of the job spec
+//	{trace}     Gotenberg trace, the job ID for Outbox jobs
+//	{job}       job ID
+//	{filename}  filename of the document
+//	{ext}       extension of the document including the dot, e.g. ".pdf"
+//	{sha256}    SHA-256 of the document, see ContentKey
+//
+// Empty path segments are removed and values cannot add path segments.
+type KeyTemplate string
+
+// Expand returns the key of result.
+func (t KeyTemplate) Expand(job Job, result *WebhookResult) string {
+	if t == "" {
+		t = DefaultResultKey
+	}
+	created := job.CreatedAt.UTC()
+	r := strings.NewReplacer(
+		"{date}", created.Format("2006-01-02"),
+		"{year}", created.Format("2006"),
+		"{month}", created.Format("01"),
+		"{day}", created.Format("02"),
+		"{tenant}", keySegment(job.Spec.Tenant),
+		"{trace}", keySegment(result.Trace),
+		"{job}", keySegment(job.ID),
+		"{filename}", keySegment(result.Filename),
+		"{ext}", keySegment(resultExt(result)),
+		"{sha256}", keySegment(result.Checksum),
+	)
+	return strings.TrimPrefix(path.Clean("/"+r.Replace(string(t))), "/")
+}
+
+// keySegment keeps a placeholder value within its path segment.
+func keySegment(value string) string {
+	value = strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+	if value == "." || value == ".." {
+		return "_"
+	}
+	return value
+}
+
+// resultExt returns the extension of the document.
+func resultExt(result *WebhookResult) string {
+	return documentExt(result.Filename, result.ContentType)
+}
+
+// documentExt returns the extension of filename, or else of contentType.
+func documentExt(filename, contentType string) string {
+	if ext := path.Ext(filename); ext != "" {
+		return ext
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/pdf":
+		return ".pdf"
+	case "application/zip":
+		return ".zip"
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	}
+	return ""
+}
+
+// SaveToDir returns a ResultConsumer writing documents to files below dir,
+// named by key. Files are written atomically.
+func SaveToDir(dir string, key KeyTemplate) ResultConsumer {
+	return func(ctx context.Context, job Job, result *WebhookResult) error {
+		name := filepath.Join(dir, filepath.FromSlash(key.Expand(job, result)))
+		result.StorageKey = name
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(name), ".result-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		if _, err := io.Copy(tmp, result.Body); err != nil {
+			tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), name)
+	}
+}
+
+// SaveToStorage returns a ResultConsumer uploading documents to storage, named by key.
+// Uploads are labelled with the tenant of the job, see ContextWithTenant.
+func SaveToStorage(storage Storage, key KeyTemplate) ResultConsumer {
+	return func(ctx context.Context, job Job, result *WebhookResult) error {
+		result.StorageKey = key.Expand(job, result)
+		_, err := storage.UploadFile(ContextWithTenant(ctx, job.Spec.Tenant), result.StorageKey, result.Body, result.Size, result.ContentType)
+		return err
+	}
+}
+
+// newWebhookResult describes the document posted in r with its spooled body.
+func newWebhookResult(r *http.Request, body *spooledBody) *WebhookResult {
+	result := &WebhookResult{
+		Trace:       r.Header.Get(HeaderGotenbergTrace),
+		ContentType: r.Header.Get("Content-Type"),
+		Size:        body.size,
+		Checksum:    body.checksum,
+		Body:        body,
+	}
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+		result.Filename = params["filename"]
+	}
+	return result
+}
+
+// describe returns the Result recorded for the completed job.
+func (r *WebhookResult) describe(job Job) *Result {
+	trace := r.Trace
+	if trace == "" {
+		trace = job.ID
+	}
+	return &Result{
+		Trace:       trace,
+		Filename:    r.Filename,
+		ContentType: r.ContentType,
+		Size:        r.Size,
+		Checksum:    r.Checksum,
+		StorageKey:  r.StorageKey,
+	}
+}
+
+// spooledBody is a request body read completely, either into memory or,
+// above the memory threshold, into a temporary file removed by Close.
+type spooledBody struct {
+	io.ReadSeeker
+	size int64
+	file *os.File
+	// checksum is the hex encoded SHA-256 of the body
+	checksum string
+
+	// release updates the client Stats once the body is closed
+	release func()
+}
+
+// spoolBody reads body, keeping up to threshold bytes in memory.
+func spoolBody(body io.Reader, threshold int64) (*spooledBody, error) {
+	h := sha256.New()
+	body = io.TeeReader(body, h)
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, body, threshold+1)
+	if errors.Is(err, io.EOF) {
+		return &spooledBody{ReadSeeker: bytes.NewReader(buf.Bytes()), size: n, checksum: hex.EncodeToString(h.Sum(nil))}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "gotenberg-webhook-*")
+	if err != nil {
+		return nil, err
+	}
+	s := &spooledBody{ReadSeeker: file, file: file}
+	if s.size, err = io.Copy(file, io.MultiReader(&buf, body)); err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	s.checksum = hex.EncodeToString(h.Sum(nil))
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close removes the temporary file, if any.
+func (s *spooledBody) Close() error {
+	if s.release != nil {
+		s.release()
+		s.release = nil
+	}
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
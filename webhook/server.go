@@ -0,0 +1,83 @@
+// Package webhook runs the HTTP listener receiving Gotenberg webhook callbacks.
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/nativebpm/gotenberg-client/server"
+)
+
+// DefaultShutdownTimeout is the time in-flight callbacks get to complete on shutdown.
+const DefaultShutdownTimeout = server.DefaultShutdownTimeout
+
+// DefaultHealthPath is the path of the health endpoint.
+const DefaultHealthPath = server.DefaultHealthPath
+
+// Server is the callback listener for Gotenberg webhooks, e.g. serving the
+// handlers of a gotenberg.Outbox. It answers health checks and shuts down
+// gracefully, letting in-flight callbacks complete. It is a server.Server
+// without drain: callbacks are served until shutdown.
+type Server struct {
+	// Addr is the TCP address to listen on, ":http" or ":https" if empty.
+	Addr string
+
+	// Handler serves the callbacks.
+	Handler http.Handler
+
+	// TLSConfig enables HTTPS. It must provide the certificate through
+	// Certificates or GetCertificate.
+	TLSConfig *tls.Config
+
+	// ShutdownTimeout is DefaultShutdownTimeout if zero.
+	ShutdownTimeout time.Duration
+
+	// HealthPath is answered with 200 OK, DefaultHealthPath if empty.
+	HealthPath string
+}
+
+// Run serves until the process receives SIGINT or SIGTERM.
+func (s *Server) Run() error {
+	return s.server().Run()
+}
+
+// ListenAndServe listens on Addr and serves until ctx is done.
+// It returns nil after a graceful shutdown.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	return s.server().ListenAndServe(ctx)
+}
+
+// Serve serves on ln until ctx is done. It returns nil after a graceful shutdown.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	return s.server().Serve(ctx, ln)
+}
+
+func (s *Server) server() *server.Server {
+	return &server.Server{
+		Addr:            s.Addr,
+		Handler:         s.handler(),
+		TLSConfig:       s.TLSConfig,
+		ShutdownTimeout: s.ShutdownTimeout,
+	}
+}
+
+func (s *Server) handler() http.Handler {
+	health := s.HealthPath
+	if health == "" {
+		health = DefaultHealthPath
+	}
+	handler := s.Handler
+	if handler == nil {
+		handler = http.NotFoundHandler()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(health, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/", handler)
+	return mux
+}
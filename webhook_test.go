@@ -0,0 +1,69 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKeyTemplateExpand(t *testing.T) {
+	job := Job{
+		ID:        "job1",
+		CreatedAt: time.Date(2026, 3, 7, 23, 0, 0, 0, time.UTC),
+		Spec:      ConversionSpec{Tenant: "acme"},
+	}
+	result := &WebhookResult{Trace: "job1", ContentType: "application/pdf"}
+
+	tests := []struct {
+		key  KeyTemplate
+		want string
+	}{
+		{"", "2026-03-07/acme/job1.pdf"},
+		{"{year}/{month}/{day}/{job}-{filename}", "2026/03/07/job1-"},
+		{"{tenant}/{trace}{ext}", "acme/job1.pdf"},
+	}
+	for _, tt := range tests {
+		if got := tt.key.Expand(job, result); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.key, tt.want, got)
+		}
+	}
+
+	// values never add path segments
+	job.Spec.Tenant = ".."
+	result.Filename = "../../etc/passwd"
+	if got := KeyTemplate("{tenant}/{filename}").Expand(job, result); got != "_/.._.._etc_passwd" {
+		t.Errorf("unexpected key %q", got)
+	}
+	job.Spec.Tenant = ""
+	if got := DefaultResultKey.Expand(job, result); got != "2026-03-07/job1.pdf" {
+		t.Errorf("unexpected key %q", got)
+	}
+}
+
+func TestSaveToDirAndStorage(t *testing.T) {
+	ctx := context.Background()
+	job := Job{ID: "job1", CreatedAt: time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)}
+	newResult := func() *WebhookResult {
+		return &WebhookResult{Trace: "job1", Filename: "invoice.pdf", Size: 3, Body: bytes.NewReader([]byte("pdf"))}
+	}
+
+	dir := t.TempDir()
+	if err := SaveToDir(dir, "{date}/{filename}")(ctx, job, newResult()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "2026-03-07", "invoice.pdf"))
+	if err != nil || string(data) != "pdf" {
+		t.Errorf("unexpected file %q: %v", data, err)
+	}
+
+	storage := newMemoryStorage()
+	if err := SaveToStorage(storage, "")(ctx, job, newResult()); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(storage.files["2026-03-07/job1.pdf"]); got != "pdf" {
+		t.Errorf("unexpected stored objects %v", storage.files)
+	}
+}